@@ -8,12 +8,16 @@ import (
 	"time"
 
 	captchaadp "github.com/Veysel440/finance-master-api/internal/adapters/captcha"
+	"github.com/Veysel440/finance-master-api/internal/adapters/filestore"
+	"github.com/Veysel440/finance-master-api/internal/adapters/mailer"
 	mysqladp "github.com/Veysel440/finance-master-api/internal/adapters/mysql"
+	pricesadp "github.com/Veysel440/finance-master-api/internal/adapters/prices"
 	ratesadp "github.com/Veysel440/finance-master-api/internal/adapters/rates"
 	"github.com/Veysel440/finance-master-api/internal/config"
 	"github.com/Veysel440/finance-master-api/internal/cron"
 	apihttp "github.com/Veysel440/finance-master-api/internal/http"
 	"github.com/Veysel440/finance-master-api/internal/obs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
 	"github.com/Veysel440/finance-master-api/internal/services"
 )
 
@@ -39,13 +43,38 @@ func main() {
 	txRepo := mysqladp.NewTxRepo(db)
 	walletRepo := mysqladp.NewWalletRepo(db)
 	catRepo := mysqladp.NewCategoryRepo(db)
+	instRepo := mysqladp.NewInstallmentRepo(db)
+	contactRepo := mysqladp.NewContactRepo(db)
+	debtRepo := mysqladp.NewDebtRepo(db)
+	houseRepo := mysqladp.NewHouseholdRepo(db)
+	groupRepo := mysqladp.NewGroupRepo(db)
+	investRepo := mysqladp.NewInvestmentRepo(db)
+	worthRepo := mysqladp.NewNetWorthRepo(db)
+	reconRepo := mysqladp.NewReconcileRepo(db)
+	viewRepo := mysqladp.NewViewRepo(db)
+	subRepo := mysqladp.NewSubscriptionRepo(db)
+	insightRepo := mysqladp.NewInsightRepo(db)
+	reportRepo := mysqladp.NewReportRepo(db)
+	onboardRepo := mysqladp.NewOnboardRepo(db)
+	exportRepo := mysqladp.NewExportRepo(db)
+	accountRepo := mysqladp.NewAccountRepo(db)
+	importRepo := mysqladp.NewImportRepo(db)
 	auditRepo := mysqladp.NewAuditRepo(db)
 	sessionRepo := mysqladp.NewSessionRepo(db)
 	idemRepo := mysqladp.NewIdempotencyRepo(db.DB) // *sql.DB
 	ratesStore := mysqladp.NewRatesStore(db.DB)    // *sql.DB
 
 	auditSvc := &services.AuditService{Repo: auditRepo}
-	onboard := &services.OnboardService{Wallet: walletRepo, Cat: catRepo}
+	onboard := &services.OnboardService{Repo: onboardRepo, Audit: auditSvc}
+	exportStore := &filestore.Local{Dir: cfg.ExportDir}
+	accountSvc := &services.AccountService{
+		Repo:    accountRepo,
+		Auth:    authRepo,
+		Exports: exportRepo,
+		Store:   exportStore,
+		Audit:   auditSvc,
+		Grace:   cfg.DeletionGrace,
+	}
 
 	var capVerifier services.CaptchaVerifier
 	switch strings.ToLower(cfg.CaptchaProvider) {
@@ -55,6 +84,26 @@ func main() {
 		capVerifier = &captchaadp.ReCaptcha{Secret: cfg.RecaptchaSecret}
 	}
 
+	var mail ports.Mailer = mailer.Log{}
+	switch cfg.MailDriver {
+	case "smtp":
+		mail = &mailer.SMTP{Host: cfg.SMTPHost, Port: cfg.SMTPPort, User: cfg.SMTPUser, Pass: cfg.SMTPPass, From: cfg.MailFrom}
+	case "file":
+		mail = &mailer.File{Dir: cfg.MailDir, From: cfg.MailFrom}
+	}
+
+	emailSvc := &services.EmailService{
+		Repo:      mysqladp.NewEmailRepo(db),
+		Mailer:    mail,
+		Audit:     auditSvc,
+		Secret:    []byte(cfg.JWTSecret),
+		VerifyTTL: cfg.EmailVerifyTTL,
+		ChangeTTL: cfg.EmailChangeTTL,
+		VerifyURL: cfg.EmailVerifyURL,
+		ChangeURL: cfg.EmailChangeURL,
+		Required:  cfg.EmailVerifyRequired,
+	}
+
 	authSvc := &services.AuthService{
 		Repo:       authRepo,
 		Sess:       sessionRepo,
@@ -63,6 +112,8 @@ func main() {
 		RefreshTTL: cfg.RefreshTTL,
 		Issuer:     "FinanceMaster",
 		Onboard:    onboard,
+		Account:    accountSvc,
+		Email:      emailSvc,
 		Audit:      auditSvc,
 
 		BindRefreshToUA:  true,
@@ -73,11 +124,37 @@ func main() {
 
 		CaptchaThreshold: cfg.CaptchaThreshold,
 		Captcha:          capVerifier,
+
+		Resets:   mysqladp.NewPasswordResetRepo(db),
+		Mailer:   mail,
+		ResetTTL: cfg.PasswordResetTTL,
+		ResetURL: cfg.PasswordResetURL,
 	}
 
-	txSvc := &services.TxService{Repo: txRepo, Audit: auditSvc, Idem: idemRepo}
-	walletSvc := &services.WalletService{Repo: walletRepo, Audit: auditSvc}
+	houseSvc := &services.HouseholdService{Repo: houseRepo, Wallets: walletRepo, Tx: txRepo, Audit: auditSvc}
+	groupSvc := &services.GroupService{Repo: groupRepo, Cats: catRepo, Access: houseSvc, Audit: auditSvc}
+	txSvc := &services.TxService{Repo: txRepo, Cats: catRepo, Audit: auditSvc, Idem: idemRepo, Access: houseSvc}
+	walletSvc := &services.WalletService{Repo: walletRepo, Audit: auditSvc, Roles: houseRepo}
 	catSvc := &services.CategoryService{Repo: catRepo, Audit: auditSvc}
+	stmtSvc := &services.StatementService{Wallets: walletRepo, Tx: txRepo}
+	instSvc := &services.InstallmentService{Repo: instRepo, Wallets: walletRepo, Audit: auditSvc}
+	contactSvc := &services.ContactService{Repo: contactRepo, Audit: auditSvc}
+	reconSvc := &services.ReconcileService{Repo: reconRepo, Wallets: walletRepo, Access: houseSvc, Audit: auditSvc}
+	viewSvc := &services.ViewService{Repo: viewRepo, Tx: txRepo, Cats: catRepo, Audit: auditSvc}
+	subSvc := &services.SubscriptionService{Repo: subRepo}
+	insightSvc := &services.InsightService{Repo: insightRepo}
+	reportSvc := &services.ReportService{Repo: reportRepo}
+	exportSvc := &services.ExportService{
+		Repo:    exportRepo,
+		Store:   exportStore,
+		Audit:   auditSvc,
+		Secret:  []byte(cfg.JWTSecret),
+		LinkTTL: cfg.ExportLinkTTL,
+		FileTTL: cfg.ExportFileTTL,
+	}
+	importSvc := &services.ImportService{Repo: importRepo, Audit: auditSvc}
+	debtSvc := &services.DebtService{Repo: debtRepo, Contacts: contactRepo, Audit: auditSvc, Access: houseSvc,
+		Wallets: walletRepo, Cats: catRepo}
 
 	httpClient := &http.Client{Timeout: 8 * time.Second}
 	ratesFetcher := &ratesadp.HTTPClient{BaseURL: cfg.RatesURL, Client: httpClient}
@@ -88,17 +165,67 @@ func main() {
 		StaleTTL: cfg.RatesStaleTTL,
 	}
 
+	investSvc := &services.InvestmentService{Repo: investRepo, Rates: ratesSvc, Access: houseSvc, Audit: auditSvc}
+	if cfg.PricesURL != "" {
+		investSvc.Quotes = &pricesadp.HTTPClient{BaseURL: cfg.PricesURL, Client: httpClient}
+	}
+	worthSvc := &services.NetWorthService{Repo: worthRepo, Rates: ratesSvc, Audit: auditSvc}
+	txSvc.NetWorth = worthSvc
+	debtSvc.NetWorth = worthSvc
+	importSvc.NetWorth = worthSvc
+
 	if cfg.RatesWarmEvery > 0 {
 		stop := cron.StartRatesWarm(context.Background(), ratesSvc, cfg.RatesWarmBases, cfg.RatesWarmEvery)
 		defer stop()
 	}
+	if cfg.PriceRefreshEvery > 0 {
+		stop := cron.StartPriceRefresh(context.Background(), investSvc, cfg.PriceRefreshEvery)
+		defer stop()
+	}
+	if cfg.NetWorthEvery > 0 {
+		stop := cron.StartNetWorth(context.Background(), worthSvc, cfg.NetWorthEvery)
+		defer stop()
+	}
+	if cfg.SubscriptionEvery > 0 {
+		stop := cron.StartSubscriptionScan(context.Background(), subSvc, cfg.SubscriptionEvery)
+		defer stop()
+	}
+	if cfg.AutoPostEvery > 0 {
+		stop := cron.StartAutoPost(context.Background(), txSvc, cfg.AutoPostEvery)
+		defer stop()
+	}
+	if cfg.ExportEvery > 0 {
+		stop := cron.StartExports(context.Background(), exportSvc, cfg.ExportEvery)
+		defer stop()
+	}
+	if cfg.PurgeEvery > 0 {
+		stop := cron.StartAccountPurge(context.Background(), accountSvc, cfg.PurgeEvery)
+		defer stop()
+	}
+	if cfg.InsightEvery > 0 {
+		stop := cron.StartInsights(context.Background(), insightSvc, cfg.InsightEvery)
+		defer stop()
+	}
 
 	api := &apihttp.API{
-		Auth:   &apihttp.AuthHandlers{S: authSvc},
-		H:      &apihttp.Handlers{Auth: authSvc, Tx: txSvc},
-		CatH:   &apihttp.CatalogHandlers{Wallet: walletSvc, Cat: catSvc},
-		Rates:  &apihttp.RatesHandlers{S: ratesSvc},
-		Secret: []byte(cfg.JWTSecret),
+		Auth:    &apihttp.AuthHandlers{S: authSvc},
+		H:       &apihttp.Handlers{Auth: authSvc, Tx: txSvc},
+		CatH:    &apihttp.CatalogHandlers{Wallet: walletSvc, Cat: catSvc, Stmt: stmtSvc},
+		Rates:   &apihttp.RatesHandlers{S: ratesSvc},
+		Inst:    &apihttp.InstallmentHandlers{S: instSvc},
+		Debt:    &apihttp.DebtHandlers{Contacts: contactSvc, Debts: debtSvc},
+		House:   &apihttp.HouseholdHandlers{S: houseSvc},
+		Group:   &apihttp.GroupHandlers{S: groupSvc},
+		Invest:  &apihttp.InvestmentHandlers{S: investSvc},
+		Worth:   &apihttp.NetWorthHandlers{S: worthSvc},
+		Recon:   &apihttp.ReconcileHandlers{S: reconSvc, Tx: txSvc},
+		Views:   &apihttp.ViewHandlers{S: viewSvc},
+		Insight: &apihttp.InsightHandlers{S: insightSvc, Subs: subSvc},
+		Report:  &apihttp.ReportHandlers{S: reportSvc},
+		Onboard: &apihttp.OnboardHandlers{S: onboard},
+		Account: &apihttp.AccountHandlers{S: accountSvc, Export: exportSvc, Import: importSvc},
+		Email:   &apihttp.EmailHandlers{S: emailSvc},
+		Secret:  []byte(cfg.JWTSecret),
 	}
 	r := apihttp.Router(api)
 
@@ -0,0 +1,33 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// Local dosyaları tek bir dizinde saklar; ad dizin dışına çıkamaz.
+type Local struct{ Dir string }
+
+func (l *Local) path(name string) string { return filepath.Join(l.Dir, filepath.Base(name)) }
+
+func (l *Local) Create(name string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(l.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(l.path(name), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+}
+
+func (l *Local) Open(name string) (io.ReadCloser, error) { return os.Open(l.path(name)) }
+
+func (l *Local) Remove(name string) error {
+	if err := os.Remove(l.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+var _ ports.BlobStore = (*Local)(nil)
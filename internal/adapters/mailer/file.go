@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// File iletileri Dir altına .eml olarak yazar; yerel geliştirme ve testlerde gönderilen
+// postaları incelemek için.
+type File struct {
+	Dir  string
+	From string
+
+	seq atomic.Int64
+}
+
+func (f *File) Send(m ports.Mail) error {
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%03d.eml", time.Now().UTC().Format("20060102T150405.000000000"), f.seq.Add(1))
+	return os.WriteFile(filepath.Join(f.Dir, name), render(f.From, m), 0o600)
+}
+
+var _ ports.Mailer = (*File)(nil)
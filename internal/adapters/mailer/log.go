@@ -0,0 +1,18 @@
+package mailer
+
+import (
+	"log"
+
+	"github.com/Veysel440/finance-master-api/internal/obs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// Log iletiyi yalnız günlüğe yazar. Gövde bağlantı/belirteç içerebileceği için yazılmaz.
+type Log struct{}
+
+func (Log) Send(m ports.Mail) error {
+	log.Printf("mail: to=%s subject=%q (%d bytes)", obs.MaskEmail(m.To), m.Subject, len(m.Text))
+	return nil
+}
+
+var _ ports.Mailer = Log{}
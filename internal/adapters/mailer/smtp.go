@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type SMTP struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+}
+
+func (s *SMTP) Send(m ports.Mail) error {
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	var auth smtp.Auth
+	if s.User != "" {
+		auth = smtp.PlainAuth("", s.User, s.Pass, s.Host)
+	}
+	return smtp.SendMail(addr, auth, s.From, []string{m.To}, render(s.From, m))
+}
+
+// render düz metin RFC 5322 iletisi üretir; başlıklardaki satır sonları atılır (header injection).
+func render(from string, m ports.Mail) []byte {
+	clean := strings.NewReplacer("\r", "", "\n", "").Replace
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", clean(from))
+	fmt.Fprintf(&b, "To: %s\r\n", clean(m.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", clean(m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Text, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+var _ ports.Mailer = (*SMTP)(nil)
@@ -0,0 +1,259 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type AccountRepo struct{ db *sqlx.DB }
+
+func NewAccountRepo(db *sqlx.DB) *AccountRepo { return &AccountRepo{db: db} }
+
+var _ ports.AccountRepo = (*AccountRepo)(nil)
+
+func (r *AccountRepo) PassHash(userID int64) (string, error) {
+	var h string
+	err := r.db.Get(&h, `SELECT pass_hash FROM users WHERE id=?`, userID)
+	return h, err
+}
+
+func (r *AccountRepo) RequestDeletion(userID int64, at, after time.Time) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`UPDATE users SET deletion_requested_at=?, delete_after=? WHERE id=?`, at, after, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *AccountRepo) CancelDeletion(userID int64) (bool, error) {
+	res, err := r.db.Exec(`UPDATE users SET deletion_requested_at=NULL, delete_after=NULL
+		WHERE id=? AND delete_after IS NOT NULL`, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *AccountRepo) DueDeletions(now time.Time, limit int) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(&ids, `SELECT id FROM users WHERE delete_after IS NOT NULL AND delete_after <= ?
+		ORDER BY delete_after LIMIT ?`, now, limit)
+	return ids, err
+}
+
+func (r *AccountRepo) Purge(userID int64) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var pending int
+	if err := tx.Get(&pending, `SELECT COUNT(*) FROM users WHERE id=? AND delete_after IS NOT NULL FOR UPDATE`, userID); err != nil {
+		return err
+	}
+	if pending == 0 {
+		// Bu arada giriş yapılıp talep iptal edilmiş olabilir.
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`UPDATE audit_logs SET user_id=NULL, details=NULL WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	if err := handOverHouseholds(tx, userID); err != nil {
+		return err
+	}
+	if err := handOverGroups(tx, userID); err != nil {
+		return err
+	}
+	if err := handOverSharedRows(tx, userID); err != nil {
+		return err
+	}
+
+	// Çocuktan ebeveyne: her adım bir sonrakinin FK'sini serbest bırakır.
+	steps := []string{
+		`DELETE FROM household_invites WHERE invited_by=?`,
+		`DELETE FROM debt_payments WHERE user_id=?`,
+		`DELETE FROM debts WHERE user_id=?`,
+		`DELETE FROM contacts WHERE user_id=?`,
+		`DELETE FROM holding_lots WHERE user_id=?`,
+		`DELETE FROM assets WHERE user_id=?`,
+		`DELETE FROM transactions WHERE user_id=?`,
+		`DELETE FROM installment_plans WHERE user_id=?`,
+		`DELETE FROM reconciliations WHERE user_id=?`,
+		// Kullanıcının cüzdanlarında kalan başkalarına ait satırlar (hanesinden ayrılmış
+		// eski üyelerin kayıtları) cüzdanla birlikte gider; aksi halde FK silmeyi engeller.
+		`DELETE t FROM transactions t JOIN wallets w ON w.id = t.wallet_id WHERE w.user_id=?`,
+		`DELETE p FROM installment_plans p JOIN wallets w ON w.id = p.wallet_id WHERE w.user_id=?`,
+		`DELETE l FROM holding_lots l JOIN wallets w ON w.id = l.wallet_id WHERE w.user_id=?`,
+		`DELETE FROM wallets WHERE user_id=?`,
+		`UPDATE categories SET parent_id=NULL, merged_into_id=NULL WHERE user_id=?`,
+		`DELETE FROM categories WHERE user_id=?`,
+		`DELETE FROM households WHERE created_by=?`,
+		`DELETE r FROM session_rotations r JOIN sessions s ON s.id=r.session_id WHERE s.user_id=?`,
+		`DELETE FROM sessions WHERE user_id=?`,
+		`DELETE FROM user_devices WHERE user_id=?`,
+		`DELETE FROM totp_secrets WHERE user_id=?`,
+		`DELETE FROM idempotency_keys WHERE user_id=?`,
+		`DELETE FROM users WHERE id=?`,
+	}
+	for _, q := range steps {
+		if _, err := tx.Exec(q, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// handOverHouseholds kullanıcının kurduğu ve başka üyesi olan haneleri en eski sahibe (yoksa en
+// eski üyeye) devreder; paylaşılan hanelerdeki cüzdanları da hane sahibine geçer. Tek üyeli
+// hanelerde başkasına ait kalmış cüzdanlar haneden ayrılır, hane sonra silinir.
+func handOverHouseholds(tx *sqlx.Tx, userID int64) error {
+	hids := []int64{}
+	if err := tx.Select(&hids, `SELECT id FROM households WHERE created_by=? FOR UPDATE`, userID); err != nil {
+		return err
+	}
+	for _, hid := range hids {
+		var next int64
+		err := tx.Get(&next, `SELECT user_id FROM household_members
+			WHERE household_id=? AND user_id<>?
+			ORDER BY role='owner' DESC, joined_at, user_id LIMIT 1`, hid, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := tx.Exec(`UPDATE wallets SET household_id=NULL WHERE household_id=? AND user_id<>?`, hid, userID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE households SET created_by=? WHERE id=?`, next, hid); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE household_members SET role='owner' WHERE household_id=? AND user_id=?`, hid, next); err != nil {
+			return err
+		}
+	}
+	// Ad kullanıcı içinde tekil; yeni sahipte aynı adlı cüzdan varsa devredilen yeniden adlandırılır.
+	if _, err := tx.Exec(`
+		UPDATE wallets w
+		JOIN households h ON h.id = w.household_id
+		JOIN wallets o ON o.user_id = h.created_by AND o.name = w.name
+		SET w.name = CONCAT(LEFT(w.name, 60), ' #', w.id)
+		WHERE w.user_id=? AND h.created_by<>?`, userID, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+		UPDATE wallets w
+		JOIN households h ON h.id = w.household_id
+		SET w.user_id = h.created_by
+		WHERE w.user_id=? AND h.created_by<>?`, userID, userID)
+	return err
+}
+
+// handOverSharedRows kullanıcının başkasına ait cüzdanlardaki (devredilen ya da katıldığı
+// hanedeki) işlem, taksit ve mutabakat kayıtlarını cüzdan sahibine aktarır; ortak defter
+// eksilmez. Kullanıcının kategorisindeki kayıtlar sahipte aynı tür ve addaki kategoriye
+// (yoksa oluşturulur) taşınır. Oluşturan (created_by) FK ile NULL'a düşer.
+func handOverSharedRows(tx *sqlx.Tx, userID int64) error {
+	steps := []string{
+		`INSERT IGNORE INTO categories(user_id, type, name)
+		 SELECT DISTINCT w.user_id, c.type, c.name
+		 FROM transactions t
+		 JOIN wallets w ON w.id = t.wallet_id
+		 JOIN categories c ON c.id = t.category_id
+		 WHERE t.user_id=? AND w.user_id<>? AND c.user_id=?`,
+		`INSERT IGNORE INTO categories(user_id, type, name)
+		 SELECT DISTINCT w.user_id, c.type, c.name
+		 FROM installment_plans p
+		 JOIN wallets w ON w.id = p.wallet_id
+		 JOIN categories c ON c.id = p.category_id
+		 WHERE p.user_id=? AND w.user_id<>? AND c.user_id=?`,
+		`UPDATE transactions t
+		 JOIN wallets w ON w.id = t.wallet_id
+		 JOIN categories c ON c.id = t.category_id
+		 JOIN categories n ON n.user_id = w.user_id AND n.type = c.type AND n.name = c.name
+		 SET t.category_id = n.id
+		 WHERE t.user_id=? AND w.user_id<>? AND c.user_id=?`,
+		`UPDATE installment_plans p
+		 JOIN wallets w ON w.id = p.wallet_id
+		 JOIN categories c ON c.id = p.category_id
+		 JOIN categories n ON n.user_id = w.user_id AND n.type = c.type AND n.name = c.name
+		 SET p.category_id = n.id
+		 WHERE p.user_id=? AND w.user_id<>? AND c.user_id=?`,
+		`UPDATE transactions t JOIN wallets w ON w.id = t.wallet_id
+		 SET t.user_id = w.user_id WHERE t.user_id=? AND w.user_id<>?`,
+		`UPDATE installment_plans p JOIN wallets w ON w.id = p.wallet_id
+		 SET p.user_id = w.user_id WHERE p.user_id=? AND w.user_id<>?`,
+		`UPDATE reconciliations r JOIN wallets w ON w.id = r.wallet_id
+		 SET r.user_id = w.user_id WHERE r.user_id=? AND w.user_id<>?`,
+	}
+	for _, q := range steps {
+		args := make([]any, strings.Count(q, "?"))
+		for i := range args {
+			args[i] = userID
+		}
+		if _, err := tx.Exec(q, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handOverGroups bağlı başka üyesi olan grupları o üyeye devreder, kalanları tamamen siler.
+// Başkalarının gruplarında kullanıcının yazdığı masraflar grup sahibine geçer.
+func handOverGroups(tx *sqlx.Tx, userID int64) error {
+	gids := []int64{}
+	if err := tx.Select(&gids, `SELECT id FROM expense_groups WHERE owner_id=? FOR UPDATE`, userID); err != nil {
+		return err
+	}
+	for _, gid := range gids {
+		var next int64
+		err := tx.Get(&next, `SELECT user_id FROM group_members
+			WHERE group_id=? AND user_id IS NOT NULL AND user_id<>? ORDER BY id LIMIT 1`, gid, userID)
+		if err == nil {
+			if _, err := tx.Exec(`UPDATE expense_groups SET owner_id=? WHERE id=?`, next, gid); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		for _, q := range []string{
+			`DELETE FROM group_settlements WHERE group_id=?`,
+			`DELETE FROM group_expenses WHERE group_id=?`,
+			`DELETE FROM group_members WHERE group_id=?`,
+			`DELETE FROM expense_groups WHERE id=?`,
+		} {
+			if _, err := tx.Exec(q, gid); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := tx.Exec(`
+		UPDATE group_expenses e
+		JOIN expense_groups g ON g.id = e.group_id
+		SET e.created_by = g.owner_id
+		WHERE e.created_by=?`, userID)
+	return err
+}
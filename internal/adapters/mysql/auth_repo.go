@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -29,6 +30,24 @@ func (r *AuthRepo) FindUserByEmail(email string) (*ports.User, error) {
 	}
 	return &u, nil
 }
+func (r *AuthRepo) FindUserByID(id int64) (*ports.User, error) {
+	var u ports.User
+	err := r.db.QueryRowx(`SELECT id,name,email,pass_hash FROM users WHERE id=?`, id).Scan(&u.ID, &u.Name, &u.Email, &u.PassHash)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+func (r *AuthRepo) SetPassword(userID int64, passHash string) error {
+	res, err := r.db.Exec(`UPDATE users SET pass_hash=? WHERE id=?`, passHash, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
 func (r *AuthRepo) StoreRefresh(userID int64, refreshHash string, expires time.Time) error {
 	_, err := r.db.Exec(`INSERT INTO sessions(user_id, refresh_hash, expires_at) VALUES (?,?,?)`,
@@ -94,3 +113,57 @@ func (r *AuthRepo) ConfirmTotp(userID int64) error {
 	_, err := r.db.Exec(`UPDATE totp_secrets SET confirmed_at=NOW() WHERE user_id=?`, userID)
 	return err
 }
+
+func (r *AuthRepo) UseTotpStep(userID, step int64) (bool, error) {
+	res, err := r.db.Exec(`UPDATE totp_secrets SET last_step=?
+		WHERE user_id=? AND (last_step IS NULL OR last_step < ?)`, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *AuthRepo) ReplaceRecoveryCodes(userID int64, hashes []string) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if _, err := tx.Exec(`INSERT INTO totp_recovery_codes(user_id, code_hash) VALUES (?,?)`, userID, h); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AuthRepo) UseRecoveryCode(userID int64, hash string, at time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE totp_recovery_codes SET used_at=?
+		WHERE user_id=? AND code_hash=? AND used_at IS NULL`, at, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *AuthRepo) DeleteTotp(userID int64) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM totp_recovery_codes WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM totp_secrets WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -2,8 +2,10 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/Veysel440/finance-master-api/internal/errs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
@@ -15,25 +17,35 @@ type CategoryRepo struct{ db *sqlx.DB }
 
 func NewCategoryRepo(db *sqlx.DB) *CategoryRepo { return &CategoryRepo{db: db} }
 
+const catCols = `id, user_id, parent_id, name, type, sort_order, icon, color, archived_at, merged_into_id, updated_at`
+
 func (r *CategoryRepo) List(userID int64, typ string) ([]ports.Category, error) {
 	var rows []ports.Category
-	q := `SELECT id, user_id, name, type FROM categories WHERE user_id=?`
+	q := `SELECT ` + catCols + ` FROM categories WHERE user_id=?`
 	args := []any{userID}
 	if s := strings.TrimSpace(typ); s != "" {
 		q += ` AND type=?`
 		args = append(args, s)
 	}
-	q += ` ORDER BY type, name`
+	q += ` ORDER BY type, sort_order, name`
 	if err := r.db.Select(&rows, q, args...); err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
 
+func (r *CategoryRepo) Get(userID, id int64) (*ports.Category, error) {
+	var c ports.Category
+	if err := r.db.Get(&c, `SELECT `+catCols+` FROM categories WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
 func (r *CategoryRepo) Create(userID int64, c *ports.Category) error {
 	res, err := r.db.ExecContext(context.Background(),
-		`INSERT INTO categories(user_id, name, type) VALUES (?,?,?)`,
-		userID, c.Name, c.Type,
+		`INSERT INTO categories(user_id, parent_id, name, type, sort_order, icon, color) VALUES (?,?,?,?,?,?,?)`,
+		userID, c.ParentID, c.Name, c.Type, c.SortOrder, c.Icon, c.Color,
 	)
 	if err != nil {
 		return err
@@ -45,19 +57,83 @@ func (r *CategoryRepo) Create(userID int64, c *ports.Category) error {
 
 func (r *CategoryRepo) Update(userID int64, c *ports.Category) error {
 	_, err := r.db.ExecContext(context.Background(),
-		`UPDATE categories SET name=?, type=? WHERE id=? AND user_id=?`,
-		c.Name, c.Type, c.ID, userID,
+		`UPDATE categories SET parent_id=?, name=?, type=?, sort_order=?, icon=?, color=?, updated_at=NOW() WHERE id=? AND user_id=?`,
+		c.ParentID, c.Name, c.Type, c.SortOrder, c.Icon, c.Color, c.ID, userID,
 	)
 	return err
 }
 
+func (r *CategoryRepo) ListSince(userID int64, since time.Time) ([]ports.Category, error) {
+	rows := []ports.Category{}
+	err := r.db.Select(&rows, `SELECT `+catCols+` FROM categories WHERE user_id=? AND updated_at > ? ORDER BY updated_at ASC`,
+		userID, since)
+	return rows, err
+}
+
+func (r *CategoryRepo) SetArchived(userID, id int64, archived bool) error {
+	q := `UPDATE categories SET archived_at=NULL, updated_at=NOW() WHERE id=? AND user_id=?`
+	if archived {
+		q = `UPDATE categories SET archived_at=COALESCE(archived_at, NOW()), updated_at=NOW() WHERE id=? AND user_id=?`
+	}
+	res, err := r.db.ExecContext(context.Background(), q, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CategoryRepo) Merge(userID, srcID, dstID int64) (moved int64, err error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// İki satırı da kilitle; eşzamanlı merge/taşıma yarışını engeller.
+	var n int
+	if err = tx.Get(&n, `SELECT COUNT(*) FROM categories WHERE user_id=? AND id IN (?,?) FOR UPDATE`,
+		userID, srcID, dstID); err != nil {
+		return 0, err
+	}
+	if n != 2 {
+		return 0, sql.ErrNoRows
+	}
+
+	res, err := tx.Exec(`UPDATE transactions SET category_id=?, updated_at=NOW() WHERE user_id=? AND category_id=?`,
+		dstID, userID, srcID)
+	if err != nil {
+		return 0, err
+	}
+	moved, _ = res.RowsAffected()
+
+	if _, err = tx.Exec(`UPDATE categories SET parent_id=?, updated_at=NOW() WHERE user_id=? AND parent_id=?`,
+		dstID, userID, srcID); err != nil {
+		return 0, err
+	}
+	if _, err = tx.Exec(`
+		UPDATE categories SET archived_at=COALESCE(archived_at, NOW()), merged_into_id=?, updated_at=NOW()
+		WHERE id=? AND user_id=?`, dstID, srcID, userID); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
 func (r *CategoryRepo) Delete(userID int64, id int64) error {
 	_, err := r.db.ExecContext(context.Background(),
 		`DELETE FROM categories WHERE id=? AND user_id=?`, id, userID,
-	) // FK RESTRICT -> ilişkili tx varsa 1451
+	) // FK RESTRICT -> ilişkili tx ya da alt kategori varsa 1451
 	if err != nil {
 		var me *mysql.MySQLError
 		if errors.As(err, &me) && me.Number == 1451 {
+			if strings.Contains(me.Message, "fk_cat_parent") {
+				return errs.HasChildren
+			}
 			return errs.HasTransactions
 		}
 		return err
@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+type ContactRepo struct{ db *sqlx.DB }
+
+func NewContactRepo(db *sqlx.DB) *ContactRepo { return &ContactRepo{db: db} }
+
+const contactCols = `id, user_id, name, email, phone, note, created_at, updated_at`
+
+func (r *ContactRepo) List(userID int64) ([]ports.Contact, error) {
+	rows := []ports.Contact{}
+	err := r.db.Select(&rows, `SELECT `+contactCols+` FROM contacts WHERE user_id=? ORDER BY name`, userID)
+	return rows, err
+}
+
+func (r *ContactRepo) Get(userID, id int64) (*ports.Contact, error) {
+	var c ports.Contact
+	if err := r.db.Get(&c, `SELECT `+contactCols+` FROM contacts WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ContactRepo) Create(userID int64, c *ports.Contact) error {
+	res, err := r.db.ExecContext(context.Background(),
+		`INSERT INTO contacts(user_id, name, email, phone, note) VALUES (?,?,?,?,?)`,
+		userID, c.Name, c.Email, c.Phone, c.Note)
+	if err != nil {
+		return err
+	}
+	c.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *ContactRepo) Update(userID int64, c *ports.Contact) error {
+	_, err := r.db.ExecContext(context.Background(),
+		`UPDATE contacts SET name=?, email=?, phone=?, note=? WHERE id=? AND user_id=?`,
+		c.Name, c.Email, c.Phone, c.Note, c.ID, userID)
+	return err
+}
+
+func (r *ContactRepo) Delete(userID, id int64) error {
+	_, err := r.db.ExecContext(context.Background(), `DELETE FROM contacts WHERE id=? AND user_id=?`, id, userID)
+	if err != nil {
+		var me *mysql.MySQLError
+		if errors.As(err, &me) && me.Number == 1451 {
+			return errs.HasDebts
+		}
+		return err
+	}
+	return nil
+}
+
+type DebtRepo struct{ db *sqlx.DB }
+
+func NewDebtRepo(db *sqlx.DB) *DebtRepo { return &DebtRepo{db: db} }
+
+const debtSelect = `
+SELECT d.id, d.user_id, d.contact_id, d.direction, d.amount, d.currency, d.due_at, d.note, d.status,
+       d.created_at, d.updated_at, d.settled_at, COALESCE(p.paid, 0) AS paid
+FROM debts d
+LEFT JOIN (SELECT debt_id, SUM(amount) AS paid FROM debt_payments GROUP BY debt_id) p ON p.debt_id = d.id`
+
+func (r *DebtRepo) List(userID int64, f ports.DebtFilter) ([]ports.Debt, error) {
+	q := debtSelect + ` WHERE d.user_id=?`
+	args := []any{userID}
+	if f.ContactID > 0 {
+		q += ` AND d.contact_id=?`
+		args = append(args, f.ContactID)
+	}
+	if f.Status != "" {
+		q += ` AND d.status=?`
+		args = append(args, f.Status)
+	}
+	if f.OverdueAt != nil {
+		q += ` AND d.status='open' AND d.due_at IS NOT NULL AND d.due_at < ?`
+		args = append(args, *f.OverdueAt)
+	}
+	q += ` ORDER BY d.due_at IS NULL, d.due_at ASC, d.id DESC`
+	rows := []ports.Debt{}
+	err := r.db.Select(&rows, q, args...)
+	return rows, err
+}
+
+func (r *DebtRepo) Get(userID, id int64) (*ports.Debt, error) {
+	var d ports.Debt
+	if err := r.db.Get(&d, debtSelect+` WHERE d.id=? AND d.user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *DebtRepo) Create(userID int64, d *ports.Debt) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO debts(user_id, contact_id, direction, amount, currency, due_at, note, status)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		userID, d.ContactID, d.Direction, d.Amount, d.Currency, d.DueAt, d.Note, d.Status)
+	if err != nil {
+		return err
+	}
+	d.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *DebtRepo) Payments(userID, debtID int64) ([]ports.DebtPayment, error) {
+	rows := []ports.DebtPayment{}
+	err := r.db.Select(&rows, `
+		SELECT id, debt_id, user_id, amount, paid_at, transaction_id, note, created_at
+		FROM debt_payments WHERE user_id=? AND debt_id=? ORDER BY paid_at ASC, id ASC`, userID, debtID)
+	return rows, err
+}
+
+func (r *DebtRepo) AddPayment(userID int64, p *ports.DebtPayment, t *ports.Transaction) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var d struct {
+		Amount float64 `db:"amount"`
+		Status string  `db:"status"`
+	}
+	if err = tx.Get(&d, `SELECT amount, status FROM debts WHERE id=? AND user_id=? FOR UPDATE`, p.DebtID, userID); err != nil {
+		return err
+	}
+	amount := d.Amount
+	if d.Status == ports.DebtSettled {
+		return errs.ValidationFailed("debt_settled")
+	}
+	// Servisteki kontrol kilitten önce yapılır; eşzamanlı ödemeler fazla ödemeye yol açmasın diye
+	// kalan tutar kilit altında yeniden hesaplanır.
+	var before float64
+	if err = tx.Get(&before, `SELECT COALESCE(SUM(amount),0) FROM debt_payments WHERE debt_id=?`, p.DebtID); err != nil {
+		return err
+	}
+	if math.Round(p.Amount*100) > math.Round((amount-before)*100) {
+		return errs.ValidationFailed("bad_amount")
+	}
+	if t != nil {
+		if err = insertTxRow(tx, userID, t); err != nil {
+			return err
+		}
+		p.TransactionID = &t.ID
+	}
+	res, err := tx.Exec(`
+		INSERT INTO debt_payments(debt_id, user_id, amount, paid_at, transaction_id, note)
+		VALUES (?,?,?,?,?,?)`, p.DebtID, userID, p.Amount, p.PaidAt, p.TransactionID, p.Note)
+	if err != nil {
+		return err
+	}
+	p.ID, _ = res.LastInsertId()
+
+	var paid float64
+	if err = tx.Get(&paid, `SELECT COALESCE(SUM(amount),0) FROM debt_payments WHERE debt_id=?`, p.DebtID); err != nil {
+		return err
+	}
+	if paid >= amount {
+		if _, err = tx.Exec(`UPDATE debts SET status='settled', settled_at=? WHERE id=?`, p.PaidAt, p.DebtID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *DebtRepo) Summary(userID int64) ([]ports.DebtBalance, error) {
+	rows := []ports.DebtBalance{}
+	err := r.db.Select(&rows, `
+		SELECT d.contact_id, c.name AS contact_name, d.currency,
+		       SUM(CASE WHEN d.direction='lent'     THEN d.amount - COALESCE(p.paid,0) ELSE 0 END) AS owed_to_me,
+		       SUM(CASE WHEN d.direction='borrowed' THEN d.amount - COALESCE(p.paid,0) ELSE 0 END) AS i_owe,
+		       SUM(CASE WHEN d.direction='lent' THEN 1 ELSE -1 END * (d.amount - COALESCE(p.paid,0))) AS net
+		FROM debts d
+		JOIN contacts c ON c.id = d.contact_id
+		LEFT JOIN (SELECT debt_id, SUM(amount) AS paid FROM debt_payments GROUP BY debt_id) p ON p.debt_id = d.id
+		WHERE d.user_id=? AND d.status='open'
+		GROUP BY d.contact_id, c.name, d.currency
+		ORDER BY c.name, d.currency`, userID)
+	return rows, err
+}
+
+var (
+	_ ports.ContactRepo = (*ContactRepo)(nil)
+	_ ports.DebtRepo    = (*DebtRepo)(nil)
+)
@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type EmailRepo struct{ db *sqlx.DB }
+
+func NewEmailRepo(db *sqlx.DB) *EmailRepo { return &EmailRepo{db: db} }
+
+var _ ports.EmailRepo = (*EmailRepo)(nil)
+
+const emailChangeCols = `id, user_id, new_email, old_confirmed_at, new_confirmed_at, completed_at, expires_at, created_at`
+
+func (r *EmailRepo) EmailStatus(userID int64) (*ports.EmailStatus, error) {
+	var s ports.EmailStatus
+	if err := r.db.Get(&s, `SELECT id, email, pass_hash, verified_at FROM users WHERE id=?`, userID); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *EmailRepo) MarkVerified(userID int64, email string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE users SET verified_at=? WHERE id=? AND email=? AND verified_at IS NULL`, at, userID, email)
+	return err
+}
+
+func (r *EmailRepo) EmailTaken(email string, exceptUserID int64) (bool, error) {
+	var n int
+	err := r.db.Get(&n, `SELECT COUNT(*) FROM users WHERE email=? AND id<>?`, email, exceptUserID)
+	return n > 0, err
+}
+
+func (r *EmailRepo) CreateChange(c *ports.EmailChange) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM email_changes WHERE user_id=? AND completed_at IS NULL`, c.UserID); err != nil {
+		return err
+	}
+	res, err := tx.Exec(`INSERT INTO email_changes(user_id, new_email, expires_at, created_at) VALUES (?,?,?,?)`,
+		c.UserID, c.NewEmail, c.ExpiresAt, c.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if c.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *EmailRepo) ConfirmChange(id int64, side string, now time.Time) (*ports.EmailChange, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var c ports.EmailChange
+	if err := tx.Get(&c, `SELECT `+emailChangeCols+` FROM email_changes
+		WHERE id=? AND completed_at IS NULL AND expires_at > ? FOR UPDATE`, id, now); err != nil {
+		return nil, err
+	}
+	switch side {
+	case ports.EmailSideOld:
+		if c.OldConfirmedAt == nil {
+			c.OldConfirmedAt = &now
+		}
+	case ports.EmailSideNew:
+		if c.NewConfirmedAt == nil {
+			c.NewConfirmedAt = &now
+		}
+	default:
+		return nil, sql.ErrNoRows
+	}
+	if _, err := tx.Exec(`UPDATE email_changes SET old_confirmed_at=?, new_confirmed_at=? WHERE id=?`,
+		c.OldConfirmedAt, c.NewConfirmedAt, c.ID); err != nil {
+		return nil, err
+	}
+
+	if c.OldConfirmedAt != nil && c.NewConfirmedAt != nil {
+		var taken int
+		if err := tx.Get(&taken, `SELECT COUNT(*) FROM users WHERE email=? AND id<>? FOR UPDATE`, c.NewEmail, c.UserID); err != nil {
+			return nil, err
+		}
+		if taken > 0 {
+			return nil, errs.Conflict
+		}
+		// Yeni adres onaylandığı için doğrulanmış sayılır.
+		if _, err := tx.Exec(`UPDATE users SET email=?, verified_at=? WHERE id=?`, c.NewEmail, now, c.UserID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE email_changes SET completed_at=? WHERE id=?`, now, c.ID); err != nil {
+			return nil, err
+		}
+		// Eski adrese gönderilmiş sıfırlama bağlantıları geçersiz kalmalı.
+		if _, err := tx.Exec(`UPDATE password_resets SET used_at=? WHERE user_id=? AND used_at IS NULL`, now, c.UserID); err != nil {
+			return nil, err
+		}
+		c.CompletedAt = &now
+	}
+	return &c, tx.Commit()
+}
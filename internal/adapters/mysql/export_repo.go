@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type ExportRepo struct{ db *sqlx.DB }
+
+func NewExportRepo(db *sqlx.DB) *ExportRepo { return &ExportRepo{db: db} }
+
+const exportCols = `id, user_id, status, file_name, size_bytes, sha256, error, attempts, created_at, started_at,
+	completed_at, expires_at`
+
+// exportQueries veri kümesi başına sorgular. Parola özeti, refresh hash ve TOTP sırrı
+// gibi kimlik bilgileri bilinçli olarak dışarıda bırakılır.
+var exportQueries = map[string]string{
+	"profile": `SELECT id, name, email, base_currency, locale, created_at FROM users WHERE id=?`,
+	"wallets": `SELECT id, household_id, name, currency, kind, credit_limit, statement_day, due_day,
+		institution, account_mask, archived_at, updated_at
+		FROM wallets WHERE user_id=? ORDER BY id`,
+	"categories": `SELECT ` + catCols + ` FROM categories WHERE user_id=? ORDER BY id`,
+	"transactions": `SELECT id, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at,
+		deleted_at, status, reconciliation_id, installment_plan_id, installment_no, tax_rate, tax_amount, deductible
+		FROM transactions WHERE user_id=? ORDER BY id`,
+	"sessions":   `SELECT id, ua, ip, created_at, last_used_at, expires_at FROM sessions WHERE user_id=? ORDER BY id`,
+	"devices":    `SELECT id, device_id, name, last_seen, created_at FROM user_devices WHERE user_id=? ORDER BY id`,
+	"audit_logs": `SELECT id, action, entity, entity_id, details, created_at FROM audit_logs WHERE user_id=? ORDER BY id`,
+}
+
+func (r *ExportRepo) Create(e *ports.DataExport) error {
+	res, err := r.db.Exec(`INSERT INTO data_exports(user_id, status) VALUES (?, 'queued')`, e.UserID)
+	if err != nil {
+		return err
+	}
+	e.ID, _ = res.LastInsertId()
+	e.Status = ports.ExportQueued
+	return r.db.Get(&e.CreatedAt, `SELECT created_at FROM data_exports WHERE id=?`, e.ID)
+}
+
+func (r *ExportRepo) Active(userID int64) (*ports.DataExport, error) {
+	var e ports.DataExport
+	if err := r.db.Get(&e, `SELECT `+exportCols+` FROM data_exports
+		WHERE user_id=? AND status IN ('queued','running') ORDER BY id DESC LIMIT 1`, userID); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ExportRepo) Get(userID, id int64) (*ports.DataExport, error) {
+	var e ports.DataExport
+	if err := r.db.Get(&e, `SELECT `+exportCols+` FROM data_exports WHERE id=? AND user_id=?`, id, userID); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ExportRepo) GetByID(id int64) (*ports.DataExport, error) {
+	var e ports.DataExport
+	if err := r.db.Get(&e, `SELECT `+exportCols+` FROM data_exports WHERE id=?`, id); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ExportRepo) List(userID int64) ([]ports.DataExport, error) {
+	rows := []ports.DataExport{}
+	err := r.db.Select(&rows, `SELECT `+exportCols+` FROM data_exports WHERE user_id=? ORDER BY id DESC LIMIT 20`, userID)
+	return rows, err
+}
+
+func (r *ExportRepo) Claim() (*ports.DataExport, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var e ports.DataExport
+	err = tx.Get(&e, `SELECT `+exportCols+` FROM data_exports
+		WHERE status='queued' ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE data_exports SET status='running', started_at=UTC_TIMESTAMP(), attempts=attempts+1
+		WHERE id=?`, e.ID); err != nil {
+		return nil, err
+	}
+	e.Status = ports.ExportRunning
+	e.Attempts++
+	return &e, tx.Commit()
+}
+
+func (r *ExportRepo) ReclaimStale(before time.Time, maxAttempts int) (int64, int64, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		UPDATE data_exports SET status='failed', error='export_timed_out', completed_at=UTC_TIMESTAMP()
+		WHERE status='running' AND started_at < ? AND attempts >= ?`, before, maxAttempts)
+	if err != nil {
+		return 0, 0, err
+	}
+	failed, _ := res.RowsAffected()
+	res, err = tx.Exec(`
+		UPDATE data_exports SET status='queued', started_at=NULL
+		WHERE status='running' AND started_at < ?`, before)
+	if err != nil {
+		return 0, 0, err
+	}
+	requeued, _ := res.RowsAffected()
+	return requeued, failed, tx.Commit()
+}
+
+func (r *ExportRepo) Finish(id int64, file string, size int64, sha string, completed, expires time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE data_exports
+		SET status='ready', file_name=?, size_bytes=?, sha256=?, completed_at=?, expires_at=?, error=NULL
+		WHERE id=?`, file, size, sha, completed, expires, id)
+	return err
+}
+
+func (r *ExportRepo) Fail(id int64, msg string) error {
+	if len(msg) > 255 {
+		msg = msg[:255]
+	}
+	_, err := r.db.Exec(`UPDATE data_exports SET status='failed', error=?, completed_at=UTC_TIMESTAMP() WHERE id=?`, msg, id)
+	return err
+}
+
+func (r *ExportRepo) ExpiredBefore(now time.Time) ([]ports.DataExport, error) {
+	rows := []ports.DataExport{}
+	err := r.db.Select(&rows, `SELECT `+exportCols+` FROM data_exports
+		WHERE status='ready' AND expires_at <= ? ORDER BY id LIMIT 500`, now)
+	return rows, err
+}
+
+func (r *ExportRepo) MarkExpired(id int64) error {
+	_, err := r.db.Exec(`UPDATE data_exports SET status='expired', file_name=NULL WHERE id=?`, id)
+	return err
+}
+
+func (r *ExportRepo) Stream(userID int64, dataset string, fn func(cols []string, vals []any) error) error {
+	q, ok := exportQueries[dataset]
+	if !ok {
+		return fmt.Errorf("unknown dataset %q", dataset)
+	}
+	rows, err := r.db.Queryx(q, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if err := fn(cols, vals); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+var _ ports.ExportRepo = (*ExportRepo)(nil)
@@ -0,0 +1,206 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type GroupRepo struct{ db *sqlx.DB }
+
+func NewGroupRepo(db *sqlx.DB) *GroupRepo { return &GroupRepo{db: db} }
+
+const groupMemberCols = `id, group_id, user_id, name, invite_email, invite_expires_at, created_at`
+
+func (r *GroupRepo) Create(ownerID int64, g *ports.ExpenseGroup, owner *ports.GroupMember) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`INSERT INTO expense_groups(owner_id, name, currency) VALUES (?,?,?)`,
+		ownerID, g.Name, g.Currency)
+	if err != nil {
+		return err
+	}
+	g.ID, _ = res.LastInsertId()
+	g.OwnerID = ownerID
+	owner.GroupID = g.ID
+	if err = insertGroupMember(tx, owner); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *GroupRepo) ListForUser(userID int64) ([]ports.ExpenseGroup, error) {
+	rows := []ports.ExpenseGroup{}
+	err := r.db.Select(&rows, `
+		SELECT g.id, g.owner_id, g.name, g.currency, g.created_at
+		FROM expense_groups g
+		JOIN group_members m ON m.group_id = g.id
+		WHERE m.user_id=?
+		ORDER BY g.created_at DESC`, userID)
+	return rows, err
+}
+
+func (r *GroupRepo) Get(id int64) (*ports.ExpenseGroup, error) {
+	var g ports.ExpenseGroup
+	if err := r.db.Get(&g, `SELECT id, owner_id, name, currency, created_at FROM expense_groups WHERE id=? LIMIT 1`, id); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *GroupRepo) Members(groupID int64) ([]ports.GroupMember, error) {
+	rows := []ports.GroupMember{}
+	err := r.db.Select(&rows, `SELECT `+groupMemberCols+` FROM group_members WHERE group_id=? ORDER BY id`, groupID)
+	return rows, err
+}
+
+func (r *GroupRepo) MemberByUser(groupID, userID int64) (*ports.GroupMember, error) {
+	var m ports.GroupMember
+	if err := r.db.Get(&m, `SELECT `+groupMemberCols+` FROM group_members WHERE group_id=? AND user_id=? LIMIT 1`,
+		groupID, userID); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *GroupRepo) AddMember(m *ports.GroupMember, tokenHash string) error {
+	if tokenHash == "" {
+		return insertGroupMember(r.db, m)
+	}
+	res, err := r.db.Exec(`
+		INSERT INTO group_members(group_id, name, invite_email, invite_token_hash, invite_expires_at)
+		VALUES (?,?,?,?,?)`, m.GroupID, m.Name, m.InviteEmail, tokenHash, m.InviteExpiresAt)
+	if err != nil {
+		return err
+	}
+	m.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func insertGroupMember(ex sqlx.Execer, m *ports.GroupMember) error {
+	res, err := ex.Exec(`INSERT INTO group_members(group_id, user_id, name) VALUES (?,?,?)`, m.GroupID, m.UserID, m.Name)
+	if err != nil {
+		return err
+	}
+	m.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *GroupRepo) MemberByInvite(tokenHash string) (*ports.GroupMember, error) {
+	var m ports.GroupMember
+	if err := r.db.Get(&m, `SELECT `+groupMemberCols+` FROM group_members
+		WHERE invite_token_hash=? AND user_id IS NULL LIMIT 1`, tokenHash); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *GroupRepo) LinkInvited(memberID, userID int64) error {
+	res, err := r.db.Exec(`
+		UPDATE group_members
+		SET user_id=?, invite_email=NULL, invite_token_hash=NULL, invite_expires_at=NULL
+		WHERE id=? AND user_id IS NULL
+		  AND invite_email=(SELECT email FROM users WHERE id=?)`, userID, memberID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *GroupRepo) AddExpense(e *ports.GroupExpense, t *ports.Transaction) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if t != nil {
+		if err = insertTxRow(tx, t.UserID, t); err != nil {
+			return err
+		}
+		e.TransactionID = &t.ID
+	}
+	res, err := tx.Exec(`
+		INSERT INTO group_expenses(group_id, paid_by, amount, description, split_mode, occurred_at, created_by, transaction_id)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		e.GroupID, e.PaidBy, e.Amount, e.Description, e.SplitMode, e.OccurredAt, e.CreatedBy, e.TransactionID)
+	if err != nil {
+		return err
+	}
+	e.ID, _ = res.LastInsertId()
+	for i := range e.Shares {
+		e.Shares[i].ExpenseID = e.ID
+		if _, err = tx.Exec(`INSERT INTO group_expense_shares(expense_id, member_id, amount) VALUES (?,?,?)`,
+			e.ID, e.Shares[i].MemberID, e.Shares[i].Amount); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *GroupRepo) Expenses(groupID int64) ([]ports.GroupExpense, error) {
+	rows := []ports.GroupExpense{}
+	err := r.db.Select(&rows, `
+		SELECT id, group_id, paid_by, amount, description, split_mode, occurred_at, created_by, transaction_id, created_at
+		FROM group_expenses WHERE group_id=? ORDER BY occurred_at DESC, id DESC`, groupID)
+	return rows, err
+}
+
+func (r *GroupRepo) Shares(groupID int64) ([]ports.GroupShare, error) {
+	rows := []ports.GroupShare{}
+	err := r.db.Select(&rows, `
+		SELECT s.expense_id, s.member_id, s.amount
+		FROM group_expense_shares s
+		JOIN group_expenses e ON e.id = s.expense_id
+		WHERE e.group_id=?`, groupID)
+	return rows, err
+}
+
+func (r *GroupRepo) AddSettlement(s *ports.GroupSettlement, fromTx, toTx *ports.Transaction) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if fromTx != nil {
+		if err = insertTxRow(tx, fromTx.UserID, fromTx); err != nil {
+			return err
+		}
+		s.FromTxID = &fromTx.ID
+	}
+	if toTx != nil {
+		if err = insertTxRow(tx, toTx.UserID, toTx); err != nil {
+			return err
+		}
+		s.ToTxID = &toTx.ID
+	}
+	res, err := tx.Exec(`
+		INSERT INTO group_settlements(group_id, from_member, to_member, amount, settled_at, from_tx_id, to_tx_id, created_by)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		s.GroupID, s.FromMember, s.ToMember, s.Amount, s.SettledAt, s.FromTxID, s.ToTxID, s.CreatedBy)
+	if err != nil {
+		return err
+	}
+	s.ID, _ = res.LastInsertId()
+	return tx.Commit()
+}
+
+func (r *GroupRepo) Settlements(groupID int64) ([]ports.GroupSettlement, error) {
+	rows := []ports.GroupSettlement{}
+	err := r.db.Select(&rows, `
+		SELECT id, group_id, from_member, to_member, amount, settled_at, from_tx_id, to_tx_id, created_by, created_at
+		FROM group_settlements WHERE group_id=? ORDER BY settled_at DESC, id DESC`, groupID)
+	return rows, err
+}
+
+var _ ports.GroupRepo = (*GroupRepo)(nil)
@@ -0,0 +1,251 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+type HouseholdRepo struct{ db *sqlx.DB }
+
+func NewHouseholdRepo(db *sqlx.DB) *HouseholdRepo { return &HouseholdRepo{db: db} }
+
+func (r *HouseholdRepo) Create(ownerID int64, h *ports.Household) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`INSERT INTO households(name, created_by) VALUES (?,?)`, h.Name, ownerID)
+	if err != nil {
+		return err
+	}
+	h.ID, _ = res.LastInsertId()
+	if _, err = tx.Exec(`INSERT INTO household_members(household_id, user_id, role) VALUES (?,?,?)`,
+		h.ID, ownerID, ports.RoleOwner); err != nil {
+		return err
+	}
+	h.CreatedBy, h.Role = ownerID, ports.RoleOwner
+	return tx.Commit()
+}
+
+func (r *HouseholdRepo) ListForUser(userID int64) ([]ports.Household, error) {
+	rows := []ports.Household{}
+	err := r.db.Select(&rows, `
+		SELECT h.id, h.name, h.created_by, h.created_at, m.role
+		FROM households h
+		JOIN household_members m ON m.household_id = h.id
+		WHERE m.user_id=?
+		ORDER BY h.name`, userID)
+	return rows, err
+}
+
+func (r *HouseholdRepo) Get(userID, id int64) (*ports.Household, error) {
+	var h ports.Household
+	if err := r.db.Get(&h, `
+		SELECT h.id, h.name, h.created_by, h.created_at, m.role
+		FROM households h
+		JOIN household_members m ON m.household_id = h.id
+		WHERE h.id=? AND m.user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *HouseholdRepo) Delete(id int64) error {
+	_, err := r.db.ExecContext(context.Background(), `DELETE FROM households WHERE id=?`, id)
+	if err != nil {
+		var me *mysql.MySQLError
+		if errors.As(err, &me) && me.Number == 1451 {
+			return errs.HasWallets
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *HouseholdRepo) Members(householdID int64) ([]ports.HouseholdMember, error) {
+	rows := []ports.HouseholdMember{}
+	err := r.db.Select(&rows, `
+		SELECT m.household_id, m.user_id, u.name, u.email, m.role, m.joined_at
+		FROM household_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.household_id=?
+		ORDER BY m.joined_at ASC`, householdID)
+	return rows, err
+}
+
+func (r *HouseholdRepo) Role(householdID, userID int64) (string, error) {
+	var role string
+	err := r.db.Get(&role, `SELECT role FROM household_members WHERE household_id=? AND user_id=? LIMIT 1`,
+		householdID, userID)
+	return role, err
+}
+
+func (r *HouseholdRepo) SetRole(householdID, userID int64, role string) error {
+	res, err := r.db.ExecContext(context.Background(),
+		`UPDATE household_members SET role=? WHERE household_id=? AND user_id=?`, role, householdID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *HouseholdRepo) RemoveMember(householdID, userID int64) error {
+	res, err := r.db.ExecContext(context.Background(),
+		`DELETE FROM household_members WHERE household_id=? AND user_id=?`, householdID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *HouseholdRepo) CreateInvite(inv *ports.HouseholdInvite, tokenHash string) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO household_invites(household_id, email, role, token_hash, invited_by, expires_at)
+		VALUES (?,?,?,?,?,?)`,
+		inv.HouseholdID, inv.Email, inv.Role, tokenHash, inv.InvitedBy, inv.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	inv.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *HouseholdRepo) InviteByHash(tokenHash string) (*ports.HouseholdInvite, error) {
+	var inv ports.HouseholdInvite
+	if err := r.db.Get(&inv, `
+		SELECT id, household_id, email, role, invited_by, expires_at, accepted_at, created_at
+		FROM household_invites WHERE token_hash=? LIMIT 1`, tokenHash); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *HouseholdRepo) AcceptInvite(inv *ports.HouseholdInvite, userID int64) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// accepted_at IS NULL koşulu aynı davetin iki kez kullanılmasını engeller.
+	res, err := tx.Exec(`
+		UPDATE household_invites SET accepted_at=?, accepted_by=?
+		WHERE id=? AND accepted_at IS NULL`, time.Now().UTC(), userID, inv.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err = tx.Exec(`
+		INSERT INTO household_members(household_id, user_id, role) VALUES (?,?,?)
+		ON DUPLICATE KEY UPDATE role=role`, inv.HouseholdID, userID, inv.Role); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *HouseholdRepo) UserEmail(userID int64) (string, error) {
+	var email string
+	err := r.db.Get(&email, `SELECT email FROM users WHERE id=? LIMIT 1`, userID)
+	return email, err
+}
+
+func (r *HouseholdRepo) Wallets(householdID int64) ([]ports.Wallet, error) {
+	rows := []ports.Wallet{}
+	err := r.db.Select(&rows, `SELECT `+walletCols+` FROM wallets WHERE household_id=? ORDER BY name`, householdID)
+	return rows, err
+}
+
+func (r *HouseholdRepo) Wallet(householdID, walletID int64) (*ports.Wallet, error) {
+	var w ports.Wallet
+	if err := r.db.Get(&w, `SELECT `+walletCols+` FROM wallets WHERE id=? AND household_id=? LIMIT 1`,
+		walletID, householdID); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *HouseholdRepo) WalletBalances(householdID int64) (map[int64]float64, error) {
+	var rows []struct {
+		WalletID int64   `db:"wallet_id"`
+		Balance  float64 `db:"balance"`
+	}
+	if err := r.db.Select(&rows, `
+		SELECT t.wallet_id, SUM(CASE WHEN t.type='income' THEN t.amount ELSE -t.amount END) AS balance
+		FROM transactions t
+		JOIN wallets w ON w.id = t.wallet_id
+		WHERE w.household_id=? AND t.deleted_at IS NULL AND t.status <> 'scheduled'
+		GROUP BY t.wallet_id`, householdID); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]float64, len(rows))
+	for _, r := range rows {
+		out[r.WalletID] = r.Balance
+	}
+	return out, nil
+}
+
+func (r *HouseholdRepo) WalletRole(userID, walletID int64) (string, error) {
+	var role string
+	err := r.db.Get(&role, `
+		SELECT CASE WHEN w.household_id IS NULL THEN 'owner' ELSE m.role END
+		FROM wallets w
+		LEFT JOIN household_members m ON m.household_id = w.household_id AND m.user_id = ?
+		WHERE w.id=? AND ((w.household_id IS NULL AND w.user_id=?) OR m.user_id IS NOT NULL)
+		LIMIT 1`, userID, walletID, userID)
+	return role, err
+}
+
+func (r *HouseholdRepo) WalletTransactions(walletID int64, page, size int) ([]ports.Transaction, int, error) {
+	var rows []ports.Transaction
+	if err := r.db.Select(&rows, `
+		SELECT SQL_CALC_FOUND_ROWS id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
+		FROM transactions
+		WHERE wallet_id=? AND deleted_at IS NULL
+		ORDER BY occurred_at DESC LIMIT ? OFFSET ?`, walletID, size, (page-1)*size); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	_ = r.db.Get(&total, `SELECT FOUND_ROWS()`)
+	return rows, total, nil
+}
+
+func (r *HouseholdRepo) WalletTransaction(walletID, txID int64) (*ports.Transaction, error) {
+	var t ports.Transaction
+	if err := r.db.Get(&t, `
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
+		FROM transactions
+		WHERE id=? AND wallet_id=? AND deleted_at IS NULL LIMIT 1`, txID, walletID); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *HouseholdRepo) SoftDeleteWalletTx(walletID, txID int64) error {
+	_, err := r.db.ExecContext(context.Background(), `
+		UPDATE transactions SET deleted_at=NOW(), updated_at=NOW()
+		WHERE id=? AND wallet_id=? AND status <> 'reconciled'`, txID, walletID)
+	return err
+}
+
+var _ ports.HouseholdRepo = (*HouseholdRepo)(nil)
@@ -0,0 +1,205 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type ImportRepo struct{ db *sqlx.DB }
+
+func NewImportRepo(db *sqlx.DB) *ImportRepo { return &ImportRepo{db: db} }
+
+var _ ports.ImportRepo = (*ImportRepo)(nil)
+
+// importNameMax cüzdan/kategori adı sütun genişliği; yeniden adlandırmada sonek buna sığdırılır.
+const importNameMax = 80
+
+func (r *ImportRepo) Import(userID int64, a *ports.ImportArchive, conflict string, dryRun bool) (ports.ImportResult, error) {
+	res := ports.ImportResult{DryRun: dryRun, SchemaVersion: a.Version, Conflict: conflict}
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return res, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	wallets := map[int64]int64{}
+	merged := map[int64]bool{}
+	for _, w := range a.Wallets {
+		id, how, err := importWallet(tx, userID, w, conflict)
+		if err != nil {
+			return res, err
+		}
+		wallets[w.ID] = id
+		merged[id] = how == "merged"
+		countImport(&res.Wallets, how)
+	}
+
+	cats := map[int64]int64{}
+	pending := a.Categories
+	// Ebeveyn önce eklenmeli; arşiv id sırası ağacın sırasını garanti etmez.
+	for len(pending) > 0 {
+		var next []ports.Category
+		for _, c := range pending {
+			var parent *int64
+			if c.ParentID != nil {
+				p, ok := cats[*c.ParentID]
+				if !ok {
+					next = append(next, c)
+					continue
+				}
+				parent = &p
+			}
+			id, how, err := importCategory(tx, userID, c, parent, conflict)
+			if err != nil {
+				return res, err
+			}
+			cats[c.ID] = id
+			countImport(&res.Categories, how)
+		}
+		if len(next) == len(pending) {
+			return res, errs.ValidationFailed("archive_bad_category_parent")
+		}
+		pending = next
+	}
+
+	for _, t := range a.Transactions {
+		wid, ok := wallets[t.WalletID]
+		if !ok {
+			return res, errs.ValidationFailed("archive_unknown_wallet")
+		}
+		cid, ok := cats[t.CategoryID]
+		if !ok {
+			return res, errs.ValidationFailed("archive_unknown_category")
+		}
+		if merged[wid] {
+			var n int
+			if err := tx.Get(&n, `SELECT COUNT(*) FROM transactions
+				WHERE user_id=? AND wallet_id=? AND type=? AND amount=? AND occurred_at=? AND note <=> ? AND deleted_at IS NULL`,
+				userID, wid, t.Type, t.Amount, t.OccurredAt, t.Note); err != nil {
+				return res, err
+			}
+			if n > 0 {
+				res.Transactions.Skipped++
+				continue
+			}
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO transactions
+			(user_id, created_by, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at, status,
+			 tax_rate, tax_amount, deductible)
+			VALUES (?,?,?,?,?,?,?,?,?,NOW(),?,?,?,COALESCE(?, 0))`,
+			userID, userID, wid, cid, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt, t.Status,
+			t.TaxRate, t.TaxAmount, t.Deductible); err != nil {
+			return res, err
+		}
+		res.Transactions.Created++
+	}
+
+	if dryRun {
+		return res, nil
+	}
+	return res, tx.Commit()
+}
+
+func countImport(c *ports.ImportCounts, how string) {
+	switch how {
+	case "created":
+		c.Created++
+	case "merged":
+		c.Merged++
+	case "renamed":
+		c.Renamed++
+	}
+}
+
+// importWallet uniq_wallet_user_name çakışmasını INSERT sonucundan anlar. Birleştirme yalnız
+// para birimi aynıysa yapılır; aksi halde cüzdan yeniden adlandırılarak eklenir.
+func importWallet(tx *sqlx.Tx, userID int64, w ports.Wallet, conflict string) (int64, string, error) {
+	ins := func(name string) (int64, bool, error) {
+		return insertOrExisting(tx, `
+			INSERT INTO wallets(user_id, name, currency, kind, credit_limit, statement_day, due_day, institution, account_mask, archived_at)
+			VALUES (?,?,?,?,?,?,?,?,?,?)
+			ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id)`,
+			userID, name, w.Currency, w.Kind, w.CreditLimit, w.StatementDay, w.DueDay, w.Institution, w.AccountMask, w.ArchivedAt)
+	}
+	id, created, err := ins(w.Name)
+	if err != nil || created {
+		return id, "created", err
+	}
+	if conflict == ports.ImportMerge {
+		var cur string
+		if err := tx.Get(&cur, `SELECT currency FROM wallets WHERE id=?`, id); err != nil {
+			return 0, "", err
+		}
+		if cur == w.Currency {
+			return id, "merged", nil
+		}
+	}
+	for i := 2; i < 100; i++ {
+		id, created, err := ins(suffixName(w.Name, i))
+		if err != nil || created {
+			return id, "renamed", err
+		}
+	}
+	return 0, "", errs.Conflict
+}
+
+func importCategory(tx *sqlx.Tx, userID int64, c ports.Category, parent *int64, conflict string) (int64, string, error) {
+	ins := func(name string) (int64, bool, error) {
+		return insertOrExisting(tx, `
+			INSERT INTO categories(user_id, parent_id, name, type, sort_order, icon, color, archived_at)
+			VALUES (?,?,?,?,?,?,?,?)
+			ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id)`,
+			userID, parent, name, c.Type, c.SortOrder, c.Icon, c.Color, c.ArchivedAt)
+	}
+	id, created, err := ins(c.Name)
+	if err != nil || created {
+		return id, "created", err
+	}
+	if conflict == ports.ImportMerge {
+		return id, "merged", nil
+	}
+	for i := 2; i < 100; i++ {
+		id, created, err := ins(suffixName(c.Name, i))
+		if err != nil || created {
+			return id, "renamed", err
+		}
+	}
+	return 0, "", errs.Conflict
+}
+
+// insertOrExisting "ON DUPLICATE KEY UPDATE id=LAST_INSERT_ID(id)" ile yazılmış INSERT'i
+// çalıştırır: satır eklendiyse created true, tekil anahtar çakıştıysa mevcut satırın id'si döner.
+func insertOrExisting(tx *sqlx.Tx, q string, args ...any) (int64, bool, error) {
+	res, err := tx.Exec(q, args...)
+	if err != nil {
+		return 0, false, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+	if id == 0 {
+		return 0, false, errors.New("import: no insert id")
+	}
+	return id, n == 1, nil
+}
+
+func suffixName(name string, i int) string {
+	sfx := fmt.Sprintf(" (%d)", i)
+	r := []rune(name)
+	if len(r)+len([]rune(sfx)) > importNameMax {
+		r = r[:importNameMax-len([]rune(sfx))]
+	}
+	return string(r) + sfx
+}
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type InsightRepo struct{ db *sqlx.DB }
+
+func NewInsightRepo(db *sqlx.DB) *InsightRepo { return &InsightRepo{db: db} }
+
+type insightRow struct {
+	ID            int64      `db:"id"`
+	UserID        int64      `db:"user_id"`
+	Kind          string     `db:"kind"`
+	Severity      string     `db:"severity"`
+	Fingerprint   string     `db:"fingerprint"`
+	Title         string     `db:"title"`
+	Period        time.Time  `db:"period"`
+	CategoryID    *int64     `db:"category_id"`
+	TransactionID *int64     `db:"transaction_id"`
+	Currency      *string    `db:"currency"`
+	Data          []byte     `db:"data"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DismissedAt   *time.Time `db:"dismissed_at"`
+}
+
+func (r *InsightRepo) ChangedUsers(monthStart time.Time) ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(&ids, `
+		SELECT t.user_id
+		FROM transactions t
+		LEFT JOIN insight_scans s ON s.user_id = t.user_id
+		WHERE t.type='expense'
+		GROUP BY t.user_id, s.scanned_at
+		HAVING MAX(t.updated_at) > COALESCE(s.scanned_at, '1970-01-01')
+		    OR COALESCE(s.scanned_at, '1970-01-01') < ?
+		ORDER BY t.user_id`, monthStart)
+	return ids, err
+}
+
+func (r *InsightRepo) Expenses(userID int64, since time.Time) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at
+		FROM transactions
+		WHERE user_id=? AND type='expense' AND deleted_at IS NULL AND status <> 'scheduled' AND occurred_at >= ?
+		ORDER BY occurred_at ASC, id ASC`, userID, since)
+	return rows, err
+}
+
+func (r *InsightRepo) Replace(userID int64, items []ports.Insight, scannedAt time.Time) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	fps := make([]string, len(items))
+	for i := range items {
+		fps[i] = items[i].Fingerprint
+	}
+	del := `DELETE FROM insights WHERE user_id=? AND dismissed_at IS NULL`
+	args := []any{userID}
+	if len(fps) > 0 {
+		q, a, err := sqlx.In(` AND fingerprint NOT IN (?)`, fps)
+		if err != nil {
+			return err
+		}
+		del += q
+		args = append(args, a...)
+	}
+	if _, err := tx.Exec(del, args...); err != nil {
+		return err
+	}
+	// Kapatılmış eski bulgular 90 gün sonra temizlenir.
+	if _, err := tx.Exec(`DELETE FROM insights WHERE user_id=? AND dismissed_at < ? - INTERVAL 90 DAY`, userID, scannedAt); err != nil {
+		return err
+	}
+	for _, it := range items {
+		js, _ := json.Marshal(it.Data)
+		if _, err := tx.Exec(`
+			INSERT INTO insights(user_id, kind, severity, fingerprint, title, period, category_id, transaction_id, currency, data)
+			VALUES (?,?,?,?,?,?,?,?,?,?)
+			ON DUPLICATE KEY UPDATE severity=VALUES(severity), title=VALUES(title), data=VALUES(data)`,
+			userID, it.Kind, it.Severity, it.Fingerprint, it.Title, it.Period.Format("2006-01-02"),
+			it.CategoryID, it.TransactionID, nullStr(it.Currency), js); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO insight_scans(user_id, scanned_at) VALUES (?,?)
+		ON DUPLICATE KEY UPDATE scanned_at=VALUES(scanned_at)`, userID, scannedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *InsightRepo) List(userID int64) ([]ports.Insight, error) {
+	var rows []insightRow
+	if err := r.db.Select(&rows, `
+		SELECT id, user_id, kind, severity, fingerprint, title, period, category_id, transaction_id, currency, data,
+		       created_at, dismissed_at
+		FROM insights
+		WHERE user_id=? AND dismissed_at IS NULL
+		ORDER BY FIELD(severity, 'alert', 'warning', 'info'), period DESC, id DESC`, userID); err != nil {
+		return nil, err
+	}
+	out := make([]ports.Insight, len(rows))
+	for i, r := range rows {
+		out[i] = ports.Insight{
+			ID: r.ID, UserID: r.UserID, Kind: r.Kind, Severity: r.Severity, Fingerprint: r.Fingerprint,
+			Title: r.Title, Period: r.Period, CategoryID: r.CategoryID, TransactionID: r.TransactionID,
+			CreatedAt: r.CreatedAt, DismissedAt: r.DismissedAt, Data: map[string]float64{},
+		}
+		if r.Currency != nil {
+			out[i].Currency = *r.Currency
+		}
+		_ = json.Unmarshal(r.Data, &out[i].Data)
+	}
+	return out, nil
+}
+
+func (r *InsightRepo) Dismiss(userID, id int64) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		UPDATE insights SET dismissed_at=UTC_TIMESTAMP() WHERE id=? AND user_id=? AND dismissed_at IS NULL`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+var _ ports.InsightRepo = (*InsightRepo)(nil)
@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type InstallmentRepo struct{ db *sqlx.DB }
+
+func NewInstallmentRepo(db *sqlx.DB) *InstallmentRepo { return &InstallmentRepo{db: db} }
+
+const planCols = `id, user_id, wallet_id, category_id, total_amount, currency, installment_count, first_at,
+	note, remainder, status, created_at, updated_at, closed_at`
+
+func (r *InstallmentRepo) ListPlans(userID int64, status string) ([]ports.InstallmentPlan, error) {
+	rows := []ports.InstallmentPlan{}
+	q := `SELECT ` + planCols + ` FROM installment_plans WHERE user_id=?`
+	args := []any{userID}
+	if status != "" {
+		q += ` AND status=?`
+		args = append(args, status)
+	}
+	q += ` ORDER BY first_at DESC, id DESC`
+	err := r.db.Select(&rows, q, args...)
+	return rows, err
+}
+
+func (r *InstallmentRepo) GetPlan(userID, id int64) (*ports.InstallmentPlan, error) {
+	var p ports.InstallmentPlan
+	if err := r.db.Get(&p, `SELECT `+planCols+` FROM installment_plans WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *InstallmentRepo) Items(userID, planID int64) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no
+		FROM transactions
+		WHERE user_id=? AND installment_plan_id=? AND deleted_at IS NULL
+		ORDER BY installment_no ASC, occurred_at ASC`, userID, planID)
+	return rows, err
+}
+
+func (r *InstallmentRepo) CreatePlan(userID int64, p *ports.InstallmentPlan, items []ports.Transaction) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		INSERT INTO installment_plans
+		(user_id, wallet_id, category_id, total_amount, currency, installment_count, first_at, note, remainder, status)
+		VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		userID, p.WalletID, p.CategoryID, p.TotalAmount, p.Currency, p.Count, p.FirstAt, p.Note, p.Remainder, p.Status)
+	if err != nil {
+		return err
+	}
+	p.ID, _ = res.LastInsertId()
+
+	for i := range items {
+		items[i].InstallmentPlanID = &p.ID
+	}
+	if err = insertPlanItems(tx, userID, items); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *InstallmentRepo) Replace(userID int64, p *ports.InstallmentPlan, removeIDs []int64, add []ports.Transaction) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var locked int64
+	if err = tx.Get(&locked, `SELECT id FROM installment_plans WHERE id=? AND user_id=? FOR UPDATE`, p.ID, userID); err != nil {
+		return err
+	}
+	if len(removeIDs) > 0 {
+		q, args, err := sqlx.In(`
+			UPDATE transactions SET deleted_at=NOW(), updated_at=NOW()
+			WHERE user_id=? AND installment_plan_id=? AND id IN (?) AND status <> 'reconciled'`, userID, p.ID, removeIDs)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(q, args...); err != nil {
+			return err
+		}
+	}
+	for i := range add {
+		add[i].InstallmentPlanID = &p.ID
+	}
+	if err = insertPlanItems(tx, userID, add); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`
+		UPDATE installment_plans SET installment_count=?, status=?, closed_at=?, updated_at=NOW()
+		WHERE id=? AND user_id=?`, p.Count, p.Status, p.ClosedAt, p.ID, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertPlanItems(tx *sqlx.Tx, userID int64, items []ports.Transaction) error {
+	for i := range items {
+		if err := insertTxRow(tx, userID, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ ports.InstallmentRepo = (*InstallmentRepo)(nil)
@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type InvestmentRepo struct{ db *sqlx.DB }
+
+func NewInvestmentRepo(db *sqlx.DB) *InvestmentRepo { return &InvestmentRepo{db: db} }
+
+const (
+	assetCols = `id, user_id, symbol, name, kind, unit, currency, created_at`
+	lotCols   = `id, user_id, wallet_id, asset_id, side, quantity, price, fee, traded_at, note, created_at`
+)
+
+func (r *InvestmentRepo) ListAssets(userID int64) ([]ports.Asset, error) {
+	rows := []ports.Asset{}
+	err := r.db.Select(&rows, `SELECT `+assetCols+` FROM assets WHERE user_id=? ORDER BY symbol`, userID)
+	return rows, err
+}
+
+func (r *InvestmentRepo) GetAsset(userID, id int64) (*ports.Asset, error) {
+	var a ports.Asset
+	if err := r.db.Get(&a, `SELECT `+assetCols+` FROM assets WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *InvestmentRepo) CreateAsset(userID int64, a *ports.Asset) error {
+	res, err := r.db.ExecContext(context.Background(),
+		`INSERT INTO assets(user_id, symbol, name, kind, unit, currency) VALUES (?,?,?,?,?,?)`,
+		userID, a.Symbol, a.Name, a.Kind, a.Unit, a.Currency)
+	if err != nil {
+		return err
+	}
+	a.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *InvestmentRepo) AssetsForRefresh() ([]ports.Asset, error) {
+	rows := []ports.Asset{}
+	err := r.db.Select(&rows, `SELECT `+assetCols+` FROM assets WHERE kind <> 'other' ORDER BY symbol, id`)
+	return rows, err
+}
+
+func (r *InvestmentRepo) ListLots(userID, walletID int64) ([]ports.Lot, error) {
+	q := `SELECT ` + lotCols + ` FROM holding_lots WHERE user_id=?`
+	args := []any{userID}
+	if walletID > 0 {
+		q += ` AND wallet_id=?`
+		args = append(args, walletID)
+	}
+	q += ` ORDER BY traded_at ASC, id ASC`
+	rows := []ports.Lot{}
+	err := r.db.Select(&rows, q, args...)
+	return rows, err
+}
+
+func (r *InvestmentRepo) AddLot(userID int64, l *ports.Lot) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO holding_lots(user_id, wallet_id, asset_id, side, quantity, price, fee, traded_at, note)
+		VALUES (?,?,?,?,?,?,?,?,?)`,
+		userID, l.WalletID, l.AssetID, l.Side, l.Quantity, l.Price, l.Fee, l.TradedAt, l.Note)
+	if err != nil {
+		return err
+	}
+	l.ID, _ = res.LastInsertId()
+	return nil
+}
+
+func (r *InvestmentRepo) UpsertPrice(p *ports.AssetPrice) error {
+	_, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO asset_prices(asset_id, price_date, price, source) VALUES (?,?,?,?)
+		ON DUPLICATE KEY UPDATE price=VALUES(price), source=VALUES(source)`,
+		p.AssetID, p.Date.Format("2006-01-02"), p.Price, p.Source)
+	return err
+}
+
+func (r *InvestmentRepo) Prices(userID, assetID int64, from, to time.Time) ([]ports.AssetPrice, error) {
+	rows := []ports.AssetPrice{}
+	err := r.db.Select(&rows, `
+		SELECT p.asset_id, p.price_date, p.price, p.source
+		FROM asset_prices p
+		JOIN assets a ON a.id = p.asset_id
+		WHERE a.user_id=? AND p.asset_id=? AND p.price_date BETWEEN ? AND ?
+		ORDER BY p.price_date ASC`, userID, assetID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return rows, err
+}
+
+func (r *InvestmentRepo) PriceAt(assetID int64, at time.Time) (*ports.AssetPrice, error) {
+	var p ports.AssetPrice
+	if err := r.db.Get(&p, `
+		SELECT asset_id, price_date, price, source
+		FROM asset_prices WHERE asset_id=? AND price_date <= ?
+		ORDER BY price_date DESC LIMIT 1`, assetID, at.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+var _ ports.InvestmentRepo = (*InvestmentRepo)(nil)
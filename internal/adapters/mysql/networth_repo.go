@@ -0,0 +1,196 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type NetWorthRepo struct{ db *sqlx.DB }
+
+func NewNetWorthRepo(db *sqlx.DB) *NetWorthRepo { return &NetWorthRepo{db: db} }
+
+func (r *NetWorthRepo) UserIDs() ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(&ids, `SELECT id FROM users ORDER BY id`)
+	return ids, err
+}
+
+func (r *NetWorthRepo) BaseCurrency(userID int64) (string, error) {
+	var c string
+	err := r.db.Get(&c, `SELECT base_currency FROM users WHERE id=?`, userID)
+	return c, err
+}
+
+func (r *NetWorthRepo) SetBaseCurrency(userID int64, currency string) error {
+	_, err := r.db.ExecContext(context.Background(),
+		`UPDATE users SET base_currency=? WHERE id=?`, currency, userID)
+	return err
+}
+
+// networthWallets kişisel cüzdanlar ile kullanıcının üyesi olduğu hanelerin cüzdanları.
+const networthWallets = `((w.household_id IS NULL AND w.user_id=?)
+	OR w.household_id IN (SELECT household_id FROM household_members WHERE user_id=?))`
+
+func (r *NetWorthRepo) BalancesBefore(userID int64, before time.Time) ([]ports.WalletBalance, error) {
+	rows := []ports.WalletBalance{}
+	err := r.db.Select(&rows, `
+		SELECT w.id AS wallet_id, w.currency, w.kind,
+		       CASE WHEN w.household_id IS NULL THEN 1
+		            ELSE 1 / (SELECT COUNT(*) FROM household_members hm WHERE hm.household_id = w.household_id)
+		       END AS share,
+		       COALESCE(SUM(CASE WHEN t.type='income' THEN t.amount ELSE -t.amount END), 0) AS balance
+		FROM wallets w
+		LEFT JOIN transactions t
+		       ON t.wallet_id = w.id AND t.deleted_at IS NULL AND t.status <> 'scheduled' AND t.occurred_at < ?
+		WHERE `+networthWallets+`
+		GROUP BY w.id, w.currency, w.kind, w.household_id
+		ORDER BY w.id`, before, userID, userID)
+	return rows, err
+}
+
+func (r *NetWorthRepo) DailyDeltas(userID int64, from, to time.Time) ([]ports.WalletDelta, error) {
+	rows := []ports.WalletDelta{}
+	err := r.db.Select(&rows, `
+		SELECT t.wallet_id, DATE(t.occurred_at) AS day,
+		       SUM(CASE WHEN t.type='income' THEN t.amount ELSE -t.amount END) AS amount
+		FROM transactions t
+		JOIN wallets w ON w.id = t.wallet_id
+		WHERE `+networthWallets+` AND t.deleted_at IS NULL AND t.status <> 'scheduled'
+		  AND t.occurred_at >= ? AND t.occurred_at < ?
+		GROUP BY t.wallet_id, DATE(t.occurred_at)
+		ORDER BY day`, userID, userID, from, to)
+	return rows, err
+}
+
+func (r *NetWorthRepo) ReplaceSnapshots(userID int64, from, to time.Time, rows []ports.NetWorthSnapshot) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM networth_snapshots WHERE user_id=? AND snap_date BETWEEN ? AND ?`,
+		userID, from.Format("2006-01-02"), to.Format("2006-01-02")); err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		if _, err := tx.NamedExec(`
+			INSERT INTO networth_snapshots(user_id, snap_date, wallet_id, currency, balance, base_currency, value, liability)
+			VALUES (:user_id, :snap_date, :wallet_id, :currency, :balance, :base_currency, :value, :liability)`, rows); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *NetWorthRepo) Snapshots(userID int64, from, to time.Time) ([]ports.NetWorthSnapshot, error) {
+	rows := []ports.NetWorthSnapshot{}
+	err := r.db.Select(&rows, `
+		SELECT user_id, snap_date, wallet_id, currency, balance, base_currency, value, liability
+		FROM networth_snapshots
+		WHERE user_id=? AND snap_date BETWEEN ? AND ?
+		ORDER BY snap_date, wallet_id`, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	return rows, err
+}
+
+func (r *NetWorthRepo) FirstSnapshotDate(userID int64) (*time.Time, error) {
+	var d sql.NullTime
+	if err := r.db.Get(&d, `SELECT MIN(snap_date) FROM networth_snapshots WHERE user_id=?`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !d.Valid {
+		return nil, nil
+	}
+	return &d.Time, nil
+}
+
+func (r *NetWorthRepo) MarkStale(userID int64, since time.Time) error {
+	_, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO networth_stale(user_id, since)
+		SELECT u.id, ? FROM (
+			SELECT ? AS id
+			UNION
+			SELECT hm2.user_id FROM household_members hm1
+			JOIN household_members hm2 ON hm2.household_id = hm1.household_id
+			WHERE hm1.user_id=?
+		) u
+		ON DUPLICATE KEY UPDATE since=LEAST(since, VALUES(since))`, since.Format("2006-01-02"), userID, userID)
+	return err
+}
+
+func (r *NetWorthRepo) SaveRates(base string, day time.Time, rates map[string]float64) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	type fxRow struct {
+		Base  string  `db:"base"`
+		Day   string  `db:"rate_date"`
+		Quote string  `db:"quote"`
+		Rate  float64 `db:"rate"`
+	}
+	rows := make([]fxRow, 0, len(rates))
+	for q, v := range rates {
+		if v > 0 {
+			rows = append(rows, fxRow{Base: base, Day: day.Format("2006-01-02"), Quote: q, Rate: v})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := r.db.NamedExec(`
+		INSERT INTO fx_history(base, rate_date, quote, rate) VALUES (:base, :rate_date, :quote, :rate)
+		ON DUPLICATE KEY UPDATE rate=VALUES(rate)`, rows)
+	return err
+}
+
+func (r *NetWorthRepo) RatesBetween(base string, quotes []string, from, to time.Time) ([]ports.FXRate, error) {
+	rows := []ports.FXRate{}
+	if len(quotes) == 0 {
+		return rows, nil
+	}
+	f, t := from.Format("2006-01-02"), to.Format("2006-01-02")
+	q, args, err := sqlx.In(`
+		SELECT h.rate_date, h.quote, h.rate
+		FROM fx_history h
+		WHERE h.base=? AND h.quote IN (?) AND h.rate_date <= ?
+		  AND h.rate_date >= COALESCE((
+		      SELECT MAX(p.rate_date) FROM fx_history p
+		      WHERE p.base=h.base AND p.quote=h.quote AND p.rate_date <= ?), ?)
+		ORDER BY h.rate_date, h.quote`, base, quotes, t, f, f)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&rows, r.db.Rebind(q), args...)
+	return rows, err
+}
+
+func (r *NetWorthRepo) Stale() (map[int64]time.Time, error) {
+	var rows []struct {
+		UserID int64     `db:"user_id"`
+		Since  time.Time `db:"since"`
+	}
+	if err := r.db.Select(&rows, `SELECT user_id, since FROM networth_stale`); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]time.Time, len(rows))
+	for _, s := range rows {
+		out[s.UserID] = s.Since
+	}
+	return out, nil
+}
+
+func (r *NetWorthRepo) ClearStale(userID int64, since time.Time) error {
+	_, err := r.db.ExecContext(context.Background(),
+		`DELETE FROM networth_stale WHERE user_id=? AND since=?`, userID, since.Format("2006-01-02"))
+	return err
+}
+
+var _ ports.NetWorthRepo = (*NetWorthRepo)(nil)
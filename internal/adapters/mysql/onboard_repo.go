@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type OnboardRepo struct{ db *sqlx.DB }
+
+func NewOnboardRepo(db *sqlx.DB) *OnboardRepo { return &OnboardRepo{db: db} }
+
+func (r *OnboardRepo) Locale(userID int64) (string, error) {
+	var l string
+	err := r.db.Get(&l, `SELECT locale FROM users WHERE id=?`, userID)
+	return l, err
+}
+
+func (r *OnboardRepo) Apply(userID int64, tpl ports.OnboardTemplate, initial bool) (ports.SeedResult, error) {
+	res := ports.SeedResult{Locale: tpl.Locale}
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return res, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if initial {
+		if _, err := tx.Exec(`UPDATE users SET locale=?, base_currency=? WHERE id=?`,
+			tpl.Locale, tpl.Currency, userID); err != nil {
+			return res, err
+		}
+	}
+	for _, w := range tpl.Wallets {
+		var id int64
+		err := tx.Get(&id, `SELECT id FROM wallets WHERE user_id=? AND name=? LIMIT 1`, userID, w.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return res, err
+		}
+		if _, err := tx.Exec(`INSERT INTO wallets(user_id, name, currency, kind) VALUES (?,?,?,?)`,
+			userID, w.Name, tpl.Currency, w.Kind); err != nil {
+			return res, err
+		}
+		res.Wallets++
+	}
+	for i, c := range tpl.Categories {
+		if err := seedCategory(tx, userID, nil, c.Type, i, c, &res); err != nil {
+			return res, err
+		}
+	}
+	return res, tx.Commit()
+}
+
+// seedCategory kategoriyi (tür+ad kullanıcı içinde tekildir) yoksa ekler ve alt ağaca iner;
+// mevcut kategori yerinde bırakılır, eksik çocukları onun altına eklenir.
+func seedCategory(tx *sqlx.Tx, userID int64, parentID *int64, typ string, order int, c ports.TemplateCategory, res *ports.SeedResult) error {
+	var id int64
+	err := tx.Get(&id, `SELECT id FROM categories WHERE user_id=? AND type=? AND name=? LIMIT 1`, userID, typ, c.Name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		out, err := tx.Exec(`
+			INSERT INTO categories(user_id, parent_id, name, type, sort_order, icon, color) VALUES (?,?,?,?,?,?,?)`,
+			userID, parentID, c.Name, typ, order, c.Icon, c.Color)
+		if err != nil {
+			return err
+		}
+		id, _ = out.LastInsertId()
+		res.Categories++
+	case err != nil:
+		return err
+	}
+	for i, ch := range c.Children {
+		if err := seedCategory(tx, userID, &id, typ, i, ch, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ ports.OnboardRepo = (*OnboardRepo)(nil)
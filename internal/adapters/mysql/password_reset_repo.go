@@ -0,0 +1,61 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type PasswordResetRepo struct{ db *sqlx.DB }
+
+func NewPasswordResetRepo(db *sqlx.DB) *PasswordResetRepo { return &PasswordResetRepo{db: db} }
+
+var _ ports.PasswordResetRepo = (*PasswordResetRepo)(nil)
+
+func (r *PasswordResetRepo) CreateReset(userID int64, tokenHash string, created, expires time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO password_resets(user_id, token_hash, created_at, expires_at) VALUES (?,?,?,?)`,
+		userID, tokenHash, created, expires)
+	return err
+}
+
+func (r *PasswordResetRepo) RecentResets(userID int64, since time.Time) (int, error) {
+	var n int
+	err := r.db.Get(&n, `SELECT COUNT(*) FROM password_resets WHERE user_id=? AND created_at >= ?`, userID, since)
+	return n, err
+}
+
+func (r *PasswordResetRepo) PeekReset(tokenHash string, now time.Time) (int64, string, error) {
+	var row struct {
+		UserID int64  `db:"user_id"`
+		Email  string `db:"email"`
+	}
+	err := r.db.Get(&row, `
+		SELECT p.user_id, u.email
+		FROM password_resets p JOIN users u ON u.id = p.user_id
+		WHERE p.token_hash=? AND p.used_at IS NULL AND p.expires_at > ?`, tokenHash, now)
+	return row.UserID, row.Email, err
+}
+
+func (r *PasswordResetRepo) CompleteReset(tokenHash, passHash string, now time.Time) (int64, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var uid int64
+	if err := tx.Get(&uid, `SELECT user_id FROM password_resets
+		WHERE token_hash=? AND used_at IS NULL AND expires_at > ? FOR UPDATE`, tokenHash, now); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE users SET pass_hash=? WHERE id=?`, passHash, uid); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE password_resets SET used_at=? WHERE user_id=? AND used_at IS NULL`, now, uid); err != nil {
+		return 0, err
+	}
+	return uid, tx.Commit()
+}
@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReconcileRepo struct{ db *sqlx.DB }
+
+func NewReconcileRepo(db *sqlx.DB) *ReconcileRepo { return &ReconcileRepo{db: db} }
+
+const reconCols = `id, user_id, wallet_id, statement_date, ending_balance, cleared_balance, difference, status, created_at, locked_at`
+
+func (r *ReconcileRepo) Create(userID int64, rc *ports.Reconciliation) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO reconciliations(user_id, wallet_id, statement_date, ending_balance, status)
+		VALUES (?,?,?,?,?)`,
+		userID, rc.WalletID, rc.StatementDate.Format("2006-01-02"), rc.EndingBalance, ports.ReconOpen)
+	if err != nil {
+		return err
+	}
+	rc.ID, _ = res.LastInsertId()
+	rc.UserID, rc.Status = userID, ports.ReconOpen
+	return nil
+}
+
+func (r *ReconcileRepo) Get(userID, id int64) (*ports.Reconciliation, error) {
+	var rc ports.Reconciliation
+	if err := r.db.Get(&rc, `SELECT `+reconCols+` FROM reconciliations WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+func (r *ReconcileRepo) List(userID, walletID int64) ([]ports.Reconciliation, error) {
+	q := `SELECT ` + reconCols + ` FROM reconciliations WHERE user_id=?`
+	args := []any{userID}
+	if walletID > 0 {
+		q += ` AND wallet_id=?`
+		args = append(args, walletID)
+	}
+	q += ` ORDER BY statement_date DESC, id DESC`
+	rows := []ports.Reconciliation{}
+	err := r.db.Select(&rows, q, args...)
+	return rows, err
+}
+
+func (r *ReconcileRepo) Cleared(walletID int64, before time.Time) (ports.ClearedTotals, error) {
+	var out ports.ClearedTotals
+	err := r.db.Get(&out, `
+		SELECT COALESCE(SUM(CASE WHEN status IN ('pending','scheduled') THEN 0
+		                         WHEN type='income' THEN amount ELSE -amount END), 0) AS balance,
+		       COALESCE(SUM(status='cleared'), 0) AS cleared_count,
+		       COALESCE(SUM(status='pending'), 0) AS pending_count
+		FROM transactions
+		WHERE wallet_id=? AND deleted_at IS NULL AND occurred_at < ?`, walletID, before)
+	return out, err
+}
+
+func (r *ReconcileRepo) Lock(rc *ports.Reconciliation, before time.Time) (int64, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Oturum satırını kilitle; aynı oturumun iki kez kilitlenmesini engeller.
+	var status string
+	if err := tx.Get(&status, `SELECT status FROM reconciliations WHERE id=? FOR UPDATE`, rc.ID); err != nil {
+		return 0, err
+	}
+	if status != ports.ReconOpen && status != ports.ReconUnlocked {
+		return 0, errs.Conflict
+	}
+	res, err := tx.Exec(`
+		UPDATE transactions SET status='reconciled', reconciliation_id=?, updated_at=NOW()
+		WHERE wallet_id=? AND deleted_at IS NULL AND status='cleared' AND occurred_at < ?`,
+		rc.ID, rc.WalletID, before)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	if _, err := tx.Exec(`
+		UPDATE reconciliations SET status='locked', cleared_balance=?, difference=?, locked_at=UTC_TIMESTAMP()
+		WHERE id=?`, rc.ClearedBalance, rc.Difference, rc.ID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *ReconcileRepo) Unlock(rc *ports.Reconciliation) (int64, error) {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		UPDATE transactions SET status='cleared', reconciliation_id=NULL, updated_at=NOW()
+		WHERE reconciliation_id=? AND status='reconciled'`, rc.ID)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	if _, err := tx.Exec(`
+		UPDATE reconciliations SET status='unlocked', locked_at=NULL WHERE id=?`, rc.ID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+var _ ports.ReconcileRepo = (*ReconcileRepo)(nil)
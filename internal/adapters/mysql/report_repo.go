@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReportRepo struct{ db *sqlx.DB }
+
+func NewReportRepo(db *sqlx.DB) *ReportRepo { return &ReportRepo{db: db} }
+
+func (r *ReportRepo) TaxRows(userID int64, from, to time.Time) ([]ports.TaxRow, error) {
+	rows := []ports.TaxRow{}
+	err := r.db.Select(&rows, `
+		SELECT MONTH(occurred_at) AS month, currency, type, tax_rate AS rate, deductible,
+		       SUM(amount) AS total, COALESCE(SUM(tax_amount), 0) AS tax, COUNT(*) AS cnt
+		FROM transactions
+		WHERE user_id=? AND deleted_at IS NULL AND status <> 'scheduled' AND occurred_at >= ? AND occurred_at < ?
+		GROUP BY MONTH(occurred_at), currency, type, tax_rate, deductible
+		ORDER BY currency, month, type, rate`, userID, from, to)
+	return rows, err
+}
+
+var _ ports.ReportRepo = (*ReportRepo)(nil)
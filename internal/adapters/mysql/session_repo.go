@@ -106,3 +106,13 @@ func (r *SessionRepo) RevokeSession(userID, sessionID int64) error {
 	_, err := r.db.Exec(`DELETE FROM sessions WHERE id=? AND user_id=?`, sessionID, userID)
 	return err
 }
+
+func (r *SessionRepo) RevokeAll(userID int64) error {
+	_, err := r.db.Exec(`DELETE FROM sessions WHERE user_id=?`, userID)
+	return err
+}
+
+func (r *SessionRepo) RevokeOthers(userID int64, keepHash string) error {
+	_, err := r.db.Exec(`DELETE FROM sessions WHERE user_id=? AND refresh_hash<>?`, userID, keepHash)
+	return err
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type SubscriptionRepo struct{ db *sqlx.DB }
+
+func NewSubscriptionRepo(db *sqlx.DB) *SubscriptionRepo { return &SubscriptionRepo{db: db} }
+
+const subCols = `user_id, sub_key, name, currency, cadence, wallet_id, category_id, charges, avg_amount,
+	last_amount, last_charge, next_expected, annualized, status, prev_amount, changed_at`
+
+func (r *SubscriptionRepo) ChangedUsers() ([]int64, error) {
+	ids := []int64{}
+	err := r.db.Select(&ids, `
+		SELECT t.user_id
+		FROM transactions t
+		LEFT JOIN subscription_scans s ON s.user_id = t.user_id
+		WHERE t.type='expense'
+		GROUP BY t.user_id, s.scanned_at
+		HAVING MAX(t.updated_at) > COALESCE(s.scanned_at, '1970-01-01')
+		ORDER BY t.user_id`)
+	return ids, err
+}
+
+func (r *SubscriptionRepo) Expenses(userID int64, since time.Time) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at
+		FROM transactions
+		WHERE user_id=? AND type='expense' AND deleted_at IS NULL AND status <> 'scheduled'
+		  AND note IS NOT NULL AND note <> '' AND occurred_at >= ?
+		ORDER BY occurred_at ASC, id ASC`, userID, since)
+	return rows, err
+}
+
+func (r *SubscriptionRepo) Replace(userID int64, subs []ports.Subscription, scannedAt time.Time) error {
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM detected_subscriptions WHERE user_id=?`, userID); err != nil {
+		return err
+	}
+	if len(subs) > 0 {
+		for i := range subs {
+			subs[i].UserID = userID
+		}
+		if _, err := tx.NamedExec(`
+			INSERT INTO detected_subscriptions(`+subCols+`)
+			VALUES (:user_id, :sub_key, :name, :currency, :cadence, :wallet_id, :category_id, :charges, :avg_amount,
+			        :last_amount, :last_charge, :next_expected, :annualized, :status, :prev_amount, :changed_at)`, subs); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO subscription_scans(user_id, scanned_at) VALUES (?,?)
+		ON DUPLICATE KEY UPDATE scanned_at=VALUES(scanned_at)`, userID, scannedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SubscriptionRepo) List(userID int64) ([]ports.Subscription, error) {
+	rows := []ports.Subscription{}
+	err := r.db.Select(&rows, `
+		SELECT `+subCols+`
+		FROM detected_subscriptions
+		WHERE user_id=?
+		ORDER BY status ASC, annualized DESC`, userID)
+	return rows, err
+}
+
+var _ ports.SubscriptionRepo = (*SubscriptionRepo)(nil)
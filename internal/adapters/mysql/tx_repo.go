@@ -1,10 +1,14 @@
 package mysql
 
 import (
+	"database/sql"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/Veysel440/finance-master-api/internal/errs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/txquery"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -13,57 +17,59 @@ type TxRepo struct{ db *sqlx.DB }
 func NewTxRepo(db *sqlx.DB) *TxRepo { return &TxRepo{db: db} }
 
 func (r *TxRepo) List(userID int64, page, size int, q string) ([]ports.Transaction, int, error) {
-	off := (page - 1) * size
-	baseQ := `
-SELECT SQL_CALC_FOUND_ROWS id,user_id,wallet_id,category_id,type,amount,currency,note,occurred_at,updated_at
-FROM transactions
-WHERE user_id=? AND deleted_at IS NULL`
-	args := []any{userID}
-
-	if s := strings.TrimSpace(q); s != "" {
-		if strings.HasPrefix(strings.ToLower(s), "ft:") {
-			term := strings.TrimSpace(s[3:])
-			if term != "" {
-				baseQ += ` AND MATCH(note) AGAINST (? IN NATURAL LANGUAGE MODE)`
-				args = append(args, term)
-			}
-		} else {
-			baseQ += ` AND note LIKE ?`
-			args = append(args, "%"+s+"%")
-		}
-	}
+	return r.list(walletScope+` AND deleted_at IS NULL`, []any{userID, userID}, q, page, size)
+}
 
-	baseQ += ` ORDER BY occurred_at DESC LIMIT ? OFFSET ?`
-	args = append(args, size, off)
+func (r *TxRepo) ListRange(userID int64, from, to time.Time, q string, page, size int) ([]ports.Transaction, int, error) {
+	return r.list(walletScope+` AND deleted_at IS NULL AND occurred_at BETWEEN ? AND ?`,
+		[]any{userID, userID, from, to}, q, page, size)
+}
 
-	var rows []ports.Transaction
-	if err := r.db.Select(&rows, baseQ, args...); err != nil {
+func (r *TxRepo) ListWalletRange(walletID int64, from, to time.Time) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
+		FROM transactions
+		WHERE wallet_id=? AND deleted_at IS NULL AND occurred_at >= ? AND occurred_at < ?`+notScheduled(false)+`
+		ORDER BY occurred_at ASC, id ASC`, walletID, from, to)
+	return rows, err
+}
+
+func (r *TxRepo) WalletBalanceBefore(walletID int64, before time.Time) (float64, error) {
+	var bal float64
+	err := r.db.Get(&bal, `
+		SELECT COALESCE(SUM(CASE WHEN type='income' THEN amount ELSE -amount END), 0)
+		FROM transactions
+		WHERE wallet_id=? AND deleted_at IS NULL AND occurred_at < ?`+notScheduled(false), walletID, before)
+	return bal, err
+}
+
+func (r *TxRepo) ListFilter(userID int64, f ports.TxFilter, page, size int) ([]ports.Transaction, int, error) {
+	where, args, err := filterWhere(userID, f)
+	if err != nil {
 		return nil, 0, err
 	}
-	var total int
-	_ = r.db.Get(&total, `SELECT FOUND_ROWS()`)
-	return rows, total, nil
+	return r.list(where, args, f.Query, page, size)
 }
 
-func (r *TxRepo) ListRange(userID int64, from, to time.Time, q string, page, size int) ([]ports.Transaction, int, error) {
+// list List/ListRange/ListFilter'ın ortak gövdesi: where'e arama koşulunu ekler ve sayfalar.
+func (r *TxRepo) list(where string, args []any, q string, page, size int) ([]ports.Transaction, int, error) {
 	off := (page - 1) * size
 	baseQ := `
-SELECT SQL_CALC_FOUND_ROWS id,user_id,wallet_id,category_id,type,amount,currency,note,occurred_at,updated_at
+SELECT SQL_CALC_FOUND_ROWS id,user_id,created_by,wallet_id,category_id,type,amount,currency,note,occurred_at,updated_at,
+       installment_plan_id,installment_no,status,reconciliation_id,tax_rate,tax_amount,deductible
 FROM transactions
-WHERE user_id=? AND deleted_at IS NULL AND occurred_at BETWEEN ? AND ?`
-	args := []any{userID, from, to}
-
-	if s := strings.TrimSpace(q); s != "" {
-		if strings.HasPrefix(strings.ToLower(s), "ft:") {
-			term := strings.TrimSpace(s[3:])
-			if term != "" {
-				baseQ += ` AND MATCH(note) AGAINST (? IN NATURAL LANGUAGE MODE)`
-				args = append(args, term)
-			}
-		} else {
-			baseQ += ` AND note LIKE ?`
-			args = append(args, "%"+s+"%")
-		}
+WHERE ` + where
+
+	cond, qargs, err := searchCond(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cond != "" {
+		baseQ += ` AND ` + cond
+		args = append(args, qargs...)
 	}
 
 	baseQ += ` ORDER BY occurred_at DESC LIMIT ? OFFSET ?`
@@ -78,37 +84,74 @@ WHERE user_id=? AND deleted_at IS NULL AND occurred_at BETWEEN ? AND ?`
 	return rows, total, nil
 }
 
+// filterWhere görünüm süzgecini koşula çevirir; kategori seti tüm alt seviyeleri de içerir.
+func filterWhere(userID int64, f ports.TxFilter) (string, []any, error) {
+	where := walletScope + ` AND deleted_at IS NULL AND occurred_at >= ? AND occurred_at < ?`
+	args := []any{userID, userID, f.From, f.To}
+	if len(f.WalletIDs) > 0 {
+		q, a, err := sqlx.In(` AND wallet_id IN (?)`, f.WalletIDs)
+		if err != nil {
+			return "", nil, err
+		}
+		where += q
+		args = append(args, a...)
+	}
+	if len(f.CategoryIDs) > 0 {
+		q, a, err := sqlx.In(` AND category_id IN (WITH RECURSIVE sub(id) AS (
+				SELECT id FROM categories WHERE id IN (?)
+				UNION
+				SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id)
+			SELECT id FROM sub)`, f.CategoryIDs)
+		if err != nil {
+			return "", nil, err
+		}
+		where += q
+		args = append(args, a...)
+	}
+	return where, args, nil
+}
+
 func (r *TxRepo) GetSince(userID int64, since time.Time) ([]ports.Transaction, error) {
 	rows := []ports.Transaction{}
 	err := r.db.Select(&rows, `
-		SELECT id, user_id, wallet_id, category_id, type, amount, currency, note,
-		       occurred_at, updated_at, deleted_at
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
 		FROM transactions
-		WHERE user_id=? AND (updated_at > ? OR (deleted_at IS NOT NULL AND deleted_at > ?))
-		ORDER BY updated_at ASC`, userID, since, since)
+		WHERE `+walletScope+` AND (updated_at > ? OR (deleted_at IS NOT NULL AND deleted_at > ?))
+		ORDER BY updated_at ASC`, userID, userID, since, since)
 	return rows, err
 }
 
 func (r *TxRepo) Create(userID int64, t *ports.Transaction) error {
 	res, err := r.db.Exec(`
 		INSERT INTO transactions
-		(user_id, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at)
-		VALUES (?,?,?,?,?,?,?,?,NOW())`,
-		userID, t.WalletID, t.CategoryID, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt)
+		(user_id, created_by, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at, status,
+		 tax_rate, tax_amount, deductible)
+		VALUES (?,?,?,?,?,?,?,?,?,NOW(),COALESCE(?, 'pending'),?,?,COALESCE(?, 0))`,
+		userID, userID, t.WalletID, t.CategoryID, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt, nullStr(t.Status),
+		t.TaxRate, t.TaxAmount, t.Deductible)
 	if err != nil {
 		return err
 	}
 	id, _ := res.LastInsertId()
 	t.ID = id
+	if t.Status == "" {
+		t.Status = ports.TxPending
+	}
+	t.UserID, t.CreatedBy = userID, &userID
 	return nil
 }
 
 func (r *TxRepo) Update(userID int64, t *ports.Transaction) error {
 	_, err := r.db.Exec(`
 		UPDATE transactions
-		SET wallet_id=?, category_id=?, type=?, amount=?, currency=?, note=?, occurred_at=?, updated_at=NOW()
-		WHERE id=? AND user_id=?`,
-		t.WalletID, t.CategoryID, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt, t.ID, userID)
+		SET wallet_id=?, category_id=?, type=?, amount=?, currency=?, note=?, occurred_at=?, status=COALESCE(?, status),
+		    tax_rate=COALESCE(?, tax_rate), tax_amount=COALESCE(?, tax_amount), deductible=COALESCE(?, deductible),
+		    updated_at=NOW()
+		WHERE id=? AND `+walletScope+` AND status <> 'reconciled'`,
+		t.WalletID, t.CategoryID, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt, nullStr(t.Status),
+		t.TaxRate, t.TaxAmount, t.Deductible, t.ID, userID, userID)
 	return err
 }
 
@@ -116,7 +159,7 @@ func (r *TxRepo) SoftDelete(userID int64, id int64) error {
 	_, err := r.db.Exec(`
 		UPDATE transactions
 		SET deleted_at=NOW(), updated_at=NOW()
-		WHERE id=? AND user_id=?`, id, userID)
+		WHERE id=? AND `+walletScope+` AND status <> 'reconciled'`, id, userID, userID)
 	return err
 }
 
@@ -125,17 +168,13 @@ func (r *TxRepo) UpsertBatch(userID int64, items []ports.Transaction) error {
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		} else {
-			_ = tx.Commit()
-		}
-	}()
+	defer func() { _ = tx.Rollback() }()
 
+	// Durum ya da vergi alanları gönderilmezse yeni kayıt varsayılanı alır, mevcut kaydınki korunur.
 	const ins = `INSERT INTO transactions
-		(id, user_id, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at, deleted_at)
-		VALUES (?,?,?,?,?,?,?,?,?,?,?)
+		(id, user_id, created_by, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at, deleted_at, status,
+		 tax_rate, tax_amount, deductible)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,COALESCE(?, 'pending'),?,?,COALESCE(?, 0))
 		ON DUPLICATE KEY UPDATE
 		  wallet_id=VALUES(wallet_id),
 		  category_id=VALUES(category_id),
@@ -145,51 +184,213 @@ func (r *TxRepo) UpsertBatch(userID int64, items []ports.Transaction) error {
 		  note=VALUES(note),
 		  occurred_at=VALUES(occurred_at),
 		  updated_at=VALUES(updated_at),
-		  deleted_at=VALUES(deleted_at)`
+		  deleted_at=VALUES(deleted_at),
+		  tax_rate=COALESCE(?, tax_rate),
+		  tax_amount=COALESCE(?, tax_amount),
+		  deductible=COALESCE(?, deductible),
+		  status=COALESCE(?, status)`
 
 	for i := range items {
 		it := items[i]
+		if it.ID > 0 {
+			// Mevcut satır kilitlenir: mutabık kayıt reddedilir, erişilemeyen cüzdandaki
+			// kayıt yokmuş gibi davranılır (id tahminiyle başkasının kaydı ezilemez).
+			var cur struct {
+				Status  string `db:"status"`
+				Visible bool   `db:"visible"`
+			}
+			err := tx.Get(&cur, `SELECT status, `+walletScope+` AS visible FROM transactions WHERE id=? FOR UPDATE`,
+				userID, userID, it.ID)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+			case err != nil:
+				return err
+			case !cur.Visible:
+				return errs.NotFound
+			case cur.Status == ports.TxReconciled:
+				return errs.TxLocked
+			}
+		}
 		if it.UpdatedAt.IsZero() {
 			it.UpdatedAt = time.Now()
 		}
-		_, err = tx.Exec(ins,
-			nullID(it.ID), userID, it.WalletID, it.CategoryID, it.Type, it.Amount, it.Currency, it.Note,
-			it.OccurredAt, it.UpdatedAt, it.DeletedAt)
-		if err != nil {
+		if _, err := tx.Exec(ins,
+			nullID(it.ID), userID, userID, it.WalletID, it.CategoryID, it.Type, it.Amount, it.Currency, it.Note,
+			it.OccurredAt, it.UpdatedAt, it.DeletedAt, nullStr(it.Status), it.TaxRate, it.TaxAmount, it.Deductible,
+			it.TaxRate, it.TaxAmount, it.Deductible, nullStr(it.Status)); err != nil {
 			return err
 		}
 	}
-	return nil
+	return tx.Commit()
+}
+
+// notScheduled özet sorgularında planlanmış işlemleri dışlayan koşul.
+func notScheduled(include bool) string {
+	if include {
+		return ""
+	}
+	return ` AND status <> 'scheduled'`
 }
 
-func (r *TxRepo) Summary(userID int64, from, to time.Time) ([]ports.TxSummary, error) {
+func (r *TxRepo) Summary(userID int64, from, to time.Time, includeScheduled bool) ([]ports.TxSummary, error) {
 	rows := []ports.TxSummary{}
 	err := r.db.Select(&rows, `
 		SELECT DATE(occurred_at) AS date, type, SUM(amount) AS total
 		FROM transactions
-		WHERE user_id=? AND deleted_at IS NULL AND occurred_at >= ? AND occurred_at < ?
+		WHERE user_id=? AND deleted_at IS NULL AND occurred_at >= ? AND occurred_at < ?`+notScheduled(includeScheduled)+`
 		GROUP BY DATE(occurred_at), type
 		ORDER BY DATE(occurred_at) ASC, type ASC`, userID, from, to)
 	return rows, err
 }
 
+func (r *TxRepo) SummaryByCategory(userID int64, from, to time.Time, includeScheduled bool) ([]ports.CategoryTotal, error) {
+	rows := []ports.CategoryTotal{}
+	err := r.db.Select(&rows, `
+		SELECT category_id, type, SUM(amount) AS total
+		FROM transactions
+		WHERE user_id=? AND deleted_at IS NULL AND occurred_at >= ? AND occurred_at < ?`+notScheduled(includeScheduled)+`
+		GROUP BY category_id, type
+		ORDER BY category_id ASC`, userID, from, to)
+	return rows, err
+}
+
+func (r *TxRepo) SummaryByCategoryFilter(userID int64, f ports.TxFilter) ([]ports.CategoryTotal, error) {
+	where, args, err := filterWhere(userID, f)
+	if err != nil {
+		return nil, err
+	}
+	cond, qargs, err := searchCond(f.Query)
+	if err != nil {
+		return nil, err
+	}
+	if cond != "" {
+		where += ` AND ` + cond
+		args = append(args, qargs...)
+	}
+	rows := []ports.CategoryTotal{}
+	err = r.db.Select(&rows, `
+		SELECT category_id, type, SUM(amount) AS total
+		FROM transactions
+		WHERE `+where+notScheduled(false)+`
+		GROUP BY category_id, type
+		ORDER BY category_id ASC`, args...)
+	return rows, err
+}
+
 func (r *TxRepo) GetOne(userID, id int64) (*ports.Transaction, error) {
 	var t ports.Transaction
 	err := r.db.Get(&t, `
-		SELECT id, user_id, wallet_id, category_id, type, amount, currency, note,
-		       occurred_at, updated_at, deleted_at
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
 		FROM transactions
-		WHERE id=? AND user_id=? AND deleted_at IS NULL
-		LIMIT 1`, id, userID)
+		WHERE id=? AND `+walletScope+` AND deleted_at IS NULL
+		LIMIT 1`, id, userID, userID)
 	if err != nil {
 		return nil, err
 	}
 	return &t, nil
 }
 
+// insertTxRow başka bir repo'nun transaction'ı içinde işlem satırı ekler. Durumu servis belirler
+// (ileri tarihli satır scheduled); boşsa pending yazılır.
+func insertTxRow(ex sqlx.Execer, userID int64, t *ports.Transaction) error {
+	res, err := ex.Exec(`
+		INSERT INTO transactions
+		(user_id, created_by, wallet_id, category_id, type, amount, currency, note, occurred_at, updated_at,
+		 installment_plan_id, installment_no, status)
+		VALUES (?,?,?,?,?,?,?,?,?,NOW(),?,?,COALESCE(?, 'pending'))`,
+		userID, userID, t.WalletID, t.CategoryID, t.Type, t.Amount, t.Currency, t.Note, t.OccurredAt,
+		t.InstallmentPlanID, t.InstallmentNo, nullStr(t.Status))
+	if err != nil {
+		return err
+	}
+	t.ID, _ = res.LastInsertId()
+	t.UserID, t.CreatedBy = userID, &userID
+	return nil
+}
+
+func (r *TxRepo) SetStatus(userID int64, ids []int64, status string) (int64, error) {
+	q, args, err := sqlx.In(`
+		UPDATE transactions SET status=?, updated_at=NOW()
+		WHERE user_id=? AND id IN (?) AND deleted_at IS NULL AND status <> 'reconciled'`, status, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.db.Exec(q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// searchCond q parametresini SQL koşuluna çevirir: "ft:" öneki fulltext aramadır,
+// diğer her şey txquery dilidir.
+func searchCond(q string) (string, []any, error) {
+	s := strings.TrimSpace(q)
+	if s == "" {
+		return "", nil, nil
+	}
+	if strings.HasPrefix(strings.ToLower(s), "ft:") {
+		term := strings.TrimSpace(s[3:])
+		if term == "" {
+			return "", nil, nil
+		}
+		return `MATCH(note) AGAINST (? IN NATURAL LANGUAGE MODE)`, []any{term}, nil
+	}
+	return txquery.Build(s)
+}
+
+func nullStr(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 func nullID(id int64) any {
 	if id > 0 {
 		return id
 	}
 	return nil
 }
+
+func (r *TxRepo) Upcoming(userID int64, from, to time.Time) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, created_by, wallet_id, category_id, type, amount, currency, note,
+		       occurred_at, updated_at, deleted_at, installment_plan_id, installment_no,
+		       status, reconciliation_id, tax_rate, tax_amount, deductible
+		FROM transactions
+		WHERE user_id=? AND deleted_at IS NULL AND status='scheduled' AND occurred_at >= ? AND occurred_at < ?
+		ORDER BY occurred_at ASC, id ASC`, userID, from, to)
+	return rows, err
+}
+
+func (r *TxRepo) DueScheduled(now time.Time, limit int) ([]ports.Transaction, error) {
+	rows := []ports.Transaction{}
+	err := r.db.Select(&rows, `
+		SELECT id, user_id, wallet_id, category_id, type, amount, currency, occurred_at, updated_at, status
+		FROM transactions
+		WHERE status='scheduled' AND deleted_at IS NULL AND occurred_at <= ?
+		ORDER BY occurred_at ASC, id ASC
+		LIMIT ?`, now, limit)
+	return rows, err
+}
+
+func (r *TxRepo) PostScheduled(ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	q, args, err := sqlx.In(`
+		UPDATE transactions SET status='pending', updated_at=NOW()
+		WHERE id IN (?) AND status='scheduled' AND deleted_at IS NULL`, ids)
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.db.Exec(q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,117 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/jmoiron/sqlx"
+)
+
+type ViewRepo struct{ db *sqlx.DB }
+
+func NewViewRepo(db *sqlx.DB) *ViewRepo { return &ViewRepo{db: db} }
+
+type viewRow struct {
+	ID          int64      `db:"id"`
+	UserID      int64      `db:"user_id"`
+	Name        string     `db:"name"`
+	Range       string     `db:"date_range"`
+	From        *time.Time `db:"from_date"`
+	To          *time.Time `db:"to_date"`
+	WalletIDs   []byte     `db:"wallet_ids"`
+	CategoryIDs []byte     `db:"category_ids"`
+	Query       string     `db:"query"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+const viewCols = `id, user_id, name, date_range, from_date, to_date, wallet_ids, category_ids, query, updated_at`
+
+func (v viewRow) toView() ports.SavedView {
+	out := ports.SavedView{
+		ID: v.ID, UserID: v.UserID, Name: v.Name, Range: v.Range, From: v.From, To: v.To,
+		Query: v.Query, UpdatedAt: v.UpdatedAt, WalletIDs: []int64{}, CategoryIDs: []int64{},
+	}
+	if len(v.WalletIDs) > 0 {
+		_ = json.Unmarshal(v.WalletIDs, &out.WalletIDs)
+	}
+	if len(v.CategoryIDs) > 0 {
+		_ = json.Unmarshal(v.CategoryIDs, &out.CategoryIDs)
+	}
+	return out
+}
+
+func idsJSON(ids []int64) []byte {
+	if ids == nil {
+		ids = []int64{}
+	}
+	js, _ := json.Marshal(ids)
+	return js
+}
+
+func dateOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+func (r *ViewRepo) List(userID int64) ([]ports.SavedView, error) {
+	var rows []viewRow
+	if err := r.db.Select(&rows, `SELECT `+viewCols+` FROM saved_views WHERE user_id=? ORDER BY name`, userID); err != nil {
+		return nil, err
+	}
+	out := make([]ports.SavedView, len(rows))
+	for i := range rows {
+		out[i] = rows[i].toView()
+	}
+	return out, nil
+}
+
+func (r *ViewRepo) Get(userID, id int64) (*ports.SavedView, error) {
+	var row viewRow
+	if err := r.db.Get(&row, `SELECT `+viewCols+` FROM saved_views WHERE id=? AND user_id=? LIMIT 1`, id, userID); err != nil {
+		return nil, err
+	}
+	v := row.toView()
+	return &v, nil
+}
+
+func (r *ViewRepo) Create(userID int64, v *ports.SavedView) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO saved_views(user_id, name, date_range, from_date, to_date, wallet_ids, category_ids, query)
+		VALUES (?,?,?,?,?,?,?,?)`,
+		userID, v.Name, v.Range, dateOrNil(v.From), dateOrNil(v.To), idsJSON(v.WalletIDs), idsJSON(v.CategoryIDs), v.Query)
+	if err != nil {
+		return err
+	}
+	v.ID, _ = res.LastInsertId()
+	v.UserID = userID
+	return nil
+}
+
+func (r *ViewRepo) Update(userID int64, v *ports.SavedView) error {
+	res, err := r.db.ExecContext(context.Background(), `
+		UPDATE saved_views
+		SET name=?, date_range=?, from_date=?, to_date=?, wallet_ids=?, category_ids=?, query=?
+		WHERE id=? AND user_id=?`,
+		v.Name, v.Range, dateOrNil(v.From), dateOrNil(v.To), idsJSON(v.WalletIDs), idsJSON(v.CategoryIDs), v.Query,
+		v.ID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err := r.Get(userID, v.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ViewRepo) Delete(userID, id int64) error {
+	_, err := r.db.ExecContext(context.Background(), `DELETE FROM saved_views WHERE id=? AND user_id=?`, id, userID)
+	return err
+}
+
+var _ ports.ViewRepo = (*ViewRepo)(nil)
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"github.com/Veysel440/finance-master-api/internal/errs"
@@ -14,21 +15,39 @@ type WalletRepo struct{ db *sqlx.DB }
 
 func NewWalletRepo(db *sqlx.DB) *WalletRepo { return &WalletRepo{db: db} }
 
+const walletCols = `id, user_id, household_id, name, currency, kind, credit_limit, statement_day, due_day, institution, account_mask, archived_at, updated_at`
+
+// walletAccess kullanıcının kişisel cüzdanları ile üyesi olduğu hanelerin cüzdanlarını seçen
+// koşul; userID iki kez bağlanır. Rol kontrolü serviste (WalletRole) yapılır.
+const walletAccess = `((household_id IS NULL AND user_id=?) OR household_id IN (SELECT household_id FROM household_members WHERE user_id=?))`
+
+// walletScope işlem sorgularında wallet_id'yi erişilebilir cüzdanlarla sınırlar.
+const walletScope = `wallet_id IN (SELECT id FROM wallets WHERE ` + walletAccess + `)`
+
 func (r *WalletRepo) List(userID int64) ([]ports.Wallet, error) {
 	var rows []ports.Wallet
 	if err := r.db.Select(&rows,
-		`SELECT id, user_id, name, currency FROM wallets WHERE user_id=? ORDER BY name`,
-		userID,
+		`SELECT `+walletCols+` FROM wallets WHERE `+walletAccess+` ORDER BY name`,
+		userID, userID,
 	); err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
 
+func (r *WalletRepo) Get(userID, id int64) (*ports.Wallet, error) {
+	var w ports.Wallet
+	if err := r.db.Get(&w, `SELECT `+walletCols+` FROM wallets WHERE id=? AND `+walletAccess+` LIMIT 1`, id, userID, userID); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
 func (r *WalletRepo) Create(userID int64, w *ports.Wallet) error {
 	res, err := r.db.ExecContext(context.Background(),
-		`INSERT INTO wallets(user_id, name, currency) VALUES (?,?,?)`,
-		userID, w.Name, w.Currency,
+		`INSERT INTO wallets(user_id, household_id, name, currency, kind, credit_limit, statement_day, due_day, institution, account_mask)
+		 VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		userID, w.HouseholdID, w.Name, w.Currency, w.Kind, w.CreditLimit, w.StatementDay, w.DueDay, w.Institution, w.AccountMask,
 	)
 	if err != nil {
 		return err
@@ -40,15 +59,51 @@ func (r *WalletRepo) Create(userID int64, w *ports.Wallet) error {
 
 func (r *WalletRepo) Update(userID int64, w *ports.Wallet) error {
 	_, err := r.db.ExecContext(context.Background(),
-		`UPDATE wallets SET name=?, currency=? WHERE id=? AND user_id=?`,
-		w.Name, w.Currency, w.ID, userID,
+		`UPDATE wallets SET name=?, currency=?, kind=?, credit_limit=?, statement_day=?, due_day=?,
+		 institution=?, account_mask=?, updated_at=NOW()
+		 WHERE id=? AND `+walletAccess,
+		w.Name, w.Currency, w.Kind, w.CreditLimit, w.StatementDay, w.DueDay, w.Institution, w.AccountMask, w.ID, userID, userID,
 	)
 	return err
 }
 
+func (r *WalletRepo) SetArchived(userID, id int64, archived bool) error {
+	q := `UPDATE wallets SET archived_at=NULL, updated_at=NOW() WHERE id=? AND ` + walletAccess
+	if archived {
+		q = `UPDATE wallets SET archived_at=COALESCE(archived_at, NOW()), updated_at=NOW() WHERE id=? AND ` + walletAccess
+	}
+	res, err := r.db.ExecContext(context.Background(), q, id, userID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *WalletRepo) Balances(userID int64) (map[int64]float64, error) {
+	var rows []struct {
+		WalletID int64   `db:"wallet_id"`
+		Balance  float64 `db:"balance"`
+	}
+	if err := r.db.Select(&rows, `
+		SELECT wallet_id, SUM(CASE WHEN type='income' THEN amount ELSE -amount END) AS balance
+		FROM transactions
+		WHERE `+walletScope+` AND deleted_at IS NULL AND status <> 'scheduled'
+		GROUP BY wallet_id`, userID, userID); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]float64, len(rows))
+	for _, r := range rows {
+		out[r.WalletID] = r.Balance
+	}
+	return out, nil
+}
+
 func (r *WalletRepo) Delete(userID, id int64) error {
 	_, err := r.db.ExecContext(context.Background(),
-		`DELETE FROM wallets WHERE id=? AND user_id=?`, id, userID,
+		`DELETE FROM wallets WHERE id=? AND `+walletAccess, id, userID, userID,
 	)
 	if err != nil {
 		var me *mysql.MySQLError
@@ -0,0 +1,41 @@
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPClient basit bir fiyat servisinden anlık fiyat çeker:
+// GET {BaseURL}?symbol=XAU -> {"symbol":"XAU","price":2450.1,"currency":"TRY","date":"2026-10-01"}
+type HTTPClient struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+type quoteResp struct {
+	Symbol   string  `json:"symbol"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+	Date     string  `json:"date"`
+}
+
+func (h *HTTPClient) Quote(symbol string) (float64, string, time.Time, error) {
+	u := fmt.Sprintf("%s?symbol=%s", h.BaseURL, url.QueryEscape(symbol))
+	resp, err := h.Client.Get(u)
+	if err != nil {
+		return 0, "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", time.Time{}, fmt.Errorf("prices: %s returned %d", symbol, resp.StatusCode)
+	}
+	var qr quoteResp
+	if err := json.NewDecoder(resp.Body).Decode(&qr); err != nil {
+		return 0, "", time.Time{}, err
+	}
+	d, _ := time.Parse("2006-01-02", qr.Date)
+	return qr.Price, qr.Currency, d, nil
+}
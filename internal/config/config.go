@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -24,6 +25,38 @@ type Config struct {
 	RatesWarmEvery time.Duration
 	RatesWarmBases []string
 
+	PricesURL         string
+	PriceRefreshEvery time.Duration
+
+	NetWorthEvery     time.Duration
+	SubscriptionEvery time.Duration
+	InsightEvery      time.Duration
+	AutoPostEvery     time.Duration
+
+	ExportDir     string
+	ExportEvery   time.Duration
+	ExportLinkTTL time.Duration
+	ExportFileTTL time.Duration
+
+	DeletionGrace time.Duration
+	PurgeEvery    time.Duration
+
+	MailDriver       string
+	MailFrom         string
+	MailDir          string
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUser         string
+	SMTPPass         string
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+
+	EmailVerifyURL      string
+	EmailVerifyTTL      time.Duration
+	EmailChangeURL      string
+	EmailChangeTTL      time.Duration
+	EmailVerifyRequired []string
+
 	CaptchaProvider  string
 	RecaptchaSecret  string
 	TurnstileSecret  string
@@ -52,6 +85,39 @@ func Load() Config {
 		RatesWarmEvery: getdur("RATES_WARM_EVERY", time.Duration(0)),
 		RatesWarmBases: splitCSV(getenv("RATES_WARM_BASES", "TRY,USD,EUR")),
 
+		PricesURL:         getenv("PRICES_URL", ""),
+		PriceRefreshEvery: getdur("PRICE_REFRESH_EVERY", time.Duration(0)),
+
+		NetWorthEvery:     getdur("NETWORTH_EVERY", 6*time.Hour),
+		SubscriptionEvery: getdur("SUBSCRIPTION_SCAN_EVERY", 6*time.Hour),
+		InsightEvery:      getdur("INSIGHT_EVERY", 6*time.Hour),
+		AutoPostEvery:     getdur("AUTOPOST_EVERY", 15*time.Minute),
+
+		ExportDir:     getenv("EXPORT_DIR", filepath.Join(os.TempDir(), "finance-master-exports")),
+		ExportEvery:   getdur("EXPORT_EVERY", time.Minute),
+		ExportLinkTTL: getdur("EXPORT_LINK_TTL", time.Hour),
+		ExportFileTTL: getdur("EXPORT_FILE_TTL", 7*24*time.Hour),
+
+		DeletionGrace: getdur("ACCOUNT_DELETE_GRACE", 30*24*time.Hour),
+		PurgeEvery:    getdur("ACCOUNT_PURGE_EVERY", time.Hour),
+
+		MailDriver:       strings.ToLower(getenv("MAIL_DRIVER", "log")),
+		MailFrom:         getenv("MAIL_FROM", "no-reply@finance-master.local"),
+		MailDir:          getenv("MAIL_DIR", filepath.Join(os.TempDir(), "finance-master-mail")),
+		SMTPHost:         getenv("SMTP_HOST", ""),
+		SMTPPort:         getint("SMTP_PORT", 587),
+		SMTPUser:         getenv("SMTP_USER", ""),
+		SMTPPass:         getenv("SMTP_PASS", ""),
+		PasswordResetURL: getenv("PASSWORD_RESET_URL", ""),
+		PasswordResetTTL: getdur("PASSWORD_RESET_TTL", 30*time.Minute),
+
+		EmailVerifyURL: getenv("EMAIL_VERIFY_URL", ""),
+		EmailVerifyTTL: getdur("EMAIL_VERIFY_TTL", 48*time.Hour),
+		EmailChangeURL: getenv("EMAIL_CHANGE_URL", ""),
+		EmailChangeTTL: getdur("EMAIL_CHANGE_TTL", 24*time.Hour),
+		// Doğrulanmış adres isteyen eylemler; boş bırakılırsa hiçbir eylem engellenmez.
+		EmailVerifyRequired: splitCSV(getenv("EMAIL_VERIFY_REQUIRED", "account.export,account.import,households.invite")),
+
 		CaptchaProvider:   strings.ToLower(getenv("CAPTCHA_PROVIDER", "")),
 		RecaptchaSecret:   getenv("RECAPTCHA_SECRET", ""),
 		TurnstileSecret:   getenv("TURNSTILE_SECRET", ""),
@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartAccountPurge(ctx context.Context, s *services.AccountService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	run := func() {
+		if _, err := s.PurgeDue(); err != nil {
+			log.Println("account purge:", err)
+		}
+	}
+	go func() {
+		run()
+		for {
+			select {
+			case <-tkr.C:
+				run()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartAutoPost(ctx context.Context, s *services.TxService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	post := func() {
+		if _, err := s.PostDue(); err != nil {
+			log.Println("auto-post:", err)
+		}
+	}
+	go func() {
+		post()
+		for {
+			select {
+			case <-tkr.C:
+				post()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
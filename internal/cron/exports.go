@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartExports(ctx context.Context, s *services.ExportService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	run := func() {
+		if _, err := s.RunPending(); err != nil {
+			log.Println("export:", err)
+		}
+		if _, err := s.Cleanup(); err != nil {
+			log.Println("export cleanup:", err)
+		}
+	}
+	go func() {
+		run()
+		for {
+			select {
+			case <-tkr.C:
+				run()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartInsights(ctx context.Context, s *services.InsightService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	gen := func() {
+		if _, err := s.Generate(); err != nil {
+			log.Println("insights:", err)
+		}
+	}
+	go func() {
+		gen()
+		for {
+			select {
+			case <-tkr.C:
+				gen()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartNetWorth(ctx context.Context, s *services.NetWorthService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	snapshot := func() {
+		if _, err := s.RunDaily(); err != nil {
+			log.Println("networth snapshot:", err)
+		}
+	}
+	go func() {
+		snapshot()
+		for {
+			select {
+			case <-tkr.C:
+				snapshot()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
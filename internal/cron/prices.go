@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartPriceRefresh(ctx context.Context, s *services.InvestmentService, every time.Duration) (stop func()) {
+	if s == nil || s.Quotes == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	refresh := func() {
+		if _, err := s.RefreshPrices(); err != nil {
+			log.Println("price refresh:", err)
+		}
+	}
+	go func() {
+		refresh()
+		for {
+			select {
+			case <-tkr.C:
+				refresh()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
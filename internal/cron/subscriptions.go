@@ -0,0 +1,36 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+func StartSubscriptionScan(ctx context.Context, s *services.SubscriptionService, every time.Duration) (stop func()) {
+	if s == nil || every <= 0 {
+		return func() {}
+	}
+	tkr := time.NewTicker(every)
+	done := make(chan struct{})
+
+	scan := func() {
+		if _, err := s.Scan(); err != nil {
+			log.Println("subscription scan:", err)
+		}
+	}
+	go func() {
+		scan()
+		for {
+			select {
+			case <-tkr.C:
+				scan()
+			case <-ctx.Done():
+				close(done)
+				return
+			}
+		}
+	}()
+	return func() { tkr.Stop(); <-done }
+}
@@ -27,6 +27,10 @@ var (
 	Internal           = E("internal", 500, "internal server error")
 
 	HasTransactions   = E("has_transactions", 409, "resource has linked transactions")
+	HasChildren       = E("has_children", 409, "category has sub-categories")
+	HasDebts          = E("has_debts", 409, "contact has debt records")
+	HasWallets        = E("has_wallets", 409, "household has wallets")
+	TxLocked          = E("tx_locked", 409, "transaction is reconciled and locked")
 	CaptchaRequired   = E("captcha_required", 401, "captcha required")
 	SlowDown          = E("slow_down", 429, "too many attempts, slow down")
 	InsecureTransport = E("insecure_transport", 426, "https required")
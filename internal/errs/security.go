@@ -1,6 +1,7 @@
 package errs
 
 var (
-	AccountLocked   = E("account_locked", 423, "account temporarily locked")
-	SessionMismatch = E("session_mismatch", 401, "refresh not valid for this device/ip")
+	AccountLocked    = E("account_locked", 423, "account temporarily locked")
+	SessionMismatch  = E("session_mismatch", 401, "refresh not valid for this device/ip")
+	EmailNotVerified = E("email_not_verified", 403, "verify your email address first")
 )
@@ -1,9 +1,22 @@
 package http
 
 type API struct {
-	Auth   *AuthHandlers
-	H      *Handlers
-	CatH   *CatalogHandlers
-	Rates  *RatesHandlers
-	Secret []byte
+	Auth    *AuthHandlers
+	H       *Handlers
+	CatH    *CatalogHandlers
+	Rates   *RatesHandlers
+	Inst    *InstallmentHandlers
+	Debt    *DebtHandlers
+	House   *HouseholdHandlers
+	Group   *GroupHandlers
+	Invest  *InvestmentHandlers
+	Worth   *NetWorthHandlers
+	Recon   *ReconcileHandlers
+	Views   *ViewHandlers
+	Insight *InsightHandlers
+	Report  *ReportHandlers
+	Onboard *OnboardHandlers
+	Account *AccountHandlers
+	Email   *EmailHandlers
+	Secret  []byte
 }
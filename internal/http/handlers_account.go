@@ -0,0 +1,134 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+// importMaxBody içe aktarma isteğinin gövde sınırı; diğer istekler 1 MiB ile sınırlıdır.
+const importMaxBody = 64 << 20
+
+type AccountHandlers struct {
+	S      *services.AccountService
+	Export *services.ExportService
+	Import *services.ImportService
+}
+
+type reqDeleteAccount struct {
+	Password string `json:"password"       validate:"required,max=256"`
+	Totp     string `json:"totp,omitempty" validate:"omitempty,min=6,max=32"`
+}
+
+// Delete hesabı silinmeyi bekler duruma alır; bekleme süresi içinde giriş yapmak talebi iptal eder.
+func (h *AccountHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	var in reqDeleteAccount
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	d, err := h.S.RequestDeletion(UID(r), in.Password, in.Totp)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusAccepted, map[string]any{"status": "pending_deletion", "deletion": d})
+}
+
+// ExportStart kişisel veri arşivini kuyruğa alır; durum GET /account/exports/{id} ile izlenir.
+func (h *AccountHandlers) ExportStart(w http.ResponseWriter, r *http.Request) {
+	e, err := h.Export.Request(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusAccepted, e)
+}
+
+func (h *AccountHandlers) ExportList(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.Export.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *AccountHandlers) ExportGet(w http.ResponseWriter, r *http.Request) {
+	e, err := h.Export.Get(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, e)
+}
+
+// ExportDownload imzalı bağlantıyla çalışır; Authorization başlığı gerekmez.
+func (h *AccountHandlers) ExportDownload(w http.ResponseWriter, r *http.Request) {
+	exp, err := strconv.ParseInt(r.URL.Query().Get("exp"), 10, 64)
+	if err != nil {
+		WriteAppError(w, errs.NotFound)
+		return
+	}
+	f, e, err := h.Export.Open(pathID(r, "id"), exp, r.URL.Query().Get("sig"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="finance-master-export-%d.zip"`, e.ID))
+	w.Header().Set("Cache-Control", "no-store")
+	if e.Size != nil {
+		w.Header().Set("Content-Length", strconv.FormatInt(*e.Size, 10))
+	}
+	if e.SHA256 != nil {
+		w.Header().Set("X-Checksum-SHA256", *e.SHA256)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, f)
+}
+
+type qImport struct {
+	DryRun   string `validate:"omitempty,oneof=true false 1 0"`
+	Conflict string `validate:"omitempty,oneof=merge rename"`
+}
+
+// ImportArchive dışa aktarım arşivini (application/zip gövde) hesaba yükler. dryRun=true aynı işlemi
+// çalıştırıp geri alır ve yalnız sayıları döner; conflict=merge|rename ad çakışmalarını belirler.
+func (h *AccountHandlers) ImportArchive(w http.ResponseWriter, r *http.Request) {
+	q := qImport{DryRun: r.URL.Query().Get("dryRun"), Conflict: r.URL.Query().Get("conflict")}
+	if err := validation.ValidateStruct(q); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		Fail(w, http.StatusRequestEntityTooLarge, "payload_too_large", "archive too large")
+		return
+	}
+	if len(body) == 0 {
+		WriteAppError(w, errs.ValidationFailed("archive_required"))
+		return
+	}
+	dry := q.DryRun == "true" || q.DryRun == "1"
+	res, err := h.Import.Import(UID(r), body, q.Conflict, dry)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	status := http.StatusCreated
+	if dry {
+		status = http.StatusOK
+	}
+	WriteJSON(w, status, res)
+}
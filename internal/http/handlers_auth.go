@@ -1,6 +1,7 @@
 package http
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
@@ -17,12 +18,14 @@ type reqRegister struct {
 	Name     string `json:"name"     validate:"required,min=2,max=100"`
 	Email    string `json:"email"    validate:"required,email,max=320"`
 	Password string `json:"password" validate:"required,min=10,max=256"`
+	// Locale başlangıç şablonu; boşsa Accept-Language'den seçilir.
+	Locale string `json:"locale,omitempty" validate:"omitempty,alpha,max=8"`
 }
 
 type reqLogin struct {
 	Email      string `json:"email"      validate:"required,email,max=320"`
 	Password   string `json:"password"   validate:"required,min=6,max=256"`
-	Totp       string `json:"totp,omitempty"        validate:"omitempty,len=6,numeric"`
+	Totp       string `json:"totp,omitempty"        validate:"omitempty,min=6,max=32"`
 	DeviceID   string `json:"deviceId,omitempty"    validate:"omitempty,max=128"`
 	DeviceName string `json:"deviceName,omitempty"  validate:"omitempty,max=128"`
 	Captcha    string `json:"captcha,omitempty"     validate:"omitempty,max=2048"`
@@ -32,6 +35,28 @@ type reqRefresh struct {
 	Refresh string `json:"refresh" validate:"required,min=10"`
 }
 
+type reqForgotPassword struct {
+	Email string `json:"email" validate:"required,email,max=320"`
+}
+
+type reqResetPassword struct {
+	Token    string `json:"token"    validate:"required,min=16,max=128"`
+	Password string `json:"password" validate:"required,min=10,max=256"`
+}
+
+type reqChangePassword struct {
+	CurrentPassword string `json:"currentPassword"       validate:"required,max=256"`
+	NewPassword     string `json:"newPassword"           validate:"required,min=10,max=256"`
+	Totp            string `json:"totp,omitempty"        validate:"omitempty,min=6,max=32"`
+	Refresh         string `json:"refresh"               validate:"required,min=10"`
+}
+
+// reqTotpReauth Code altı haneli TOTP ya da kurtarma kodu olabilir.
+type reqTotpReauth struct {
+	Password string `json:"password" validate:"required,max=256"`
+	Code     string `json:"code"     validate:"required,min=6,max=32"`
+}
+
 type reqTotpConfirm struct {
 	Code string `json:"code" validate:"required,len=6,numeric"`
 }
@@ -50,7 +75,12 @@ func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 		WriteAppError(w, errs.ValidationFailed("weak_password"))
 		return
 	}
-	_, _ = h.S.Register(in.Name, in.Email, in.Password)
+	if in.Locale != "" && !services.HasLocale(in.Locale) {
+		WriteAppError(w, errs.ValidationFailed("unsupported_locale"))
+		return
+	}
+	// Tohumlama hatası denetim kaydına düşer; yanıt hesap varlığını sızdırmamak için aynı kalır.
+	_, _ = h.S.Register(in.Name, in.Email, in.Password, services.PickLocale(in.Locale, r.Header.Get("Accept-Language")))
 	WriteJSON(w, http.StatusOK, map[string]any{"status": "ok"})
 }
 
@@ -111,13 +141,51 @@ func (h *AuthHandlers) TotpConfirm(w http.ResponseWriter, r *http.Request) {
 		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
 		return
 	}
-	if err := h.S.TotpConfirm(uid, in.Code); err != nil {
+	codes, err := h.S.TotpConfirm(uid, in.Code)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"status": "ok", "recoveryCodes": codes})
+}
+
+// TotpDisable parola ve geçerli TOTP ya da kurtarma koduyla iki adımlı doğrulamayı kapatır.
+func (h *AuthHandlers) TotpDisable(w http.ResponseWriter, r *http.Request) {
+	var in reqTotpReauth
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	if err := h.S.DisableTotp(UID(r), in.Password, in.Code); err != nil {
 		FromError(w, err)
 		return
 	}
 	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// TotpRecoveryCodes kodları yeniler; eskiler geçersiz olur, yeniler yalnız bu yanıtta görünür.
+func (h *AuthHandlers) TotpRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var in reqTotpReauth
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	codes, err := h.S.RegenerateRecoveryCodes(UID(r), in.Password, in.Code)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"recoveryCodes": codes})
+}
+
 func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	var in reqLogin
 	if err := DecodeStrict(r, &in); err != nil {
@@ -167,3 +235,59 @@ func (h *AuthHandlers) SessionDelete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ForgotPassword her zaman 200 döner; hesabın var olup olmadığı yanıttan anlaşılmaz. Arama ve
+// posta gönderimi arka planda yürür ki yanıt süresi de hesabı ele vermesin.
+func (h *AuthHandlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var in reqForgotPassword
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	go func(email string) {
+		if err := h.S.ForgotPassword(email); err != nil {
+			log.Printf("password forgot: %v", err)
+		}
+	}(in.Email)
+	WriteJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (h *AuthHandlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var in reqResetPassword
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	if err := h.S.ResetPassword(in.Token, in.Password); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ChangePassword gövdedeki refresh belirtecinin oturumu dışındaki tüm oturumları kapatır;
+// çağıran cihaz mevcut belirteçleriyle devam eder.
+func (h *AuthHandlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var in reqChangePassword
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	if err := h.S.ChangePassword(UID(r), in.CurrentPassword, in.NewPassword, in.Totp, in.Refresh, clientIP(r)); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
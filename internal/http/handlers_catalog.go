@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Veysel440/finance-master-api/internal/errs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
@@ -15,16 +16,34 @@ import (
 type CatalogHandlers struct {
 	Wallet *services.WalletService
 	Cat    *services.CategoryService
+	Stmt   *services.StatementService
 }
 
 /* Wallets */
 type walletReq struct {
-	Name     string `json:"name"     validate:"required,min=1,max=100"`
-	Currency string `json:"currency" validate:"required,currency"`
+	Name          string   `json:"name"          validate:"required,min=1,max=100"`
+	Currency      string   `json:"currency"      validate:"required,currency"`
+	Kind          string   `json:"kind"          validate:"omitempty,walletkind"`
+	CreditLimit   *float64 `json:"creditLimit"   validate:"omitempty,gte=0"`
+	StatementDay  *int     `json:"statementDay"  validate:"omitempty,min=1,max=31"`
+	DueDay        *int     `json:"dueDay"        validate:"omitempty,min=1,max=31"`
+	Institution   *string  `json:"institution"   validate:"omitempty,noctrl,max=120"`
+	AccountNumber *string  `json:"accountNumber" validate:"omitempty,noctrl,max=64"`
+}
+
+func (in walletReq) toWallet(id int64) ports.Wallet {
+	return ports.Wallet{
+		ID: id, Name: in.Name, Currency: in.Currency, Kind: in.Kind,
+		CreditLimit: in.CreditLimit, StatementDay: in.StatementDay, DueDay: in.DueDay,
+		Institution: in.Institution, AccountMask: in.AccountNumber,
+	}
 }
 
 func (h *CatalogHandlers) WalletList(w http.ResponseWriter, r *http.Request) {
-	rows, err := h.Wallet.List(UID(r))
+	q := r.URL.Query()
+	f := services.WalletFilter{Kind: strings.TrimSpace(q.Get("kind"))}
+	f.IncludeArchived, _ = strconv.ParseBool(q.Get("includeArchived"))
+	rows, err := h.Wallet.List(UID(r), f)
 	if err != nil {
 		FromError(w, err)
 		return
@@ -42,7 +61,7 @@ func (h *CatalogHandlers) WalletCreate(w http.ResponseWriter, r *http.Request) {
 		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
 		return
 	}
-	wal := ports.Wallet{Name: in.Name, Currency: in.Currency}
+	wal := in.toWallet(0)
 	if err := h.Wallet.Create(UID(r), &wal); err != nil {
 		FromError(w, err)
 		return
@@ -61,7 +80,7 @@ func (h *CatalogHandlers) WalletUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	wal := ports.Wallet{ID: id, Name: in.Name, Currency: in.Currency}
+	wal := in.toWallet(id)
 	if err := h.Wallet.Update(UID(r), &wal); err != nil {
 		FromError(w, err)
 		return
@@ -82,15 +101,70 @@ func (h *CatalogHandlers) WalletDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *CatalogHandlers) WalletArchive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.Wallet.Archive(UID(r), id); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CatalogHandlers) WalletUnarchive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.Wallet.Unarchive(UID(r), id); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CatalogHandlers) WalletStatements(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	rows, err := h.Stmt.List(UID(r), id, count)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
 /* Categories */
+// catReq güncellemede gönderilmeyen parentId/sortOrder/icon/color mevcut değeri korur;
+// parentId 0 kategoriyi köke taşır.
 type catReq struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
-	Type string `json:"type" validate:"required,txtype"`
+	Name      string  `json:"name"      validate:"required,min=1,max=100"`
+	Type      string  `json:"type"      validate:"required,txtype"`
+	ParentID  *int64  `json:"parentId"  validate:"omitempty,gte=0"`
+	SortOrder *int    `json:"sortOrder" validate:"omitempty,gte=0,lte=100000"`
+	Icon      *string `json:"icon"      validate:"omitempty,noctrl,max=64"`
+	Color     *string `json:"color"     validate:"omitempty,hexcolor,len=7"`
+}
+
+func (in catReq) toCategory() ports.Category {
+	c := ports.Category{Name: in.Name, Type: in.Type, Icon: in.Icon, Color: in.Color}
+	if in.ParentID != nil && *in.ParentID > 0 {
+		c.ParentID = in.ParentID
+	}
+	if in.SortOrder != nil {
+		c.SortOrder = *in.SortOrder
+	}
+	return c
 }
 
 func (h *CatalogHandlers) CategoryList(w http.ResponseWriter, r *http.Request) {
 	typ := strings.TrimSpace(r.URL.Query().Get("type"))
-	rows, err := h.Cat.List(UID(r), typ)
+	archived, _ := strconv.ParseBool(r.URL.Query().Get("includeArchived"))
+	var (
+		rows []ports.Category
+		err  error
+	)
+	if tree, _ := strconv.ParseBool(r.URL.Query().Get("tree")); tree {
+		rows, err = h.Cat.Tree(UID(r), typ, archived)
+	} else {
+		rows, err = h.Cat.List(UID(r), typ, archived)
+	}
 	if err != nil {
 		FromError(w, err)
 		return
@@ -108,7 +182,7 @@ func (h *CatalogHandlers) CategoryCreate(w http.ResponseWriter, r *http.Request)
 		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
 		return
 	}
-	c := ports.Category{Name: in.Name, Type: in.Type}
+	c := in.toCategory()
 	if err := h.Cat.Create(UID(r), &c); err != nil {
 		FromError(w, err)
 		return
@@ -127,8 +201,10 @@ func (h *CatalogHandlers) CategoryUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
-	c := ports.Category{ID: id, Name: in.Name, Type: in.Type}
-	if err := h.Cat.Update(UID(r), &c); err != nil {
+	c, err := h.Cat.Update(UID(r), id, services.CategoryInput{
+		Name: in.Name, Type: in.Type, ParentID: in.ParentID, SortOrder: in.SortOrder, Icon: in.Icon, Color: in.Color,
+	})
+	if err != nil {
 		FromError(w, err)
 		return
 	}
@@ -138,8 +214,8 @@ func (h *CatalogHandlers) CategoryUpdate(w http.ResponseWriter, r *http.Request)
 func (h *CatalogHandlers) CategoryDelete(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err := h.Cat.Delete(UID(r), id); err != nil {
-		if err == errs.HasTransactions {
-			WriteAppError(w, errs.HasTransactions)
+		if err == errs.HasTransactions || err == errs.HasChildren {
+			WriteAppError(w, err.(*errs.AppError))
 			return
 		}
 		FromError(w, err)
@@ -147,3 +223,53 @@ func (h *CatalogHandlers) CategoryDelete(w http.ResponseWriter, r *http.Request)
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type catMergeReq struct {
+	TargetID int64 `json:"targetId" validate:"required,gt=0"`
+}
+
+func (h *CatalogHandlers) CategoryMerge(w http.ResponseWriter, r *http.Request) {
+	var in catMergeReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	moved, err := h.Cat.Merge(UID(r), id, in.TargetID)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"moved": moved, "targetId": in.TargetID})
+}
+
+func (h *CatalogHandlers) CategoryArchive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.Cat.Archive(UID(r), id); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CatalogHandlers) CategoryUnarchive(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.Cat.Unarchive(UID(r), id); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CatalogHandlers) CategorySince(w http.ResponseWriter, r *http.Request) {
+	tm, err := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+	if err != nil {
+		WriteAppError(w, errs.ValidationFailed("bad since"))
+		return
+	}
+	rows, err := h.Cat.Since(UID(r), tm)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
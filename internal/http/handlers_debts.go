@@ -0,0 +1,168 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type DebtHandlers struct {
+	Contacts *services.ContactService
+	Debts    *services.DebtService
+}
+
+/* Contacts */
+type contactReq struct {
+	Name  string  `json:"name"  validate:"required,min=1,max=120"`
+	Email *string `json:"email" validate:"omitempty,email,max=191"`
+	Phone *string `json:"phone" validate:"omitempty,noctrl,max=32"`
+	Note  *string `json:"note"  validate:"omitempty,noctrl,max=255"`
+}
+
+func (h *DebtHandlers) ContactList(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.Contacts.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *DebtHandlers) ContactCreate(w http.ResponseWriter, r *http.Request) {
+	var in contactReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	c := ports.Contact{Name: in.Name, Email: in.Email, Phone: in.Phone, Note: in.Note}
+	if err := h.Contacts.Create(UID(r), &c); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, c)
+}
+
+func (h *DebtHandlers) ContactUpdate(w http.ResponseWriter, r *http.Request) {
+	var in contactReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	c := ports.Contact{ID: id, Name: in.Name, Email: in.Email, Phone: in.Phone, Note: in.Note}
+	if err := h.Contacts.Update(UID(r), &c); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, c)
+}
+
+func (h *DebtHandlers) ContactDelete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err := h.Contacts.Delete(UID(r), id); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/* Debts */
+type debtReq struct {
+	ContactID int64   `json:"contactId" validate:"required,gt=0"`
+	Direction string  `json:"direction" validate:"required,oneof=lent borrowed"`
+	Amount    float64 `json:"amount"    validate:"required,gt=0"`
+	Currency  string  `json:"currency"  validate:"required,currency"`
+	DueAt     *string `json:"dueAt"     validate:"omitempty,iso8601"`
+	Note      *string `json:"note"      validate:"omitempty,noctrl,max=255"`
+}
+
+type debtPaymentReq struct {
+	Amount     float64 `json:"amount"     validate:"required,gt=0"`
+	PaidAt     string  `json:"paidAt"     validate:"omitempty,iso8601"`
+	Note       *string `json:"note"       validate:"omitempty,noctrl,max=255"`
+	WalletID   *int64  `json:"walletId"   validate:"omitempty,gt=0"`
+	CategoryID *int64  `json:"categoryId" validate:"omitempty,gt=0"`
+}
+
+func (h *DebtHandlers) DebtList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if overdue, _ := strconv.ParseBool(q.Get("overdue")); overdue {
+		rows, err := h.Debts.Overdue(UID(r))
+		if err != nil {
+			FromError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, rows)
+		return
+	}
+	f := ports.DebtFilter{Status: strings.TrimSpace(q.Get("status"))}
+	f.ContactID, _ = strconv.ParseInt(q.Get("contactId"), 10, 64)
+	rows, err := h.Debts.List(UID(r), f)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *DebtHandlers) DebtCreate(w http.ResponseWriter, r *http.Request) {
+	var in debtReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	d := ports.Debt{
+		ContactID: in.ContactID, Direction: in.Direction, Amount: in.Amount,
+		Currency: in.Currency, Note: in.Note,
+	}
+	if in.DueAt != nil {
+		due, _ := time.Parse(time.RFC3339, *in.DueAt)
+		d.DueAt = &due
+	}
+	if err := h.Debts.Create(UID(r), &d); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, d)
+}
+
+func (h *DebtHandlers) DebtGet(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	d, err := h.Debts.Get(UID(r), id)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, d)
+}
+
+func (h *DebtHandlers) DebtPayment(w http.ResponseWriter, r *http.Request) {
+	var in debtPaymentReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	pin := services.DebtPaymentInput{
+		Amount: in.Amount, Note: in.Note, WalletID: in.WalletID, CategoryID: in.CategoryID,
+	}
+	if in.PaidAt != "" {
+		pin.PaidAt, _ = time.Parse(time.RFC3339, in.PaidAt)
+	}
+	p, err := h.Debts.AddPayment(UID(r), id, pin)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, p)
+}
+
+func (h *DebtHandlers) DebtSummary(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.Debts.Summary(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
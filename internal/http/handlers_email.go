@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+type EmailHandlers struct {
+	S *services.EmailService
+}
+
+type reqEmailToken struct {
+	Token string `json:"token" validate:"required,min=16,max=256"`
+}
+
+type reqEmailChange struct {
+	Email    string `json:"email"    validate:"required,email,max=191"`
+	Password string `json:"password" validate:"required,max=256"`
+}
+
+// Require politikada doğrulama isteyen eylemi doğrulanmamış hesaplara kapatır; Auth'tan sonra kullanılır.
+func (h *EmailHandlers) Require(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if h == nil || h.S == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := h.S.Require(UID(r), action); err != nil {
+				FromError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (h *EmailHandlers) Status(w http.ResponseWriter, r *http.Request) {
+	st, err := h.S.Status(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"email": st.Email, "verified": st.VerifiedAt != nil, "verifiedAt": st.VerifiedAt})
+}
+
+// Verify posta bağlantısıyla çalışır; Authorization başlığı gerekmez.
+func (h *EmailHandlers) Verify(w http.ResponseWriter, r *http.Request) {
+	var in reqEmailToken
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	if err := h.S.Verify(in.Token); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "verified"})
+}
+
+func (h *EmailHandlers) Resend(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.SendVerification(UID(r)); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+// Change iki adrese de onay bağlantısı gönderir; adres ikisi de onaylanınca değişir.
+func (h *EmailHandlers) Change(w http.ResponseWriter, r *http.Request) {
+	var in reqEmailChange
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	c, err := h.S.RequestChange(UID(r), in.Password, in.Email)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusAccepted, c)
+}
+
+func (h *EmailHandlers) ChangeConfirm(w http.ResponseWriter, r *http.Request) {
+	var in reqEmailToken
+	if err := DecodeStrict(r, &in); err != nil {
+		Fail(w, http.StatusBadRequest, "bad_request", "invalid json")
+		return
+	}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	c, err := h.S.ConfirmChange(in.Token)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	status := "pending"
+	if c.CompletedAt != nil {
+		status = "changed"
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"status": status, "change": c})
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+type GroupHandlers struct{ S *services.GroupService }
+
+type groupReq struct {
+	Name       string `json:"name"       validate:"required,min=1,max=120,noctrl"`
+	Currency   string `json:"currency"   validate:"required,currency"`
+	MemberName string `json:"memberName" validate:"required,min=1,max=120,noctrl"`
+}
+
+type groupMemberReq struct {
+	Name  string `json:"name"  validate:"required,min=1,max=120,noctrl"`
+	Email string `json:"email" validate:"omitempty,email,max=191"`
+}
+
+type splitPartReq struct {
+	MemberID int64   `json:"memberId" validate:"required,gt=0"`
+	Value    float64 `json:"value"    validate:"gte=0"`
+}
+
+type groupExpenseReq struct {
+	PaidBy      int64          `json:"paidBy"      validate:"required,gt=0"`
+	Amount      float64        `json:"amount"      validate:"required,gt=0"`
+	Description string         `json:"description" validate:"required,min=1,max=255,noctrl"`
+	Mode        string         `json:"mode"        validate:"required,oneof=equal shares exact"`
+	Parts       []splitPartReq `json:"parts"       validate:"omitempty,max=100,dive"`
+	OccurredAt  string         `json:"occurredAt"  validate:"omitempty,iso8601"`
+	WalletID    *int64         `json:"walletId"    validate:"omitempty,gt=0"`
+	CategoryID  *int64         `json:"categoryId"  validate:"omitempty,gt=0"`
+}
+
+type settlementReq struct {
+	FromMember     int64   `json:"fromMember"     validate:"required,gt=0"`
+	ToMember       int64   `json:"toMember"       validate:"required,gt=0"`
+	Amount         float64 `json:"amount"         validate:"required,gt=0"`
+	SettledAt      string  `json:"settledAt"      validate:"omitempty,iso8601"`
+	FromWalletID   *int64  `json:"fromWalletId"   validate:"omitempty,gt=0"`
+	FromCategoryID *int64  `json:"fromCategoryId" validate:"omitempty,gt=0"`
+	ToWalletID     *int64  `json:"toWalletId"     validate:"omitempty,gt=0"`
+	ToCategoryID   *int64  `json:"toCategoryId"   validate:"omitempty,gt=0"`
+}
+
+func (h *GroupHandlers) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *GroupHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var in groupReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	g := ports.ExpenseGroup{Name: in.Name, Currency: in.Currency}
+	if err := h.S.Create(UID(r), &g, in.MemberName); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, g)
+}
+
+func (h *GroupHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	g, err := h.S.Get(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, g)
+}
+
+func (h *GroupHandlers) AddMember(w http.ResponseWriter, r *http.Request) {
+	var in groupMemberReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	m, err := h.S.AddMember(UID(r), pathID(r, "id"), in.Name, in.Email)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, m)
+}
+
+func (h *GroupHandlers) Accept(w http.ResponseWriter, r *http.Request) {
+	var in acceptInviteReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	g, err := h.S.Accept(UID(r), in.Token)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, g)
+}
+
+func (h *GroupHandlers) Expenses(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.Expenses(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *GroupHandlers) AddExpense(w http.ResponseWriter, r *http.Request) {
+	var in groupExpenseReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	ein := services.GroupExpenseInput{
+		PaidBy: in.PaidBy, Amount: in.Amount, Description: in.Description, Mode: in.Mode,
+		WalletID: in.WalletID, CategoryID: in.CategoryID,
+	}
+	for _, p := range in.Parts {
+		ein.Parts = append(ein.Parts, services.SplitPart{MemberID: p.MemberID, Value: p.Value})
+	}
+	if in.OccurredAt != "" {
+		ein.OccurredAt, _ = time.Parse(time.RFC3339, in.OccurredAt)
+	}
+	e, err := h.S.AddExpense(UID(r), pathID(r, "id"), ein)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, e)
+}
+
+func (h *GroupHandlers) Balances(w http.ResponseWriter, r *http.Request) {
+	bal, plan, err := h.S.Balances(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"balances": bal, "settleUp": plan})
+}
+
+func (h *GroupHandlers) Settlements(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.Settlements(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *GroupHandlers) Settle(w http.ResponseWriter, r *http.Request) {
+	var in settlementReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	sin := services.SettlementInput{
+		FromMember: in.FromMember, ToMember: in.ToMember, Amount: in.Amount,
+		FromWalletID: in.FromWalletID, FromCategoryID: in.FromCategoryID,
+		ToWalletID: in.ToWalletID, ToCategoryID: in.ToCategoryID,
+	}
+	if in.SettledAt != "" {
+		sin.SettledAt, _ = time.Parse(time.RFC3339, in.SettledAt)
+	}
+	st, err := h.S.Settle(UID(r), pathID(r, "id"), sin)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, st)
+}
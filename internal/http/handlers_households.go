@@ -0,0 +1,193 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type HouseholdHandlers struct{ S *services.HouseholdService }
+
+type householdReq struct {
+	Name string `json:"name" validate:"required,min=1,max=120,noctrl"`
+}
+
+type inviteReq struct {
+	Email string `json:"email" validate:"required,email,max=191"`
+	Role  string `json:"role"  validate:"required,oneof=editor viewer"`
+}
+
+type acceptInviteReq struct {
+	Token string `json:"token" validate:"required,min=16,max=128"`
+}
+
+type memberRoleReq struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type householdTxIn struct {
+	Type       string  `json:"type"       validate:"required,txtype"`
+	Amount     float64 `json:"amount"     validate:"required,gt=0"`
+	Currency   string  `json:"currency"   validate:"omitempty,currency"`
+	CategoryID int64   `json:"categoryId" validate:"required,gt=0"`
+	Note       *string `json:"note"       validate:"omitempty,noctrl,max=500"`
+	OccurredAt string  `json:"occurredAt" validate:"required,iso8601"`
+}
+
+func pathID(r *http.Request, name string) int64 {
+	id, _ := strconv.ParseInt(chi.URLParam(r, name), 10, 64)
+	return id
+}
+
+func (h *HouseholdHandlers) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *HouseholdHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var in householdReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	hh := ports.Household{Name: in.Name}
+	if err := h.S.Create(UID(r), &hh); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, hh)
+}
+
+func (h *HouseholdHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	hh, err := h.S.Get(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, hh)
+}
+
+func (h *HouseholdHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.Delete(UID(r), pathID(r, "id")); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HouseholdHandlers) Invite(w http.ResponseWriter, r *http.Request) {
+	var in inviteReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	inv, err := h.S.Invite(UID(r), pathID(r, "id"), in.Email, in.Role)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, inv)
+}
+
+func (h *HouseholdHandlers) Accept(w http.ResponseWriter, r *http.Request) {
+	var in acceptInviteReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	hh, err := h.S.Accept(UID(r), in.Token)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, hh)
+}
+
+func (h *HouseholdHandlers) SetRole(w http.ResponseWriter, r *http.Request) {
+	var in memberRoleReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	if err := h.S.SetRole(UID(r), pathID(r, "id"), pathID(r, "userId"), in.Role); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HouseholdHandlers) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.RemoveMember(UID(r), pathID(r, "id"), pathID(r, "userId")); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HouseholdHandlers) Wallets(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.ListWallets(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *HouseholdHandlers) WalletCreate(w http.ResponseWriter, r *http.Request) {
+	var in walletReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	wal := in.toWallet(0)
+	if err := h.S.CreateWallet(UID(r), pathID(r, "id"), &wal); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, wal)
+}
+
+func (h *HouseholdHandlers) WalletTxList(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	if size < 1 || size > 200 {
+		size = 20
+	}
+	items, total, err := h.S.WalletTransactions(UID(r), pathID(r, "id"), pathID(r, "walletId"), page, size)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"total": total, "data": items})
+}
+
+func (h *HouseholdHandlers) WalletTxCreate(w http.ResponseWriter, r *http.Request) {
+	var in householdTxIn
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	occ, _ := time.Parse(time.RFC3339, in.OccurredAt)
+	t := ports.Transaction{
+		CategoryID: in.CategoryID, Type: in.Type, Amount: in.Amount,
+		Currency: in.Currency, Note: in.Note, OccurredAt: occ,
+	}
+	if err := h.S.CreateWalletTx(UID(r), pathID(r, "id"), pathID(r, "walletId"), &t); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, t)
+}
+
+func (h *HouseholdHandlers) WalletTxDelete(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.DeleteWalletTx(UID(r), pathID(r, "id"), pathID(r, "walletId"), pathID(r, "txId")); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+type InsightHandlers struct {
+	S    *services.InsightService
+	Subs *services.SubscriptionService
+}
+
+// List arka planda üretilmiş, kapatılmamış bulguları döner; istek anında hesaplama yapılmaz.
+func (h *InsightHandlers) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *InsightHandlers) Dismiss(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.Dismiss(UID(r), pathID(r, "id")); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InsightHandlers) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.Subs.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
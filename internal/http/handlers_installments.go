@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type InstallmentHandlers struct{ S *services.InstallmentService }
+
+type installmentReq struct {
+	WalletID   int64   `json:"walletId"   validate:"required,gt=0"`
+	CategoryID int64   `json:"categoryId" validate:"required,gt=0"`
+	Amount     float64 `json:"amount"     validate:"required,gt=0"`
+	Currency   string  `json:"currency"   validate:"omitempty,currency"`
+	Count      int     `json:"count"      validate:"required,min=2,max=36"`
+	FirstAt    string  `json:"firstAt"    validate:"required,iso8601"`
+	Note       *string `json:"note"       validate:"omitempty,noctrl,max=255"`
+	Remainder  string  `json:"remainder"  validate:"omitempty,oneof=first last"`
+}
+
+type restructureReq struct {
+	Count int `json:"count" validate:"required,min=1,max=36"`
+}
+
+func (h *InstallmentHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var in installmentReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	first, _ := time.Parse(time.RFC3339, in.FirstAt)
+	p := ports.InstallmentPlan{
+		WalletID: in.WalletID, CategoryID: in.CategoryID, TotalAmount: in.Amount,
+		Currency: in.Currency, Count: in.Count, FirstAt: first, Note: in.Note, Remainder: in.Remainder,
+	}
+	if err := h.S.Create(UID(r), &p); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, p)
+}
+
+func (h *InstallmentHandlers) List(w http.ResponseWriter, r *http.Request) {
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+	rows, err := h.S.List(UID(r), status)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *InstallmentHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	p, err := h.S.Get(UID(r), id)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, p)
+}
+
+func (h *InstallmentHandlers) Close(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	p, err := h.S.CloseEarly(UID(r), id)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, p)
+}
+
+func (h *InstallmentHandlers) Restructure(w http.ResponseWriter, r *http.Request) {
+	var in restructureReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	p, err := h.S.Restructure(UID(r), id, in.Count)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, p)
+}
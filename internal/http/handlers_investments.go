@@ -0,0 +1,159 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+type InvestmentHandlers struct{ S *services.InvestmentService }
+
+type assetReq struct {
+	Symbol   string `json:"symbol"   validate:"required,min=1,max=32,noctrl"`
+	Name     string `json:"name"     validate:"required,min=1,max=120,noctrl"`
+	Kind     string `json:"kind"     validate:"required,oneof=stock fund gold crypto other"`
+	Unit     string `json:"unit"     validate:"omitempty,max=16,noctrl"`
+	Currency string `json:"currency" validate:"required,currency"`
+}
+
+type lotReq struct {
+	WalletID int64   `json:"walletId" validate:"required,gt=0"`
+	AssetID  int64   `json:"assetId"  validate:"required,gt=0"`
+	Side     string  `json:"side"     validate:"required,oneof=buy sell"`
+	Quantity float64 `json:"quantity" validate:"required,gt=0"`
+	Price    float64 `json:"price"    validate:"gte=0"`
+	Fee      float64 `json:"fee"      validate:"gte=0"`
+	TradedAt string  `json:"tradedAt" validate:"omitempty,iso8601"`
+	Note     *string `json:"note"     validate:"omitempty,noctrl,max=255"`
+}
+
+type priceReq struct {
+	Date  string  `json:"date"  validate:"required,datetime=2006-01-02"`
+	Price float64 `json:"price" validate:"required,gt=0"`
+}
+
+func (h *InvestmentHandlers) Assets(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.ListAssets(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *InvestmentHandlers) AssetCreate(w http.ResponseWriter, r *http.Request) {
+	var in assetReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	a := ports.Asset{Symbol: in.Symbol, Name: in.Name, Kind: in.Kind, Unit: in.Unit, Currency: in.Currency}
+	if err := h.S.CreateAsset(UID(r), &a); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, a)
+}
+
+func (h *InvestmentHandlers) Prices(w http.ResponseWriter, r *http.Request) {
+	to := time.Now().UTC()
+	from := to.AddDate(-1, 0, 0)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			WriteAppError(w, errs.ValidationFailed("bad from"))
+			return
+		}
+		from = t
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			WriteAppError(w, errs.ValidationFailed("bad to"))
+			return
+		}
+		to = t
+	}
+	rows, err := h.S.Prices(UID(r), pathID(r, "id"), from, to)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *InvestmentHandlers) PriceSet(w http.ResponseWriter, r *http.Request) {
+	var in priceReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	d, _ := time.Parse("2006-01-02", in.Date)
+	if err := h.S.SetPrice(UID(r), pathID(r, "id"), d, in.Price); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InvestmentHandlers) Lots(w http.ResponseWriter, r *http.Request) {
+	walletID, _ := strconv.ParseInt(r.URL.Query().Get("walletId"), 10, 64)
+	rows, err := h.S.ListLots(UID(r), walletID)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *InvestmentHandlers) LotCreate(w http.ResponseWriter, r *http.Request) {
+	var in lotReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	l := ports.Lot{
+		WalletID: in.WalletID, AssetID: in.AssetID, Side: in.Side,
+		Quantity: in.Quantity, Price: in.Price, Fee: in.Fee, Note: in.Note,
+	}
+	if in.TradedAt != "" {
+		l.TradedAt, _ = time.Parse(time.RFC3339, in.TradedAt)
+	}
+	if err := h.S.AddLot(UID(r), &l); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, l)
+}
+
+// Portfolio: GET /v1/portfolio?currency=TRY&method=fifo|avg&walletId=&from=&to= (RFC3339)
+func (h *InvestmentHandlers) Portfolio(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	q := services.PortfolioQuery{Currency: qs.Get("currency"), Method: qs.Get("method")}
+	type rq struct {
+		Currency string `validate:"omitempty,currency"`
+		Method   string `validate:"omitempty,oneof=fifo avg"`
+		From     string `validate:"omitempty,iso8601"`
+		To       string `validate:"omitempty,iso8601"`
+	}
+	in := rq{Currency: q.Currency, Method: q.Method, From: qs.Get("from"), To: qs.Get("to")}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	q.WalletID, _ = strconv.ParseInt(qs.Get("walletId"), 10, 64)
+	if in.From != "" {
+		q.From, _ = time.Parse(time.RFC3339, in.From)
+	}
+	if in.To != "" {
+		q.To, _ = time.Parse(time.RFC3339, in.To)
+	}
+	p, err := h.S.Portfolio(UID(r), q)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, p)
+}
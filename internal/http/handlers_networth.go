@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+type NetWorthHandlers struct{ S *services.NetWorthService }
+
+type baseCurrencyReq struct {
+	Currency string `json:"currency" validate:"required,currency"`
+}
+
+type backfillReq struct {
+	From string `json:"from" validate:"required,datetime=2006-01-02"`
+}
+
+// Series: GET /v1/networth?from=2026-01-01&to=2026-06-30&interval=day|week|month
+func (h *NetWorthHandlers) Series(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	type rq struct {
+		From     string `validate:"omitempty,datetime=2006-01-02"`
+		To       string `validate:"omitempty,datetime=2006-01-02"`
+		Interval string `validate:"omitempty,oneof=day week month"`
+	}
+	in := rq{From: qs.Get("from"), To: qs.Get("to"), Interval: qs.Get("interval")}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	var from, to time.Time
+	if in.From != "" {
+		from, _ = time.Parse("2006-01-02", in.From)
+	}
+	if in.To != "" {
+		to, _ = time.Parse("2006-01-02", in.To)
+	}
+	out, err := h.S.Series(UID(r), from, to, in.Interval)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *NetWorthHandlers) SetCurrency(w http.ResponseWriter, r *http.Request) {
+	var in baseCurrencyReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	if err := h.S.SetBaseCurrency(UID(r), in.Currency); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Backfill içe aktarma ya da geçmiş düzeltmelerden sonra görüntüleri hemen yeniden üretir.
+func (h *NetWorthHandlers) Backfill(w http.ResponseWriter, r *http.Request) {
+	var in backfillReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	from, _ := time.Parse("2006-01-02", in.From)
+	n, err := h.S.Backfill(UID(r), from)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]int{"days": n})
+}
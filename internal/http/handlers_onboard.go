@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+type OnboardHandlers struct{ S *services.OnboardService }
+
+type reseedReq struct {
+	Locale string `json:"locale" validate:"omitempty,alpha,max=8"`
+}
+
+func (h *OnboardHandlers) Locales(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, map[string]any{"locales": services.Locales(), "default": services.DefaultLocale})
+}
+
+// Reseed eksik varsayılan cüzdan ve kategorileri ekler; mevcut kayıtlara dokunmaz.
+func (h *OnboardHandlers) Reseed(w http.ResponseWriter, r *http.Request) {
+	var in reseedReq
+	if r.ContentLength != 0 && !BindAndValidate(w, r, &in) {
+		return
+	}
+	res, err := h.S.Reseed(UID(r), in.Locale)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+type ReconcileHandlers struct {
+	S  *services.ReconcileService
+	Tx *services.TxService
+}
+
+type reconcileReq struct {
+	WalletID      int64   `json:"walletId"      validate:"required,gt=0"`
+	StatementDate string  `json:"statementDate" validate:"required,datetime=2006-01-02"`
+	EndingBalance float64 `json:"endingBalance"`
+}
+
+type txStatusReq struct {
+	IDs    []int64 `json:"ids"    validate:"required,min=1,max=500,dive,gt=0"`
+	Status string  `json:"status" validate:"required,oneof=pending cleared"`
+}
+
+func (h *ReconcileHandlers) List(w http.ResponseWriter, r *http.Request) {
+	walletID, _ := strconv.ParseInt(r.URL.Query().Get("walletId"), 10, 64)
+	rows, err := h.S.List(UID(r), walletID)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *ReconcileHandlers) Start(w http.ResponseWriter, r *http.Request) {
+	var in reconcileReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	d, _ := time.Parse("2006-01-02", in.StatementDate)
+	rc := ports.Reconciliation{WalletID: in.WalletID, StatementDate: d, EndingBalance: in.EndingBalance}
+	if err := h.S.Start(UID(r), &rc); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, rc)
+}
+
+func (h *ReconcileHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	rc, err := h.S.Get(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rc)
+}
+
+func (h *ReconcileHandlers) Lock(w http.ResponseWriter, r *http.Request) {
+	n, err := h.S.Lock(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]int64{"reconciled": n})
+}
+
+func (h *ReconcileHandlers) Unlock(w http.ResponseWriter, r *http.Request) {
+	n, err := h.S.Unlock(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]int64{"unlocked": n})
+}
+
+// TxStatus: POST /v1/transactions/status — işlemleri toplu pending/cleared işaretler.
+func (h *ReconcileHandlers) TxStatus(w http.ResponseWriter, r *http.Request) {
+	var in txStatusReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	n, err := h.Tx.SetStatus(UID(r), in.IDs, in.Status)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]int64{"updated": n})
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+type ReportHandlers struct{ S *services.ReportService }
+
+// Tax: GET /v1/reports/tax?year=2026&format=json|csv
+func (h *ReportHandlers) Tax(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	type rq struct {
+		Year   string `validate:"required,numeric,len=4"`
+		Format string `validate:"omitempty,oneof=json csv"`
+	}
+	in := rq{Year: qs.Get("year"), Format: qs.Get("format")}
+	if err := validation.ValidateStruct(in); err != nil {
+		WriteAppError(w, errs.ValidationFailed(validation.ValidationMessage(err)))
+		return
+	}
+	year, _ := strconv.Atoi(in.Year)
+	rep, err := h.S.TaxYear(UID(r), year)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	if in.Format == "csv" {
+		writeTaxCSV(w, rep)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rep)
+}
+
+// writeTaxCSV raporu tek tablo olarak yazar; section sütunu total/month/rate satırlarını ayırır.
+func writeTaxCSV(w http.ResponseWriter, rep *ports.TaxReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-%d.csv"`, rep.Year))
+	w.WriteHeader(http.StatusOK)
+
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"section", "currency", "month", "rate", "income", "expenses", "deductible_expenses",
+		"vat_collected", "vat_paid", "vat_deductible", "net_vat"})
+	for _, l := range rep.Ledgers {
+		_ = cw.Write([]string{"total", l.Currency, "", "", money(l.Income),
+			money(l.DeductibleExpenses + l.NonDeductibleExpenses), money(l.DeductibleExpenses),
+			money(l.VATCollected), money(l.VATPaid), money(l.VATDeductible), money(l.NetVAT)})
+		for _, m := range l.ByMonth {
+			_ = cw.Write([]string{"month", l.Currency, fmt.Sprintf("%d-%02d", rep.Year, m.Month), "", money(m.Income),
+				"", money(m.DeductibleExpenses), money(m.VATCollected), money(m.VATPaid), money(m.VATDeductible),
+				money(m.VATCollected - m.VATDeductible)})
+		}
+		for _, rl := range l.ByRate {
+			_ = cw.Write([]string{"rate", l.Currency, "", strconv.FormatFloat(rl.Rate, 'f', -1, 64), money(rl.Income),
+				money(rl.Expenses), "", money(rl.VATCollected), money(rl.VATPaid), money(rl.VATDeductible),
+				money(rl.VATCollected - rl.VATDeductible)})
+		}
+	}
+	cw.Flush()
+}
@@ -26,6 +26,11 @@ type txIn struct {
 	WalletID   int64   `json:"walletId"   validate:"required,gt=0"`
 	Note       *string `json:"note"       validate:"omitempty,noctrl,max=500"`
 	OccurredAt string  `json:"occurredAt" validate:"required,iso8601"` // ISO
+	Status     string  `json:"status"     validate:"omitempty,oneof=scheduled pending cleared"`
+
+	TaxRate    *float64 `json:"taxRate"    validate:"omitempty,gte=0,lte=100"`
+	TaxAmount  *float64 `json:"taxAmount"  validate:"omitempty,gte=0"`
+	Deductible *bool    `json:"deductible"`
 }
 
 func (h *Handlers) TxList(w http.ResponseWriter, r *http.Request) {
@@ -83,7 +88,8 @@ func (h *Handlers) TxCreate(w http.ResponseWriter, r *http.Request) {
 	occ, _ := time.Parse(time.RFC3339, in.OccurredAt)
 	t := ports.Transaction{
 		WalletID: in.WalletID, CategoryID: in.CategoryID, Type: in.Type,
-		Amount: in.Amount, Currency: in.Currency, Note: in.Note, OccurredAt: occ,
+		Amount: in.Amount, Currency: in.Currency, Note: in.Note, OccurredAt: occ, Status: in.Status,
+		TaxRate: in.TaxRate, TaxAmount: in.TaxAmount, Deductible: in.Deductible,
 	}
 	if err := h.Tx.Create(uid, &t); err != nil {
 		FromError(w, err)
@@ -107,7 +113,8 @@ func (h *Handlers) TxUpdate(w http.ResponseWriter, r *http.Request) {
 	occ, _ := time.Parse(time.RFC3339, in.OccurredAt)
 	t := ports.Transaction{
 		ID: id, WalletID: in.WalletID, CategoryID: in.CategoryID, Type: in.Type,
-		Amount: in.Amount, Currency: in.Currency, Note: in.Note, OccurredAt: occ,
+		Amount: in.Amount, Currency: in.Currency, Note: in.Note, OccurredAt: occ, Status: in.Status,
+		TaxRate: in.TaxRate, TaxAmount: in.TaxAmount, Deductible: in.Deductible,
 	}
 	if err := h.Tx.Update(uid, &t); err != nil {
 		FromError(w, err)
@@ -197,10 +204,41 @@ func (h *Handlers) TxSummary(w http.ResponseWriter, r *http.Request) {
 		WriteAppError(w, errs.ValidationFailed("bad to"))
 		return
 	}
-	rows, err := h.Tx.Summary(uid, from, to)
+	// Planlanmış işlemler yalnızca includeScheduled=true ile özete girer.
+	sched, _ := strconv.ParseBool(r.URL.Query().Get("includeScheduled"))
+	if r.URL.Query().Get("groupBy") == "category" {
+		rollup, _ := strconv.ParseBool(r.URL.Query().Get("rollup"))
+		rows, err := h.Tx.SummaryByCategory(uid, from, to, rollup, sched)
+		if err != nil {
+			FromError(w, err)
+			return
+		}
+		WriteJSON(w, 200, rows)
+		return
+	}
+	rows, err := h.Tx.Summary(uid, from, to, sched)
 	if err != nil {
 		FromError(w, err)
 		return
 	}
 	WriteJSON(w, 200, rows)
 }
+
+// TxUpcoming: GET /v1/transactions/upcoming?days=30
+func (h *Handlers) TxUpcoming(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			WriteAppError(w, errs.ValidationFailed("bad days"))
+			return
+		}
+		days = n
+	}
+	items, err := h.Tx.Upcoming(UID(r), days)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, items)
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/services"
+)
+
+type ViewHandlers struct{ S *services.ViewService }
+
+type viewReq struct {
+	Name        string  `json:"name"        validate:"required,min=1,max=120,noctrl"`
+	Range       string  `json:"range"       validate:"omitempty,max=32"`
+	From        string  `json:"from"        validate:"omitempty,datetime=2006-01-02"`
+	To          string  `json:"to"          validate:"omitempty,datetime=2006-01-02"`
+	WalletIDs   []int64 `json:"walletIds"   validate:"omitempty,max=50,dive,gt=0"`
+	CategoryIDs []int64 `json:"categoryIds" validate:"omitempty,max=50,dive,gt=0"`
+	Query       string  `json:"query"       validate:"omitempty,max=500"`
+}
+
+func (in viewReq) toView() ports.SavedView {
+	v := ports.SavedView{Name: in.Name, Range: in.Range, WalletIDs: in.WalletIDs, CategoryIDs: in.CategoryIDs, Query: in.Query}
+	if in.From != "" {
+		d, _ := time.Parse("2006-01-02", in.From)
+		v.From = &d
+	}
+	if in.To != "" {
+		d, _ := time.Parse("2006-01-02", in.To)
+		v.To = &d
+	}
+	return v
+}
+
+func (h *ViewHandlers) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.S.List(UID(r))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rows)
+}
+
+func (h *ViewHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	v, err := h.S.Get(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, v)
+}
+
+func (h *ViewHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var in viewReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	v := in.toView()
+	if err := h.S.Create(UID(r), &v); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, v)
+}
+
+func (h *ViewHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	var in viewReq
+	if !BindAndValidate(w, r, &in) {
+		return
+	}
+	v := in.toView()
+	v.ID = pathID(r, "id")
+	if err := h.S.Update(UID(r), &v); err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, v)
+}
+
+func (h *ViewHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.S.Delete(UID(r), pathID(r, "id")); err != nil {
+		FromError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Transactions görünümü çağrı anındaki tarih aralığıyla değerlendirir.
+func (h *ViewHandlers) Transactions(w http.ResponseWriter, r *http.Request) {
+	page, size := clampPage(r)
+	rows, total, f, err := h.S.Transactions(UID(r), pathID(r, "id"), page, size)
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]any{"total": total, "data": rows, "from": f.From, "to": f.To})
+}
+
+func (h *ViewHandlers) Summary(w http.ResponseWriter, r *http.Request) {
+	out, err := h.S.Summary(UID(r), pathID(r, "id"))
+	if err != nil {
+		FromError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
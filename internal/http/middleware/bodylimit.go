@@ -10,3 +10,18 @@ func BodyLimit(n int64) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// BodyLimitFor belirtilen yollar için ayrı üst sınır uygular (arşiv yükleme gibi);
+// diğer istekler n ile sınırlanır.
+func BodyLimitFor(n int64, paths map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := n
+			if l, ok := paths[r.URL.Path]; ok {
+				limit = l
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -50,7 +50,7 @@ func Router(api *API) http.Handler {
 	r.Use(chimw.RequestID)
 	r.Use(chimw.RealIP)
 	r.Use(Common())
-	r.Use(imw.BodyLimit(1 << 20))
+	r.Use(imw.BodyLimitFor(1<<20, map[string]int64{"/v1/account/import": importMaxBody}))
 	r.Use(imw.Idempotency())
 	r.Use(imw.CorrelationID)
 
@@ -67,13 +67,25 @@ func Router(api *API) http.Handler {
 		r.With(httprate.LimitByIP(20, time.Minute)).Post("/auth/register", api.Auth.Register)
 		r.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/login", api.Auth.Login)
 		r.With(httprate.LimitByIP(30, time.Minute)).Post("/auth/refresh", api.Auth.Refresh)
+		r.With(httprate.LimitByIP(5, time.Minute)).Post("/auth/password/forgot", api.Auth.ForgotPassword)
+		r.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/password/reset", api.Auth.ResetPassword)
+		r.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/email/verify", api.Email.Verify)
+		r.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/email/change/confirm", api.Email.ChangeConfirm)
+		r.With(httprate.LimitByIP(10, time.Minute)).Get("/account/exports/{id}/download", api.Account.ExportDownload)
 
 		r.Group(func(pr chi.Router) {
 			pr.Use(Auth(api.Secret))
 
 			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/auth/logout", api.Auth.Logout)
+			pr.With(httprate.LimitByIP(5, time.Minute)).Post("/auth/password/change", api.Auth.ChangePassword)
 			pr.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/totp/setup", api.Auth.TotpSetup)
 			pr.With(httprate.LimitByIP(10, time.Minute)).Post("/auth/totp/confirm", api.Auth.TotpConfirm)
+			pr.With(httprate.LimitByIP(5, time.Minute)).Post("/auth/totp/disable", api.Auth.TotpDisable)
+			pr.With(httprate.LimitByIP(5, time.Minute)).Post("/auth/totp/recovery-codes", api.Auth.TotpRecoveryCodes)
+
+			pr.With(httprate.LimitByIP(60, time.Minute)).Get("/auth/email", api.Email.Status)
+			pr.With(httprate.LimitByIP(3, time.Minute)).Post("/auth/email/resend", api.Email.Resend)
+			pr.With(httprate.LimitByIP(3, time.Minute)).Post("/auth/email/change", api.Email.Change)
 
 			pr.With(httprate.LimitByIP(60, time.Minute)).Get("/auth/sessions", api.Auth.Sessions)
 			pr.With(httprate.LimitByIP(30, time.Minute)).Delete("/auth/sessions/{id}", api.Auth.SessionDelete)
@@ -86,19 +98,111 @@ func Router(api *API) http.Handler {
 			pr.With(httprate.LimitByIP(120, time.Minute)).Put("/transactions/{id}", api.H.TxUpdate)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Delete("/transactions/{id}", api.H.TxDelete)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/transactions/summary", api.H.TxSummary)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/transactions/upcoming", api.H.TxUpcoming)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/transactions/status", api.Recon.TxStatus)
 
 			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/sync/transactions", api.H.TxSince)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/sync/transactions", api.H.TxUpsertBatch)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/sync/categories", api.CatH.CategorySince)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/installments", api.Inst.List)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/installments/{id}", api.Inst.Get)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/installments", api.Inst.Create)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/installments/{id}/close", api.Inst.Close)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/installments/{id}/restructure", api.Inst.Restructure)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/contacts", api.Debt.ContactList)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/contacts", api.Debt.ContactCreate)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Put("/contacts/{id}", api.Debt.ContactUpdate)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Delete("/contacts/{id}", api.Debt.ContactDelete)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/debts", api.Debt.DebtList)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/debts/summary", api.Debt.DebtSummary)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/debts/{id}", api.Debt.DebtGet)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/debts", api.Debt.DebtCreate)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/debts/{id}/payments", api.Debt.DebtPayment)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/households", api.House.List)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/households", api.House.Create)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/households/accept", api.House.Accept)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/households/{id}", api.House.Get)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Delete("/households/{id}", api.House.Delete)
+			pr.With(httprate.LimitByIP(30, time.Minute), api.Email.Require("households.invite")).Post("/households/{id}/invites", api.House.Invite)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Put("/households/{id}/members/{userId}", api.House.SetRole)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Delete("/households/{id}/members/{userId}", api.House.RemoveMember)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/households/{id}/wallets", api.House.Wallets)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/households/{id}/wallets", api.House.WalletCreate)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/households/{id}/wallets/{walletId}/transactions", api.House.WalletTxList)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/households/{id}/wallets/{walletId}/transactions", api.House.WalletTxCreate)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Delete("/households/{id}/wallets/{walletId}/transactions/{txId}", api.House.WalletTxDelete)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/groups", api.Group.List)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/groups", api.Group.Create)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/groups/accept", api.Group.Accept)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/groups/{id}", api.Group.Get)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/groups/{id}/members", api.Group.AddMember)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/groups/{id}/expenses", api.Group.Expenses)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/groups/{id}/expenses", api.Group.AddExpense)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/groups/{id}/balances", api.Group.Balances)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/groups/{id}/settlements", api.Group.Settlements)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/groups/{id}/settlements", api.Group.Settle)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/assets", api.Invest.Assets)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/assets", api.Invest.AssetCreate)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/assets/{id}/prices", api.Invest.Prices)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/assets/{id}/prices", api.Invest.PriceSet)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/lots", api.Invest.Lots)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/lots", api.Invest.LotCreate)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/portfolio", api.Invest.Portfolio)
+
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/networth", api.Worth.Series)
+			pr.With(httprate.LimitByIP(10, time.Minute)).Put("/networth/currency", api.Worth.SetCurrency)
+			pr.With(httprate.LimitByIP(10, time.Minute)).Post("/networth/backfill", api.Worth.Backfill)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/reconciliations", api.Recon.List)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/reconciliations", api.Recon.Start)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/reconciliations/{id}", api.Recon.Get)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/reconciliations/{id}/lock", api.Recon.Lock)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/reconciliations/{id}/unlock", api.Recon.Unlock)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/views", api.Views.List)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Post("/views", api.Views.Create)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/views/{id}", api.Views.Get)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Put("/views/{id}", api.Views.Update)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Delete("/views/{id}", api.Views.Delete)
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/views/{id}/transactions", api.Views.Transactions)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/views/{id}/summary", api.Views.Summary)
+
+			pr.With(httprate.LimitByIP(30, time.Minute)).Get("/reports/tax", api.Report.Tax)
+
+			pr.With(httprate.LimitByIP(3, time.Minute)).Delete("/account", api.Account.Delete)
+			pr.With(httprate.LimitByIP(3, time.Minute), api.Email.Require("account.export")).Post("/account/export", api.Account.ExportStart)
+			pr.With(httprate.LimitByIP(5, time.Minute), api.Email.Require("account.import")).Post("/account/import", api.Account.ImportArchive)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Get("/account/exports", api.Account.ExportList)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/account/exports/{id}", api.Account.ExportGet)
+
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/onboarding/locales", api.Onboard.Locales)
+			pr.With(httprate.LimitByIP(10, time.Minute)).Post("/onboarding/reseed", api.Onboard.Reseed)
+
+			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/insights", api.Insight.List)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/insights/{id}/dismiss", api.Insight.Dismiss)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Get("/insights/subscriptions", api.Insight.Subscriptions)
 
 			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/wallets", api.CatH.WalletList)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/wallets", api.CatH.WalletCreate)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Put("/wallets/{id}", api.CatH.WalletUpdate)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Delete("/wallets/{id}", api.CatH.WalletDelete)
+			pr.With(httprate.LimitByIP(60, time.Minute)).Get("/wallets/{id}/statements", api.CatH.WalletStatements)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/wallets/{id}/archive", api.CatH.WalletArchive)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/wallets/{id}/unarchive", api.CatH.WalletUnarchive)
 
 			pr.With(httprate.LimitByIP(240, time.Minute)).Get("/categories", api.CatH.CategoryList)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/categories", api.CatH.CategoryCreate)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Put("/categories/{id}", api.CatH.CategoryUpdate)
 			pr.With(httprate.LimitByIP(120, time.Minute)).Delete("/categories/{id}", api.CatH.CategoryDelete)
+			pr.With(httprate.LimitByIP(30, time.Minute)).Post("/categories/{id}/merge", api.CatH.CategoryMerge)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/categories/{id}/archive", api.CatH.CategoryArchive)
+			pr.With(httprate.LimitByIP(120, time.Minute)).Post("/categories/{id}/unarchive", api.CatH.CategoryUnarchive)
 		})
 	})
 
@@ -16,6 +16,20 @@ func MaskEmail(s string) string {
 	return s
 }
 
+// MaskAccountNumber yalnızca son 4 haneyi bırakır: "TR12 0006 ... 1234" -> "**** 1234".
+func MaskAccountNumber(s string) string {
+	digits := make([]rune, 0, len(s))
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) <= 4 {
+		return "**** " + string(digits)
+	}
+	return "**** " + string(digits[len(digits)-4:])
+}
+
 func HashPII(s string) string {
 	h := sha256.Sum256([]byte(s))
 	return hex.EncodeToString(h[:8]) // kısa parmak izi
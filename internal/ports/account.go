@@ -0,0 +1,23 @@
+package ports
+
+import "time"
+
+// AccountDeletion bekleyen hesap silme talebi.
+type AccountDeletion struct {
+	RequestedAt time.Time `json:"requestedAt"`
+	DeleteAfter time.Time `json:"deleteAfter"`
+}
+
+type AccountRepo interface {
+	PassHash(userID int64) (string, error)
+	// RequestDeletion hesabı silinmeyi bekler duruma alır ve tüm oturumları kapatır.
+	RequestDeletion(userID int64, at, after time.Time) error
+	// CancelDeletion bekleyen talebi kaldırır; talep yoksa false döner.
+	CancelDeletion(userID int64) (bool, error)
+	DueDeletions(now time.Time, limit int) ([]int64, error)
+
+	// Purge kullanıcının tüm verisini tek işlemde, FK sırasına göre siler. Paylaşılan hane ve
+	// gruplar başka üyeye devredilir; devredilen cüzdanlardaki kayıtları cüzdan sahibine geçer,
+	// denetim kayıtları silinmez, anonimleştirilir.
+	Purge(userID int64) error
+}
@@ -26,6 +26,8 @@ type TotpSecret struct {
 type AuthRepo interface {
 	CreateUser(u *User) error
 	FindUserByEmail(email string) (*User, error)
+	FindUserByID(id int64) (*User, error)
+	SetPassword(userID int64, passHash string) error
 
 	StoreRefresh(userID int64, refreshHash string, expires time.Time) error
 	InvalidateRefresh(userID int64, refreshHash string) error
@@ -36,4 +38,22 @@ type AuthRepo interface {
 	GetTotp(userID int64) (*TotpSecret, error)
 	SetTotp(userID int64, secret string) error
 	ConfirmTotp(userID int64) error
+	// UseTotpStep adımı kullanılmış işaretler; adım son kullanılandan büyük değilse false döner.
+	UseTotpStep(userID, step int64) (bool, error)
+	// ReplaceRecoveryCodes önceki kurtarma kodlarını siler; yalnız SHA-256 özetleri saklanır.
+	ReplaceRecoveryCodes(userID int64, hashes []string) error
+	UseRecoveryCode(userID int64, hash string, at time.Time) (bool, error)
+	// DeleteTotp sırrı ve kurtarma kodlarını kaldırır.
+	DeleteTotp(userID int64) error
+}
+
+// PasswordResetRepo tek kullanımlık sıfırlama belirteçleri; yalnız SHA-256 özeti saklanır.
+type PasswordResetRepo interface {
+	CreateReset(userID int64, tokenHash string, created, expires time.Time) error
+	RecentResets(userID int64, since time.Time) (int, error)
+	// PeekReset geçerli belirtecin sahibini döner; kullanılmış/süresi geçmişse sql.ErrNoRows.
+	PeekReset(tokenHash string, now time.Time) (userID int64, email string, err error)
+	// CompleteReset belirteci tüketir, parolayı yazar ve kullanıcının diğer açık belirteçlerini
+	// geçersiz kılar; hepsi tek işlemde. Belirteç bu arada kullanılmışsa sql.ErrNoRows.
+	CompleteReset(tokenHash, passHash string, now time.Time) (userID int64, err error)
 }
@@ -1,16 +1,61 @@
 package ports
 
+import "time"
+
+const (
+	WalletCash       = "cash"
+	WalletBank       = "bank"
+	WalletCreditCard = "credit_card"
+	WalletSavings    = "savings"
+	WalletLoan       = "loan"
+)
+
 type Wallet struct {
-	ID       int64  `db:"id" json:"id"`
-	UserID   int64  `db:"user_id" json:"-"`
-	Name     string `db:"name" json:"name"`
-	Currency string `db:"currency" json:"currency"`
+	ID          int64    `db:"id" json:"id"`
+	UserID      int64    `db:"user_id" json:"-"`
+	HouseholdID *int64   `db:"household_id" json:"householdId,omitempty"`
+	Name        string   `db:"name" json:"name"`
+	Currency    string   `db:"currency" json:"currency"`
+	Kind        string   `db:"kind" json:"kind"`
+	CreditLimit *float64 `db:"credit_limit" json:"creditLimit,omitempty"`
+	// Kredi kartı hesap kesim ve son ödeme günleri (ayın günü, 1-31; kısa aylarda son güne çekilir).
+	StatementDay *int    `db:"statement_day" json:"statementDay,omitempty"`
+	DueDay       *int    `db:"due_day" json:"dueDay,omitempty"`
+	Institution  *string `db:"institution" json:"institution,omitempty"`
+	AccountMask  *string `db:"account_mask" json:"accountMask,omitempty"`
+
+	ArchivedAt *time.Time `db:"archived_at" json:"archivedAt,omitempty"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+
+	// Hesaplanan alanlar: hareketlerden türetilir, tabloda yok.
+	Balance         float64  `db:"-" json:"balance"`
+	AvailableCredit *float64 `db:"-" json:"availableCredit,omitempty"`
 }
 type Category struct {
-	ID     int64  `db:"id" json:"id"`
-	UserID int64  `db:"user_id" json:"-"`
-	Name   string `db:"name" json:"name"`
-	Type   string `db:"type" json:"type"`
+	ID        int64   `db:"id" json:"id"`
+	UserID    int64   `db:"user_id" json:"-"`
+	ParentID  *int64  `db:"parent_id" json:"parentId,omitempty"`
+	Name      string  `db:"name" json:"name"`
+	Type      string  `db:"type" json:"type"`
+	SortOrder int     `db:"sort_order" json:"sortOrder"`
+	Icon      *string `db:"icon" json:"icon,omitempty"`
+	Color     *string `db:"color" json:"color,omitempty"`
+
+	ArchivedAt *time.Time `db:"archived_at" json:"archivedAt,omitempty"`
+	MergedInto *int64     `db:"merged_into_id" json:"mergedInto,omitempty"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+
+	Children []Category `db:"-" json:"children,omitempty"`
+}
+
+// CategoryTotal: kategori bazlı özet satırı. Roll-up açıkken Total alt
+// kategorileri de içerir, Own yalnızca kategorinin kendi hareketleridir.
+type CategoryTotal struct {
+	CategoryID int64   `db:"category_id" json:"categoryId"`
+	ParentID   *int64  `db:"-"           json:"parentId,omitempty"`
+	Type       string  `db:"type"        json:"type"`
+	Own        float64 `db:"-"           json:"own"`
+	Total      float64 `db:"total"       json:"total"`
 }
 
 type WalletRepo interface {
@@ -18,10 +63,22 @@ type WalletRepo interface {
 	Create(userID int64, w *Wallet) error
 	Update(userID int64, w *Wallet) error
 	Delete(userID int64, id int64) error
+
+	Get(userID, id int64) (*Wallet, error)
+	SetArchived(userID, id int64, archived bool) error
+	// Balances cüzdan başına gelir - gider bakiyesini döner (silinmişler hariç).
+	Balances(userID int64) (map[int64]float64, error)
 }
 type CategoryRepo interface {
 	List(userID int64, typ string) ([]Category, error)
+	Get(userID, id int64) (*Category, error)
 	Create(userID int64, c *Category) error
 	Update(userID int64, c *Category) error
 	Delete(userID int64, id int64) error
+
+	ListSince(userID int64, since time.Time) ([]Category, error)
+	SetArchived(userID, id int64, archived bool) error
+	// Merge src'ye bağlı tüm işlemleri (silinmişler dahil) ve alt kategorileri
+	// dst'ye taşır, src'yi arşivler. Tek DB transaction'ında çalışır.
+	Merge(userID, srcID, dstID int64) (moved int64, err error)
 }
@@ -0,0 +1,88 @@
+package ports
+
+import "time"
+
+const (
+	DebtLent     = "lent"
+	DebtBorrowed = "borrowed"
+
+	DebtOpen    = "open"
+	DebtSettled = "settled"
+)
+
+type Contact struct {
+	ID        int64     `db:"id"         json:"id"`
+	UserID    int64     `db:"user_id"    json:"-"`
+	Name      string    `db:"name"       json:"name"`
+	Email     *string   `db:"email"      json:"email,omitempty"`
+	Phone     *string   `db:"phone"      json:"phone,omitempty"`
+	Note      *string   `db:"note"       json:"note,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type Debt struct {
+	ID        int64      `db:"id"         json:"id"`
+	UserID    int64      `db:"user_id"    json:"-"`
+	ContactID int64      `db:"contact_id" json:"contactId"`
+	Direction string     `db:"direction"  json:"direction"`
+	Amount    float64    `db:"amount"     json:"amount"`
+	Currency  string     `db:"currency"   json:"currency"`
+	DueAt     *time.Time `db:"due_at"     json:"dueAt,omitempty"`
+	Note      *string    `db:"note"       json:"note,omitempty"`
+	Status    string     `db:"status"     json:"status"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updatedAt"`
+	SettledAt *time.Time `db:"settled_at" json:"settledAt,omitempty"`
+
+	Paid      float64       `db:"paid"      json:"paid"`
+	Remaining float64       `db:"-"         json:"remaining"`
+	Payments  []DebtPayment `db:"-"         json:"payments,omitempty"`
+}
+
+type DebtPayment struct {
+	ID            int64     `db:"id"             json:"id"`
+	DebtID        int64     `db:"debt_id"        json:"debtId"`
+	UserID        int64     `db:"user_id"        json:"-"`
+	Amount        float64   `db:"amount"         json:"amount"`
+	PaidAt        time.Time `db:"paid_at"        json:"paidAt"`
+	TransactionID *int64    `db:"transaction_id" json:"transactionId,omitempty"`
+	Note          *string   `db:"note"           json:"note,omitempty"`
+	CreatedAt     time.Time `db:"created_at"     json:"createdAt"`
+}
+
+// DebtBalance kişi + para birimi bazında açık borç/alacak durumu. Net > 0 ise kişi bize borçlu.
+type DebtBalance struct {
+	ContactID   int64   `db:"contact_id"   json:"contactId"`
+	ContactName string  `db:"contact_name" json:"contactName"`
+	Currency    string  `db:"currency"     json:"currency"`
+	OwedToMe    float64 `db:"owed_to_me"   json:"owedToMe"`
+	IOwe        float64 `db:"i_owe"        json:"iOwe"`
+	Net         float64 `db:"net"          json:"net"`
+}
+
+type DebtFilter struct {
+	ContactID int64
+	Status    string
+	// OverdueAt verilirse yalnızca bu andan önce vadesi geçmiş açık kayıtlar döner.
+	OverdueAt *time.Time
+}
+
+type ContactRepo interface {
+	List(userID int64) ([]Contact, error)
+	Get(userID, id int64) (*Contact, error)
+	Create(userID int64, c *Contact) error
+	Update(userID int64, c *Contact) error
+	Delete(userID, id int64) error
+}
+
+type DebtRepo interface {
+	List(userID int64, f DebtFilter) ([]Debt, error)
+	Get(userID, id int64) (*Debt, error)
+	Create(userID int64, d *Debt) error
+	Payments(userID, debtID int64) ([]DebtPayment, error)
+	// AddPayment ödemeyi (ve verilirse bağlı işlemi) yazar, borç tamamen
+	// kapandıysa settled işaretler; hepsi tek transaction'dadır.
+	AddPayment(userID int64, p *DebtPayment, t *Transaction) error
+	Summary(userID int64) ([]DebtBalance, error)
+}
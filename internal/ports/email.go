@@ -0,0 +1,39 @@
+package ports
+
+import "time"
+
+// E-posta değişikliğinde onaylanan taraf.
+const (
+	EmailSideOld = "old"
+	EmailSideNew = "new"
+)
+
+type EmailStatus struct {
+	UserID     int64      `db:"id"          json:"userId"`
+	Email      string     `db:"email"       json:"email"`
+	PassHash   string     `db:"pass_hash"   json:"-"`
+	VerifiedAt *time.Time `db:"verified_at" json:"verifiedAt,omitempty"`
+}
+
+type EmailChange struct {
+	ID             int64      `db:"id"               json:"id"`
+	UserID         int64      `db:"user_id"          json:"userId"`
+	NewEmail       string     `db:"new_email"        json:"newEmail"`
+	OldConfirmedAt *time.Time `db:"old_confirmed_at" json:"oldConfirmedAt,omitempty"`
+	NewConfirmedAt *time.Time `db:"new_confirmed_at" json:"newConfirmedAt,omitempty"`
+	CompletedAt    *time.Time `db:"completed_at"     json:"completedAt,omitempty"`
+	ExpiresAt      time.Time  `db:"expires_at"       json:"expiresAt"`
+	CreatedAt      time.Time  `db:"created_at"       json:"createdAt"`
+}
+
+type EmailRepo interface {
+	EmailStatus(userID int64) (*EmailStatus, error)
+	// MarkVerified adres hâlâ email ise kullanıcıyı doğrulanmış işaretler.
+	MarkVerified(userID int64, email string, at time.Time) error
+	EmailTaken(email string, exceptUserID int64) (bool, error)
+	// CreateChange kullanıcının açık değişiklik talebini yenisiyle değiştirir.
+	CreateChange(c *EmailChange) error
+	// ConfirmChange bir tarafı onaylar; iki taraf da onaylıysa adresi aynı işlemde günceller.
+	// Bulunamayan, süresi geçmiş ya da tamamlanmış talep sql.ErrNoRows döner.
+	ConfirmChange(id int64, side string, now time.Time) (*EmailChange, error)
+}
@@ -0,0 +1,64 @@
+package ports
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	ExportQueued  = "queued"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+)
+
+// ExportDatasets arşive giren veri kümeleri; her biri JSON ve CSV olarak yazılır.
+var ExportDatasets = []string{"profile", "wallets", "categories", "transactions", "sessions", "devices", "audit_logs"}
+
+type DataExport struct {
+	ID          int64      `db:"id"           json:"id"`
+	UserID      int64      `db:"user_id"      json:"-"`
+	Status      string     `db:"status"       json:"status"`
+	FileName    *string    `db:"file_name"    json:"-"`
+	Size        *int64     `db:"size_bytes"   json:"size,omitempty"`
+	SHA256      *string    `db:"sha256"       json:"sha256,omitempty"`
+	Error       *string    `db:"error"        json:"error,omitempty"`
+	Attempts    int        `db:"attempts"     json:"-"`
+	CreatedAt   time.Time  `db:"created_at"   json:"createdAt"`
+	StartedAt   *time.Time `db:"started_at"   json:"startedAt,omitempty"`
+	CompletedAt *time.Time `db:"completed_at" json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `db:"expires_at"   json:"expiresAt,omitempty"`
+
+	// DownloadURL hazır arşiv için süreli, imzalı indirme bağlantısı.
+	DownloadURL   string     `db:"-" json:"downloadUrl,omitempty"`
+	LinkExpiresAt *time.Time `db:"-" json:"linkExpiresAt,omitempty"`
+}
+
+type ExportRepo interface {
+	Create(e *DataExport) error
+	// Active kullanıcının sıradaki ya da çalışan dışa aktarımı; yoksa sql.ErrNoRows.
+	Active(userID int64) (*DataExport, error)
+	Get(userID, id int64) (*DataExport, error)
+	GetByID(id int64) (*DataExport, error)
+	List(userID int64) ([]DataExport, error)
+	// Claim sıradaki işi running'e çeker, started_at'i yazar ve denemeyi sayar; iş yoksa (nil, nil).
+	Claim() (*DataExport, error)
+	// ReclaimStale started_at'i before'dan eski running işleri, deneme hakkı kalmışsa
+	// kuyruğa geri alır, kalmamışsa failed yapar.
+	ReclaimStale(before time.Time, maxAttempts int) (requeued, failed int64, err error)
+	Finish(id int64, file string, size int64, sha string, completed, expires time.Time) error
+	Fail(id int64, msg string) error
+	ExpiredBefore(now time.Time) ([]DataExport, error)
+	MarkExpired(id int64) error
+
+	// Stream veri kümesinin satırlarını sırayla fn'e verir; sonuç belleğe toplanmaz.
+	Stream(userID int64, dataset string, fn func(cols []string, vals []any) error) error
+}
+
+// BlobStore arşiv dosyalarının saklandığı yer (yerel disk, nesne deposu).
+type BlobStore interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+	Remove(name string) error
+}
@@ -0,0 +1,112 @@
+package ports
+
+import "time"
+
+const (
+	SplitEqual  = "equal"
+	SplitShares = "shares"
+	SplitExact  = "exact"
+)
+
+// ExpenseGroup: gezi, ev arkadaşları vb. için ortak harcama grubu. Kişisel
+// defterden (transactions) ayrıdır; bağlantı transaction_id alanlarıyla kurulur.
+type ExpenseGroup struct {
+	ID        int64     `db:"id"         json:"id"`
+	OwnerID   int64     `db:"owner_id"   json:"ownerId"`
+	Name      string    `db:"name"       json:"name"`
+	Currency  string    `db:"currency"   json:"currency"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+
+	Members []GroupMember `db:"-" json:"members,omitempty"`
+}
+
+// GroupMember kayıtlı bir kullanıcıya bağlı olabilir ya da yalnızca isimden ibarettir.
+// E-posta ile eklenen üye, davetli kullanıcı kabul edene kadar bağlanmaz.
+type GroupMember struct {
+	ID              int64      `db:"id"                json:"id"`
+	GroupID         int64      `db:"group_id"          json:"groupId"`
+	UserID          *int64     `db:"user_id"           json:"userId,omitempty"`
+	Name            string     `db:"name"              json:"name"`
+	InviteEmail     *string    `db:"invite_email"      json:"inviteEmail,omitempty"`
+	InviteExpiresAt *time.Time `db:"invite_expires_at" json:"inviteExpiresAt,omitempty"`
+	CreatedAt       time.Time  `db:"created_at"        json:"createdAt"`
+
+	// Token yalnızca üye eklenirken bir kez döner; saklanan hash'tir.
+	InviteToken string `db:"-" json:"inviteToken,omitempty"`
+}
+
+type GroupExpense struct {
+	ID            int64     `db:"id"             json:"id"`
+	GroupID       int64     `db:"group_id"       json:"groupId"`
+	PaidBy        int64     `db:"paid_by"        json:"paidBy"`
+	Amount        float64   `db:"amount"         json:"amount"`
+	Description   string    `db:"description"    json:"description"`
+	SplitMode     string    `db:"split_mode"     json:"splitMode"`
+	OccurredAt    time.Time `db:"occurred_at"    json:"occurredAt"`
+	CreatedBy     int64     `db:"created_by"     json:"createdBy"`
+	TransactionID *int64    `db:"transaction_id" json:"transactionId,omitempty"`
+	CreatedAt     time.Time `db:"created_at"     json:"createdAt"`
+
+	Shares []GroupShare `db:"-" json:"shares"`
+}
+
+type GroupShare struct {
+	ExpenseID int64   `db:"expense_id" json:"-"`
+	MemberID  int64   `db:"member_id"  json:"memberId"`
+	Amount    float64 `db:"amount"     json:"amount"`
+}
+
+type GroupSettlement struct {
+	ID         int64     `db:"id"          json:"id"`
+	GroupID    int64     `db:"group_id"    json:"groupId"`
+	FromMember int64     `db:"from_member" json:"fromMember"`
+	ToMember   int64     `db:"to_member"   json:"toMember"`
+	Amount     float64   `db:"amount"      json:"amount"`
+	SettledAt  time.Time `db:"settled_at"  json:"settledAt"`
+	FromTxID   *int64    `db:"from_tx_id"  json:"fromTransactionId,omitempty"`
+	ToTxID     *int64    `db:"to_tx_id"    json:"toTransactionId,omitempty"`
+	CreatedBy  int64     `db:"created_by"  json:"createdBy"`
+	CreatedAt  time.Time `db:"created_at"  json:"createdAt"`
+}
+
+// GroupBalance: Net > 0 ise üye alacaklı, < 0 ise borçlu.
+type GroupBalance struct {
+	MemberID int64   `json:"memberId"`
+	Name     string  `json:"name"`
+	Paid     float64 `json:"paid"`
+	Owed     float64 `json:"owed"`
+	Net      float64 `json:"net"`
+}
+
+// SettleUp borçludan alacaklıya önerilen ödeme.
+type SettleUp struct {
+	FromMember int64   `json:"fromMember"`
+	ToMember   int64   `json:"toMember"`
+	Amount     float64 `json:"amount"`
+}
+
+type GroupRepo interface {
+	Create(ownerID int64, g *ExpenseGroup, owner *GroupMember) error
+	ListForUser(userID int64) ([]ExpenseGroup, error)
+	Get(id int64) (*ExpenseGroup, error)
+
+	Members(groupID int64) ([]GroupMember, error)
+	// MemberByUser kullanıcı grupta değilse sql.ErrNoRows döner.
+	MemberByUser(groupID, userID int64) (*GroupMember, error)
+	// AddMember tokenHash boş değilse üyeyi davet bekleyen olarak yazar.
+	AddMember(m *GroupMember, tokenHash string) error
+	// MemberByInvite bekleyen daveti bulamazsa sql.ErrNoRows döner.
+	MemberByInvite(tokenHash string) (*GroupMember, error)
+	// LinkInvited üyeyi, e-postası davetle eşleşen kullanıcıya bağlar ve daveti kapatır;
+	// eşleşme yoksa sql.ErrNoRows döner.
+	LinkInvited(memberID, userID int64) error
+
+	// AddExpense gider, paylar ve (verilirse) ödeyenin kişisel işlemini tek transaction'da yazar.
+	AddExpense(e *GroupExpense, t *Transaction) error
+	Expenses(groupID int64) ([]GroupExpense, error)
+	Shares(groupID int64) ([]GroupShare, error)
+
+	// AddSettlement ödeme kaydını ve verilen taraf işlemlerini (t.UserID adına) birlikte yazar.
+	AddSettlement(s *GroupSettlement, fromTx, toTx *Transaction) error
+	Settlements(groupID int64) ([]GroupSettlement, error)
+}
@@ -0,0 +1,73 @@
+package ports
+
+import "time"
+
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type Household struct {
+	ID        int64     `db:"id"         json:"id"`
+	Name      string    `db:"name"       json:"name"`
+	CreatedBy int64     `db:"created_by" json:"createdBy"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	// Role: isteği yapan kullanıcının bu hanedeki rolü.
+	Role string `db:"role" json:"role"`
+
+	Members []HouseholdMember `db:"-" json:"members,omitempty"`
+}
+
+type HouseholdMember struct {
+	HouseholdID int64     `db:"household_id" json:"householdId"`
+	UserID      int64     `db:"user_id"      json:"userId"`
+	Name        string    `db:"name"         json:"name"`
+	Email       string    `db:"email"        json:"email"`
+	Role        string    `db:"role"         json:"role"`
+	JoinedAt    time.Time `db:"joined_at"    json:"joinedAt"`
+}
+
+type HouseholdInvite struct {
+	ID          int64      `db:"id"           json:"id"`
+	HouseholdID int64      `db:"household_id" json:"householdId"`
+	Email       string     `db:"email"        json:"email"`
+	Role        string     `db:"role"         json:"role"`
+	InvitedBy   int64      `db:"invited_by"   json:"invitedBy"`
+	ExpiresAt   time.Time  `db:"expires_at"   json:"expiresAt"`
+	AcceptedAt  *time.Time `db:"accepted_at"  json:"acceptedAt,omitempty"`
+	CreatedAt   time.Time  `db:"created_at"   json:"createdAt"`
+
+	// Token yalnızca oluşturma yanıtında döner; tabloda hash'i tutulur.
+	Token string `db:"-" json:"token,omitempty"`
+}
+
+type HouseholdRepo interface {
+	Create(ownerID int64, h *Household) error
+	ListForUser(userID int64) ([]Household, error)
+	Get(userID, id int64) (*Household, error)
+	Delete(id int64) error
+
+	Members(householdID int64) ([]HouseholdMember, error)
+	// Role kullanıcı üye değilse sql.ErrNoRows döner.
+	Role(householdID, userID int64) (string, error)
+	SetRole(householdID, userID int64, role string) error
+	RemoveMember(householdID, userID int64) error
+
+	CreateInvite(inv *HouseholdInvite, tokenHash string) error
+	InviteByHash(tokenHash string) (*HouseholdInvite, error)
+	// AcceptInvite daveti kullanıldı olarak işaretler ve kullanıcıyı üye ekler (tek transaction).
+	AcceptInvite(inv *HouseholdInvite, userID int64) error
+	UserEmail(userID int64) (string, error)
+
+	Wallets(householdID int64) ([]Wallet, error)
+	Wallet(householdID, walletID int64) (*Wallet, error)
+	WalletBalances(householdID int64) (map[int64]float64, error)
+	// WalletRole: kişisel cüzdanın sahibi için owner, hane cüzdanı için üyelik rolü;
+	// erişim yoksa sql.ErrNoRows.
+	WalletRole(userID, walletID int64) (string, error)
+
+	WalletTransactions(walletID int64, page, size int) ([]Transaction, int, error)
+	WalletTransaction(walletID, txID int64) (*Transaction, error)
+	SoftDeleteWalletTx(walletID, txID int64) error
+}
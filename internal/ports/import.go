@@ -0,0 +1,38 @@
+package ports
+
+const (
+	// ImportMerge aynı adlı cüzdan/kategoriyi mevcut kayıtla birleştirir.
+	ImportMerge = "merge"
+	// ImportRename çakışan adları "Ad (2)" biçiminde yeni kayıt olarak ekler.
+	ImportRename = "rename"
+)
+
+// ImportArchive arşivden okunan veri; kimlikler arşivdeki (eski sunucudaki) kimliklerdir.
+type ImportArchive struct {
+	Version      int
+	Wallets      []Wallet
+	Categories   []Category
+	Transactions []Transaction
+}
+
+type ImportCounts struct {
+	Created int `json:"created"`
+	Merged  int `json:"merged"`
+	Renamed int `json:"renamed"`
+	Skipped int `json:"skipped"`
+}
+
+type ImportResult struct {
+	DryRun        bool         `json:"dryRun"`
+	SchemaVersion int          `json:"schemaVersion"`
+	Conflict      string       `json:"conflict"`
+	Wallets       ImportCounts `json:"wallets"`
+	Categories    ImportCounts `json:"categories"`
+	Transactions  ImportCounts `json:"transactions"`
+}
+
+type ImportRepo interface {
+	// Import arşivi tek veritabanı işleminde kullanıcıya yazar; dryRun'da aynı işlem
+	// sonunda geri alınır, böylece önizleme gerçek içe aktarmayla aynı sonucu verir.
+	Import(userID int64, a *ImportArchive, conflict string, dryRun bool) (ImportResult, error)
+}
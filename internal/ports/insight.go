@@ -0,0 +1,44 @@
+package ports
+
+import "time"
+
+const (
+	InsightCategorySpike = "category_spike"
+	InsightNewMerchant   = "new_merchant"
+	InsightLargeTx       = "large_transaction"
+	InsightCategoryMoM   = "category_mom"
+	InsightCategoryYoY   = "category_yoy"
+
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityAlert   = "alert"
+)
+
+// Insight arka planda üretilen bulgu. Fingerprint aynı bulgunun tekrar üretildiğinde
+// tekilleşmesini sağlar; kapatılan (dismissed) bulgu yeniden açılmaz.
+type Insight struct {
+	ID            int64              `json:"id"`
+	UserID        int64              `json:"-"`
+	Kind          string             `json:"kind"`
+	Severity      string             `json:"severity"`
+	Fingerprint   string             `json:"-"`
+	Title         string             `json:"title"`
+	Period        time.Time          `json:"period"`
+	CategoryID    *int64             `json:"categoryId,omitempty"`
+	TransactionID *int64             `json:"transactionId,omitempty"`
+	Currency      string             `json:"currency,omitempty"`
+	Data          map[string]float64 `json:"data"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	DismissedAt   *time.Time         `json:"dismissedAt,omitempty"`
+}
+
+type InsightRepo interface {
+	// ChangedUsers son taramadan sonra gideri değişen ya da taraması monthStart'tan eski kullanıcılar.
+	ChangedUsers(monthStart time.Time) ([]int64, error)
+	// Expenses since sonrası silinmemiş gider işlemleri (occurred_at artan).
+	Expenses(userID int64, since time.Time) ([]Transaction, error)
+	// Replace artık üretilmeyen açık bulguları siler, yenileri ekler/günceller ve tarama zamanını yazar.
+	Replace(userID int64, items []Insight, scannedAt time.Time) error
+	List(userID int64) ([]Insight, error)
+	Dismiss(userID, id int64) error
+}
@@ -0,0 +1,38 @@
+package ports
+
+import "time"
+
+type InstallmentPlan struct {
+	ID          int64      `db:"id"                json:"id"`
+	UserID      int64      `db:"user_id"           json:"-"`
+	WalletID    int64      `db:"wallet_id"         json:"walletId"`
+	CategoryID  int64      `db:"category_id"       json:"categoryId"`
+	TotalAmount float64    `db:"total_amount"      json:"totalAmount"`
+	Currency    string     `db:"currency"          json:"currency"`
+	Count       int        `db:"installment_count" json:"count"`
+	FirstAt     time.Time  `db:"first_at"          json:"firstAt"`
+	Note        *string    `db:"note"              json:"note,omitempty"`
+	Remainder   string     `db:"remainder"         json:"remainder"`
+	Status      string     `db:"status"            json:"status"`
+	CreatedAt   time.Time  `db:"created_at"        json:"createdAt"`
+	UpdatedAt   time.Time  `db:"updated_at"        json:"updatedAt"`
+	ClosedAt    *time.Time `db:"closed_at"         json:"closedAt,omitempty"`
+
+	// Hesaplanan alanlar
+	PaidCount       int           `db:"-" json:"paidCount"`
+	RemainingCount  int           `db:"-" json:"remainingCount"`
+	RemainingAmount float64       `db:"-" json:"remainingAmount"`
+	Items           []Transaction `db:"-" json:"items,omitempty"`
+}
+
+type InstallmentRepo interface {
+	ListPlans(userID int64, status string) ([]InstallmentPlan, error)
+	GetPlan(userID, id int64) (*InstallmentPlan, error)
+	// Items planın silinmemiş taksit işlemlerini taksit sırasına göre döner.
+	Items(userID, planID int64) ([]Transaction, error)
+	// CreatePlan plan satırını ve taksit işlemlerini tek transaction'da yazar.
+	CreatePlan(userID int64, p *InstallmentPlan, items []Transaction) error
+	// Replace planın removeIDs işlemlerini soft-delete eder, add işlemlerini
+	// plana bağlı olarak ekler ve planı p ile günceller; hepsi atomiktir.
+	Replace(userID int64, p *InstallmentPlan, removeIDs []int64, add []Transaction) error
+}
@@ -0,0 +1,108 @@
+package ports
+
+import "time"
+
+const (
+	LotBuy  = "buy"
+	LotSell = "sell"
+
+	CostFIFO    = "fifo"
+	CostAverage = "avg"
+
+	PriceManual   = "manual"
+	PriceProvider = "provider"
+)
+
+// Asset kullanıcının takip ettiği yatırım aracı (hisse, fon, gram altın, kripto...).
+// Currency fiyatın kote edildiği para birimidir.
+type Asset struct {
+	ID        int64     `db:"id"         json:"id"`
+	UserID    int64     `db:"user_id"    json:"-"`
+	Symbol    string    `db:"symbol"     json:"symbol"`
+	Name      string    `db:"name"       json:"name"`
+	Kind      string    `db:"kind"       json:"kind"`
+	Unit      string    `db:"unit"       json:"unit"`
+	Currency  string    `db:"currency"   json:"currency"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+type Lot struct {
+	ID        int64     `db:"id"         json:"id"`
+	UserID    int64     `db:"user_id"    json:"-"`
+	WalletID  int64     `db:"wallet_id"  json:"walletId"`
+	AssetID   int64     `db:"asset_id"   json:"assetId"`
+	Side      string    `db:"side"       json:"side"`
+	Quantity  float64   `db:"quantity"   json:"quantity"`
+	Price     float64   `db:"price"      json:"price"`
+	Fee       float64   `db:"fee"        json:"fee"`
+	TradedAt  time.Time `db:"traded_at"  json:"tradedAt"`
+	Note      *string   `db:"note"       json:"note,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+type AssetPrice struct {
+	AssetID int64     `db:"asset_id"   json:"assetId"`
+	Date    time.Time `db:"price_date" json:"date"`
+	Price   float64   `db:"price"      json:"price"`
+	Source  string    `db:"source"     json:"source"`
+}
+
+// Position bir varlıktaki açık pozisyon. Quantity/AvgCost/Price varlığın kendi
+// para biriminde; diğer tutarlar portföy para birimine çevrilmiştir.
+type Position struct {
+	AssetID  int64      `json:"assetId"`
+	Symbol   string     `json:"symbol"`
+	Name     string     `json:"name"`
+	Kind     string     `json:"kind"`
+	Unit     string     `json:"unit"`
+	Currency string     `json:"currency"`
+	Quantity float64    `json:"quantity"`
+	AvgCost  float64    `json:"avgCost"`
+	Price    *float64   `json:"price,omitempty"`
+	PriceAt  *time.Time `json:"priceAt,omitempty"`
+
+	CostBasis   float64 `json:"costBasis"`
+	MarketValue float64 `json:"marketValue"`
+	Unrealized  float64 `json:"unrealized"`
+	Realized    float64 `json:"realized"`
+	Weight      float64 `json:"weight"`
+}
+
+// PortfolioPeriod dönem performansı: Gain = End - Start - NetFlows (alış - satış).
+type PortfolioPeriod struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	StartValue float64   `json:"startValue"`
+	EndValue   float64   `json:"endValue"`
+	NetFlows   float64   `json:"netFlows"`
+	Gain       float64   `json:"gain"`
+	Return     float64   `json:"return"`
+}
+
+type Portfolio struct {
+	Currency   string          `json:"currency"`
+	Method     string          `json:"method"`
+	Positions  []Position      `json:"positions"`
+	TotalValue float64         `json:"totalValue"`
+	TotalCost  float64         `json:"totalCost"`
+	Unrealized float64         `json:"unrealized"`
+	Realized   float64         `json:"realized"`
+	Period     PortfolioPeriod `json:"period"`
+}
+
+type InvestmentRepo interface {
+	ListAssets(userID int64) ([]Asset, error)
+	GetAsset(userID, id int64) (*Asset, error)
+	CreateAsset(userID int64, a *Asset) error
+	// AssetsForRefresh sağlayıcıdan fiyat çekilecek tüm varlıklar (tüm kullanıcılar).
+	AssetsForRefresh() ([]Asset, error)
+
+	// ListLots walletID > 0 ise yalnızca o cüzdanın lotlarını döner; traded_at artan sırada.
+	ListLots(userID, walletID int64) ([]Lot, error)
+	AddLot(userID int64, l *Lot) error
+
+	UpsertPrice(p *AssetPrice) error
+	Prices(userID, assetID int64, from, to time.Time) ([]AssetPrice, error)
+	// PriceAt verilen tarihteki ya da öncesindeki son fiyat; yoksa sql.ErrNoRows.
+	PriceAt(assetID int64, at time.Time) (*AssetPrice, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// Mailer giden e-posta kanalı (SMTP, dosya, log).
+type Mailer interface {
+	Send(m Mail) error
+}
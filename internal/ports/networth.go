@@ -0,0 +1,90 @@
+package ports
+
+import "time"
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// NetWorthSnapshot bir cüzdanın gün sonu bakiyesi; Value, kullanıcının payının
+// BaseCurrency'ye çevrilmiş tutarıdır. Liability kredi kartı ve kredi cüzdanlarını işaretler.
+type NetWorthSnapshot struct {
+	UserID       int64     `db:"user_id"       json:"-"`
+	Date         time.Time `db:"snap_date"     json:"date"`
+	WalletID     int64     `db:"wallet_id"     json:"walletId"`
+	Currency     string    `db:"currency"      json:"currency"`
+	Balance      float64   `db:"balance"       json:"balance"`
+	BaseCurrency string    `db:"base_currency" json:"baseCurrency"`
+	Value        float64   `db:"value"         json:"value"`
+	Liability    bool      `db:"liability"     json:"liability"`
+
+	Share float64 `db:"-" json:"-"`
+}
+
+// WalletDelta bir cüzdanın bir gündeki net hareketi (gelir - gider).
+type WalletDelta struct {
+	WalletID int64     `db:"wallet_id"`
+	Day      time.Time `db:"day"`
+	Amount   float64   `db:"amount"`
+}
+
+// WalletBalance: Share kişisel cüzdanda 1, hane cüzdanında üye sayısına bölünmüş paydır.
+type WalletBalance struct {
+	WalletID int64   `db:"wallet_id"`
+	Currency string  `db:"currency"`
+	Kind     string  `db:"kind"`
+	Share    float64 `db:"share"`
+	Balance  float64 `db:"balance"`
+}
+
+// FXRate bir günün kuru: 1 Base = Rate Quote.
+type FXRate struct {
+	Date  time.Time `db:"rate_date"`
+	Quote string    `db:"quote"`
+	Rate  float64   `db:"rate"`
+}
+
+// NetWorthPoint grafik noktası: dönemin son anlık görüntüsü.
+type NetWorthPoint struct {
+	Date        time.Time `json:"date"`
+	Assets      float64   `json:"assets"`
+	Liabilities float64   `json:"liabilities"`
+	NetWorth    float64   `json:"netWorth"`
+}
+
+type NetWorthSeries struct {
+	Currency string          `json:"currency"`
+	Interval string          `json:"interval"`
+	Points   []NetWorthPoint `json:"points"`
+}
+
+type NetWorthRepo interface {
+	UserIDs() ([]int64, error)
+	BaseCurrency(userID int64) (string, error)
+	SetBaseCurrency(userID int64, currency string) error
+
+	// BalancesBefore kişisel ve üyesi olunan hane cüzdanlarının before anından önceki
+	// bakiyeleri (hareketi olmayanlar 0).
+	BalancesBefore(userID int64, before time.Time) ([]WalletBalance, error)
+	// DailyDeltas [from, to) aralığındaki gün/cüzdan bazlı net hareketler.
+	DailyDeltas(userID int64, from, to time.Time) ([]WalletDelta, error)
+
+	// ReplaceSnapshots [from, to] günlerinin kayıtlarını silip rows ile değiştirir (tek transaction).
+	ReplaceSnapshots(userID int64, from, to time.Time, rows []NetWorthSnapshot) error
+	Snapshots(userID int64, from, to time.Time) ([]NetWorthSnapshot, error)
+	FirstSnapshotDate(userID int64) (*time.Time, error)
+
+	SaveRates(base string, day time.Time, rates map[string]float64) error
+	// RatesBetween quotes için [from, to] aralığındaki kurları ve from'dan önceki son kuru
+	// tarih sırasıyla döner.
+	RatesBetween(base string, quotes []string, from, to time.Time) ([]FXRate, error)
+
+	// MarkStale since'i mevcut kayıttan daha erkense günceller; kullanıcıyla hane paylaşan
+	// üyeler de işaretlenir çünkü ortak cüzdan bakiyeleri onların görüntülerinde de yer alır.
+	MarkStale(userID int64, since time.Time) error
+	Stale() (map[int64]time.Time, error)
+	// ClearStale yalnızca kayıt hâlâ since ise siler; arada daha erken bir işaret gelmişse korunur.
+	ClearStale(userID int64, since time.Time) error
+}
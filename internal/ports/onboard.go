@@ -0,0 +1,36 @@
+package ports
+
+// TemplateCategory alt kategoriler ebeveynin türünü devralır.
+type TemplateCategory struct {
+	Name     string             `json:"name"`
+	Type     string             `json:"type,omitempty"`
+	Icon     *string            `json:"icon,omitempty"`
+	Color    *string            `json:"color,omitempty"`
+	Children []TemplateCategory `json:"children,omitempty"`
+}
+
+type TemplateWallet struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// OnboardTemplate bir dil için başlangıç cüzdanları, kategori ağacı ve ana para birimi.
+type OnboardTemplate struct {
+	Locale     string             `json:"locale"`
+	Currency   string             `json:"currency"`
+	Wallets    []TemplateWallet   `json:"wallets"`
+	Categories []TemplateCategory `json:"categories"`
+}
+
+type SeedResult struct {
+	Locale     string `json:"locale"`
+	Wallets    int    `json:"walletsAdded"`
+	Categories int    `json:"categoriesAdded"`
+}
+
+type OnboardRepo interface {
+	Locale(userID int64) (string, error)
+	// Apply şablondaki eksik cüzdan ve kategorileri tek transaction'da ekler; mevcutlar
+	// (arşivlenmiş olanlar dahil) atlanır. initial ise dil ve ana para birimi de atanır.
+	Apply(userID int64, tpl OnboardTemplate, initial bool) (SeedResult, error)
+}
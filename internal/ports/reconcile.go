@@ -0,0 +1,47 @@
+package ports
+
+import "time"
+
+const (
+	ReconOpen     = "open"
+	ReconLocked   = "locked"
+	ReconUnlocked = "unlocked"
+)
+
+// Reconciliation bir cüzdanın banka ekstresiyle mutabakat oturumu. Açık oturumlarda
+// ClearedBalance/Difference anlık hesaplanır; kilitlenince o anki değerler saklanır.
+type Reconciliation struct {
+	ID             int64      `db:"id"              json:"id"`
+	UserID         int64      `db:"user_id"         json:"-"`
+	WalletID       int64      `db:"wallet_id"       json:"walletId"`
+	StatementDate  time.Time  `db:"statement_date"  json:"statementDate"`
+	EndingBalance  float64    `db:"ending_balance"  json:"endingBalance"`
+	ClearedBalance *float64   `db:"cleared_balance" json:"clearedBalance"`
+	Difference     *float64   `db:"difference"      json:"difference"`
+	Status         string     `db:"status"          json:"status"`
+	CreatedAt      time.Time  `db:"created_at"      json:"createdAt"`
+	LockedAt       *time.Time `db:"locked_at"       json:"lockedAt,omitempty"`
+
+	ClearedCount int `db:"-" json:"clearedCount"`
+	PendingCount int `db:"-" json:"pendingCount"`
+}
+
+// ClearedTotals ekstre tarihine kadar (dahil) cüzdandaki işlemlerin durum bazlı özeti.
+type ClearedTotals struct {
+	Balance      float64 `db:"balance"`
+	ClearedCount int     `db:"cleared_count"`
+	PendingCount int     `db:"pending_count"`
+}
+
+type ReconcileRepo interface {
+	Create(userID int64, r *Reconciliation) error
+	Get(userID, id int64) (*Reconciliation, error)
+	List(userID, walletID int64) ([]Reconciliation, error)
+
+	// Cleared: cleared + reconciled işlemlerin bakiyesi, before öncesi.
+	Cleared(walletID int64, before time.Time) (ClearedTotals, error)
+	// Lock before öncesi cleared işlemleri reconciled yapar ve oturumu kilitler (tek transaction).
+	Lock(r *Reconciliation, before time.Time) (int64, error)
+	// Unlock oturuma bağlı işlemleri cleared'a döndürür ve oturumu açar.
+	Unlock(r *Reconciliation) (int64, error)
+}
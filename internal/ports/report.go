@@ -0,0 +1,63 @@
+package ports
+
+import "time"
+
+// TaxRow ay/para birimi/tür/oran/indirilebilirlik kırılımında vergi toplamı.
+// Rate nil ise işlem vergisizdir.
+type TaxRow struct {
+	Month      int      `db:"month"`
+	Currency   string   `db:"currency"`
+	Type       string   `db:"type"`
+	Rate       *float64 `db:"rate"`
+	Deductible bool     `db:"deductible"`
+	Total      float64  `db:"total"`
+	Tax        float64  `db:"tax"`
+	Count      int      `db:"cnt"`
+}
+
+type TaxRateLine struct {
+	Rate         float64 `json:"rate"`
+	Income       float64 `json:"income"`
+	Expenses     float64 `json:"expenses"`
+	VATCollected float64 `json:"vatCollected"`
+	VATPaid      float64 `json:"vatPaid"`
+	// VATDeductible indirilebilir giderlerin KDV'si (indirilecek KDV).
+	VATDeductible float64 `json:"vatDeductible"`
+}
+
+type TaxMonthLine struct {
+	Month              int     `json:"month"`
+	Income             float64 `json:"income"`
+	DeductibleExpenses float64 `json:"deductibleExpenses"`
+	VATCollected       float64 `json:"vatCollected"`
+	VATPaid            float64 `json:"vatPaid"`
+	VATDeductible      float64 `json:"vatDeductible"`
+}
+
+// TaxLedger tek bir para birimindeki yıllık vergi dökümü; farklı para birimleri toplanmaz.
+type TaxLedger struct {
+	Currency              string  `json:"currency"`
+	Income                float64 `json:"income"`
+	DeductibleExpenses    float64 `json:"deductibleExpenses"`
+	NonDeductibleExpenses float64 `json:"nonDeductibleExpenses"`
+	VATCollected          float64 `json:"vatCollected"`
+	VATPaid               float64 `json:"vatPaid"`
+	VATDeductible         float64 `json:"vatDeductible"`
+	// NetVAT ödenecek (pozitif) ya da devreden (negatif) KDV: hesaplanan - indirilecek.
+	NetVAT  float64        `json:"netVat"`
+	ByRate  []TaxRateLine  `json:"byRate"`
+	ByMonth []TaxMonthLine `json:"byMonth"`
+}
+
+type TaxReport struct {
+	Year        int         `json:"year"`
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	GeneratedAt time.Time   `json:"generatedAt"`
+	Ledgers     []TaxLedger `json:"ledgers"`
+}
+
+type ReportRepo interface {
+	// TaxRows [from, to) aralığındaki silinmemiş işlemlerin vergi toplamları.
+	TaxRows(userID int64, from, to time.Time) ([]TaxRow, error)
+}
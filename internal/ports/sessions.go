@@ -18,6 +18,10 @@ type SessionRepo interface {
 	RotateRefreshMeta(userID int64, oldHash, newHash, ua, ip string, exp, now time.Time) error
 	ListSessions(userID int64, page, size int) (rows []Session, total int, err error)
 	RevokeSession(userID, sessionID int64) error
+	// RevokeAll kullanıcının tüm oturumlarını kapatır (parola sıfırlama/değişikliği).
+	RevokeAll(userID int64) error
+	// RevokeOthers refresh özeti keepHash olan oturum dışındakileri kapatır (parola değişikliği).
+	RevokeOthers(userID int64, keepHash string) error
 }
 
 type LoginGuardRepo interface {
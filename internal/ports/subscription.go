@@ -0,0 +1,45 @@
+package ports
+
+import "time"
+
+const (
+	CadenceWeekly    = "weekly"
+	CadenceBiweekly  = "biweekly"
+	CadenceMonthly   = "monthly"
+	CadenceQuarterly = "quarterly"
+	CadenceYearly    = "yearly"
+
+	SubActive = "active"
+	SubLapsed = "lapsed"
+)
+
+// Subscription işlem geçmişinden tespit edilen düzenli ödeme. Key normalize edilmiş
+// not + para birimidir; PrevAmount/ChangedAt son fiyat değişikliğini gösterir.
+type Subscription struct {
+	UserID       int64      `db:"user_id"       json:"-"`
+	Key          string     `db:"sub_key"       json:"key"`
+	Name         string     `db:"name"          json:"name"`
+	Currency     string     `db:"currency"      json:"currency"`
+	Cadence      string     `db:"cadence"       json:"cadence"`
+	WalletID     *int64     `db:"wallet_id"     json:"walletId,omitempty"`
+	CategoryID   *int64     `db:"category_id"   json:"categoryId,omitempty"`
+	Charges      int        `db:"charges"       json:"charges"`
+	AvgAmount    float64    `db:"avg_amount"    json:"avgAmount"`
+	LastAmount   float64    `db:"last_amount"   json:"lastAmount"`
+	LastCharge   time.Time  `db:"last_charge"   json:"lastCharge"`
+	NextExpected time.Time  `db:"next_expected" json:"nextExpected"`
+	Annualized   float64    `db:"annualized"    json:"annualized"`
+	Status       string     `db:"status"        json:"status"`
+	PrevAmount   *float64   `db:"prev_amount"   json:"prevAmount,omitempty"`
+	ChangedAt    *time.Time `db:"changed_at"    json:"changedAt,omitempty"`
+}
+
+type SubscriptionRepo interface {
+	// ChangedUsers son taramadan sonra işlemi değişmiş (ya da hiç taranmamış) kullanıcılar.
+	ChangedUsers() ([]int64, error)
+	// Expenses since sonrası notlu, silinmemiş gider işlemleri (occurred_at artan).
+	Expenses(userID int64, since time.Time) ([]Transaction, error)
+	// Replace kullanıcının tespitlerini değiştirir ve tarama zamanını kaydeder (tek transaction).
+	Replace(userID int64, subs []Subscription, scannedAt time.Time) error
+	List(userID int64) ([]Subscription, error)
+}
@@ -2,9 +2,21 @@ package ports
 
 import "time"
 
+// Durum akışı: scheduled → pending → cleared → reconciled. scheduled henüz gerçekleşmemiş
+// (ileri tarihli) işlemdir; bakiye ve özetlere varsayılan olarak girmez. "Gerçekleşmiş"
+// (posted) ayrı bir durum değildir: scheduled dışındaki her durumdur; otomatik kayıt
+// planlanmış işlemi pending'e geçirir. Arama sözdizimi status:posted'ı bu anlamda kabul eder.
+const (
+	TxScheduled  = "scheduled"
+	TxPending    = "pending"
+	TxCleared    = "cleared"
+	TxReconciled = "reconciled"
+)
+
 type Transaction struct {
 	ID         int64      `db:"id"          json:"id"`
 	UserID     int64      `db:"user_id"     json:"userId"`
+	CreatedBy  *int64     `db:"created_by"  json:"createdBy,omitempty"`
 	WalletID   int64      `db:"wallet_id"   json:"walletId"`
 	CategoryID int64      `db:"category_id" json:"categoryId"`
 	Type       string     `db:"type"        json:"type"`
@@ -14,6 +26,19 @@ type Transaction struct {
 	OccurredAt time.Time  `db:"occurred_at" json:"occurredAt"`
 	UpdatedAt  time.Time  `db:"updated_at"  json:"updatedAt"`
 	DeletedAt  *time.Time `db:"deleted_at"  json:"deletedAt,omitempty"`
+
+	InstallmentPlanID *int64 `db:"installment_plan_id" json:"installmentPlanId,omitempty"`
+	InstallmentNo     *int   `db:"installment_no"      json:"installmentNo,omitempty"`
+
+	// Status mutabakat durumu; reconciled işlemler kilitlidir.
+	Status           string `db:"status"            json:"status"`
+	ReconciliationID *int64 `db:"reconciliation_id" json:"reconciliationId,omitempty"`
+
+	// TaxRate yüzde olarak KDV oranı; TaxAmount tutarın (KDV dahil) içindeki vergi.
+	// Güncellemede nil bırakılan vergi alanları kayıttaki değeri korur.
+	TaxRate    *float64 `db:"tax_rate"   json:"taxRate,omitempty"`
+	TaxAmount  *float64 `db:"tax_amount" json:"taxAmount,omitempty"`
+	Deductible *bool    `db:"deductible" json:"deductible,omitempty"`
 }
 
 type TxSummary struct {
@@ -26,10 +51,29 @@ type TxRepo interface {
 	List(userID int64, page, size int, q string) ([]Transaction, int, error)
 	ListRange(userID int64, from, to time.Time, q string, page, size int) ([]Transaction, int, error)
 	GetSince(userID int64, since time.Time) ([]Transaction, error)
+	// ListWalletRange / WalletBalanceBefore yalnızca cüzdana göre süzer (hane cüzdanında tüm
+	// üyelerin hareketleri); erişim serviste doğrulanır. Planlanmış işlemler dışlanır.
+	ListWalletRange(walletID int64, from, to time.Time) ([]Transaction, error)
+	WalletBalanceBefore(walletID int64, before time.Time) (float64, error)
 	UpsertBatch(userID int64, items []Transaction) error
 	Create(userID int64, t *Transaction) error
 	Update(userID int64, t *Transaction) error
 	SoftDelete(userID int64, id int64) error
-	Summary(userID int64, from, to time.Time) ([]TxSummary, error)
+	// Summary / SummaryByCategory includeScheduled false ise planlanmış işlemleri dışlar.
+	Summary(userID int64, from, to time.Time, includeScheduled bool) ([]TxSummary, error)
+	SummaryByCategory(userID int64, from, to time.Time, includeScheduled bool) ([]CategoryTotal, error)
 	GetOne(userID, id int64) (*Transaction, error)
+
+	// ListFilter / SummaryByCategoryFilter kayıtlı görünümleri değerlendirir.
+	ListFilter(userID int64, f TxFilter, page, size int) ([]Transaction, int, error)
+	SummaryByCategoryFilter(userID int64, f TxFilter) ([]CategoryTotal, error)
+
+	// SetStatus reconciled olmayan işlemlerin durumunu değiştirir; etkilenen satır sayısını döner.
+	SetStatus(userID int64, ids []int64, status string) (int64, error)
+
+	// Upcoming [from, to) aralığındaki planlanmış işlemler (tarih artan).
+	Upcoming(userID int64, from, to time.Time) ([]Transaction, error)
+	// DueScheduled tarihi gelmiş planlanmış işlemler; PostScheduled bunları pending yapar.
+	DueScheduled(now time.Time, limit int) ([]Transaction, error)
+	PostScheduled(ids []int64) (int64, error)
 }
@@ -0,0 +1,48 @@
+package ports
+
+import "time"
+
+// RangeCustom görünümün sabit From/To tarihlerini kullanır; diğer ifadeler
+// ("this-month", "last-90d" ...) sorgu anında çözülür.
+const RangeCustom = "custom"
+
+type SavedView struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"-"`
+	Name        string     `json:"name"`
+	Range       string     `json:"range"`
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	WalletIDs   []int64    `json:"walletIds"`
+	CategoryIDs []int64    `json:"categoryIds"`
+	Query       string     `json:"query"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TxFilter görünüm değerlendirmesinde TxRepo'ya giden süzgeç; To hariçtir.
+// CategoryIDs alt kategorileri de kapsar.
+type TxFilter struct {
+	From        time.Time
+	To          time.Time
+	WalletIDs   []int64
+	CategoryIDs []int64
+	Query       string
+}
+
+// ViewSummary görünümün dönem toplamları; ByCategory roll-up'lıdır.
+type ViewSummary struct {
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Income     float64         `json:"income"`
+	Expense    float64         `json:"expense"`
+	Net        float64         `json:"net"`
+	ByCategory []CategoryTotal `json:"byCategory"`
+}
+
+type ViewRepo interface {
+	List(userID int64) ([]SavedView, error)
+	Get(userID, id int64) (*SavedView, error)
+	Create(userID int64, v *SavedView) error
+	Update(userID int64, v *SavedView) error
+	Delete(userID, id int64) error
+}
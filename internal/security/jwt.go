@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -35,6 +36,8 @@ func SignAccess(secret []byte, uid int64, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": uid, "iss": jwtIssuer, "aud": jwtAudience, "typ": "access",
 		"iat": now.Unix(), "nbf": now.Unix(), "exp": now.Add(ttl).Unix(), "kid": 0,
+		// jti aynı saniyede üretilen belirteçleri de benzersiz kılar (refresh rotasyonu).
+		"jti": uuid.NewString(),
 	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString(secret)
@@ -44,6 +47,8 @@ func SignRefresh(secret []byte, uid int64, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": uid, "iss": jwtIssuer, "aud": jwtAudience, "typ": "refresh",
 		"iat": now.Unix(), "nbf": now.Unix(), "exp": now.Add(ttl).Unix(), "kid": 0,
+		// jti aynı saniyede üretilen belirteçleri de benzersiz kılar (refresh rotasyonu).
+		"jti": uuid.NewString(),
 	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString(secret)
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+const (
+	defaultDeletionGrace = 30 * 24 * time.Hour
+	// purgeBatch tek çalıştırmada silinen en fazla hesap sayısı.
+	purgeBatch = 20
+)
+
+type AccountService struct {
+	Repo    ports.AccountRepo
+	Auth    ports.AuthRepo
+	Exports ports.ExportRepo
+	Store   ports.BlobStore
+	Audit   *AuditService
+	// Grace talep ile kalıcı silme arasındaki süre; bu sürede giriş yapmak talebi iptal eder.
+	Grace time.Duration
+
+	Now func() time.Time
+}
+
+func (s *AccountService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *AccountService) grace() time.Duration {
+	if s.Grace > 0 {
+		return s.Grace
+	}
+	return defaultDeletionGrace
+}
+
+// RequestDeletion parola (ve etkinse TOTP) ile yeniden doğrulama ister, hesabı silinmeyi
+// bekler duruma alır ve tüm oturumları kapatır.
+func (s *AccountService) RequestDeletion(uid int64, password, code string) (*ports.AccountDeletion, error) {
+	hash, err := s.Repo.PassHash(uid)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := security.ArgonCheck(password, hash); !ok {
+		return nil, errs.InvalidCredentials
+	}
+	if _, err := verifySecondFactor(s.Auth, uid, code, s.now()); err != nil {
+		return nil, err
+	}
+	d := &ports.AccountDeletion{RequestedAt: s.now().Truncate(time.Second)}
+	d.DeleteAfter = d.RequestedAt.Add(s.grace())
+	if err := s.Repo.RequestDeletion(uid, d.RequestedAt, d.DeleteAfter); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "account.delete_request", "user", &uid, map[string]any{"deleteAfter": d.DeleteAfter})
+	}
+	return d, nil
+}
+
+// CancelOnLogin başarılı girişte bekleyen silme talebini kaldırır.
+func (s *AccountService) CancelOnLogin(uid int64) {
+	if s == nil || s.Repo == nil {
+		return
+	}
+	if ok, err := s.Repo.CancelDeletion(uid); err == nil && ok && s.Audit != nil {
+		s.Audit.Log(uid, "account.delete_cancel", "user", &uid, nil)
+	}
+}
+
+// PurgeDue süresi dolan talepleri kalıcı olarak siler; silinen hesap sayısını döner.
+// Arşiv dosyaları ancak veritabanı silmesi başarılı olduktan sonra kaldırılır.
+func (s *AccountService) PurgeDue() (int, error) {
+	ids, err := s.Repo.DueDeletions(s.now(), purgeBatch)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		n       int
+		lastErr error
+	)
+	for _, uid := range ids {
+		var files []string
+		if s.Exports != nil {
+			rows, err := s.Exports.List(uid)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			for _, e := range rows {
+				if e.FileName != nil {
+					files = append(files, *e.FileName)
+				}
+			}
+		}
+		if err := s.Repo.Purge(uid); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				lastErr = err
+			}
+			continue
+		}
+		for _, f := range files {
+			if s.Store != nil {
+				if err := s.Store.Remove(f); err != nil {
+					lastErr = err
+				}
+			}
+		}
+		n++
+	}
+	return n, lastErr
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+type fakeAccountRepo struct {
+	hash    string
+	pending map[int64]time.Time
+	purged  []int64
+}
+
+func (f *fakeAccountRepo) PassHash(int64) (string, error) { return f.hash, nil }
+func (f *fakeAccountRepo) RequestDeletion(uid int64, _, after time.Time) error {
+	f.pending[uid] = after
+	return nil
+}
+func (f *fakeAccountRepo) CancelDeletion(uid int64) (bool, error) {
+	_, ok := f.pending[uid]
+	delete(f.pending, uid)
+	return ok, nil
+}
+func (f *fakeAccountRepo) DueDeletions(now time.Time, _ int) ([]int64, error) {
+	var ids []int64
+	for uid, after := range f.pending {
+		if !after.After(now) {
+			ids = append(ids, uid)
+		}
+	}
+	return ids, nil
+}
+func (f *fakeAccountRepo) Purge(uid int64) error {
+	if _, ok := f.pending[uid]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(f.pending, uid)
+	f.purged = append(f.purged, uid)
+	return nil
+}
+
+func TestAccount_DeletionLifecycle(t *testing.T) {
+	auth, authRepo := newSvc()
+	uid := mustRegister(t, auth, "v@e.com", "A1complex!")
+	now := day(2026, 10, 1)
+	repo := &fakeAccountRepo{hash: authRepo.users[uid].PassHash, pending: map[int64]time.Time{}}
+	s := &AccountService{Repo: repo, Auth: authRepo, Grace: 7 * 24 * time.Hour, Now: func() time.Time { return now }}
+	auth.Account = s
+
+	if _, err := s.RequestDeletion(uid, "wrong-pass", ""); err != errs.InvalidCredentials {
+		t.Fatalf("wrong password: %v", err)
+	}
+	d, err := s.RequestDeletion(uid, "A1complex!", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.DeleteAfter.Equal(now.Add(7 * 24 * time.Hour)) {
+		t.Fatalf("deleteAfter = %v", d.DeleteAfter)
+	}
+
+	// Bekleme süresinde giriş talebi iptal eder.
+	if _, _, _, err := auth.Login("v@e.com", "A1complex!", "", "", "", "ua", "ip", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.pending) != 0 {
+		t.Fatalf("login should cancel deletion")
+	}
+
+	if _, err := s.RequestDeletion(uid, "A1complex!", ""); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.PurgeDue(); err != nil || n != 0 {
+		t.Fatalf("purged before grace: n=%d err=%v", n, err)
+	}
+	now = now.Add(8 * 24 * time.Hour)
+	if n, err := s.PurgeDue(); err != nil || n != 1 || len(repo.purged) != 1 || repo.purged[0] != uid {
+		t.Fatalf("purge: n=%d err=%v purged=%v", n, err, repo.purged)
+	}
+}
+
+func TestAccount_DeletionRequiresTotp(t *testing.T) {
+	auth, authRepo := newSvc()
+	uid := mustRegister(t, auth, "v@e.com", "A1complex!")
+	key, _ := totp.Generate(totp.GenerateOpts{Issuer: "x", AccountName: "v@e.com", Period: 30, Digits: otp.DigitsSix})
+	_ = authRepo.SetTotp(uid, key.Secret())
+	_ = authRepo.ConfirmTotp(uid)
+	s := &AccountService{
+		Repo: &fakeAccountRepo{hash: authRepo.users[uid].PassHash, pending: map[int64]time.Time{}},
+		Auth: authRepo,
+	}
+
+	if _, err := s.RequestDeletion(uid, "A1complex!", ""); err != errs.TOTPRequired {
+		t.Fatalf("missing totp: %v", err)
+	}
+	if _, err := s.RequestDeletion(uid, "A1complex!", "000000"); err != errs.TOTPInvalid {
+		t.Fatalf("bad totp: %v", err)
+	}
+	code, _ := totp.GenerateCode(key.Secret(), time.Now())
+	if _, err := s.RequestDeletion(uid, "A1complex!", code); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAccount_PurgeRemovesExportFiles(t *testing.T) {
+	now := day(2026, 10, 1)
+	repo := &fakeAccountRepo{pending: map[int64]time.Time{7: now.Add(-time.Hour)}}
+	name := "export-7-1.zip"
+	store := &memStore{files: map[string]*bytes.Buffer{name: bytes.NewBufferString("zip")}}
+	s := &AccountService{
+		Repo:    repo,
+		Exports: &fakeExportRepo{e: ports.DataExport{ID: 1, UserID: 7, Status: ports.ExportReady, FileName: &name}},
+		Store:   store,
+		Now:     func() time.Time { return now },
+	}
+	if n, err := s.PurgeDue(); err != nil || n != 1 {
+		t.Fatalf("purge: n=%d err=%v", n, err)
+	}
+	if _, ok := store.files[name]; ok {
+		t.Fatalf("export file should be removed")
+	}
+}
@@ -24,8 +24,18 @@ type AuthService struct {
 	RefreshTTL time.Duration
 	Issuer     string
 	Onboard    *OnboardService
+	Account    *AccountService
+	Email      *EmailService
 	Audit      *AuditService
 
+	// Parola sıfırlama: belirteç ResetTTL süre geçerlidir; aynı hesaba saatte en çok
+	// ResetPerHour posta gönderilir. ResetURL belirtecin ekleneceği istemci bağlantısı.
+	Resets       ports.PasswordResetRepo
+	Mailer       ports.Mailer
+	ResetTTL     time.Duration
+	ResetPerHour int
+	ResetURL     string
+
 	BindRefreshToUA bool
 	BindRefreshToIP bool
 
@@ -62,9 +72,17 @@ func (s *AuthService) defaults() {
 	if s.CaptchaThreshold == 0 {
 		s.CaptchaThreshold = 5
 	}
+	if s.ResetTTL == 0 {
+		s.ResetTTL = 30 * time.Minute
+	}
+	if s.ResetPerHour == 0 {
+		s.ResetPerHour = 3
+	}
 }
 
-func (s *AuthService) Register(name, email, pass string) (int64, error) {
+// Register kullanıcıyı oluşturur ve locale şablonuyla başlangıç verisini ekler. Tohumlama
+// hatası kullanıcıyı geri almaz; hata döner ve kullanıcı yeniden tohumlama ile tamamlayabilir.
+func (s *AuthService) Register(name, email, pass, locale string) (int64, error) {
 	if err := validation.ValidatePassword(pass, email); err != nil {
 		return 0, errs.ValidationFailed("weak_password")
 	}
@@ -76,12 +94,16 @@ func (s *AuthService) Register(name, email, pass string) (int64, error) {
 	if err := s.Repo.CreateUser(u); err != nil {
 		return 0, err
 	}
-	if s.Onboard != nil {
-		_ = s.Onboard.Seed(u.ID)
-	}
 	if s.Audit != nil {
 		s.Audit.Log(u.ID, "auth.register", "user", &u.ID, map[string]any{"email": email})
 	}
+	// Posta hatası kaydı bozmaz; bağlantı /auth/email/resend ile yeniden istenebilir.
+	_ = s.Email.SendVerification(u.ID)
+	if s.Onboard != nil {
+		if _, err := s.Onboard.Seed(u.ID, locale); err != nil {
+			return u.ID, err
+		}
+	}
 	return u.ID, nil
 }
 
@@ -113,26 +135,38 @@ func (s *AuthService) Login(email, pass, deviceID, deviceName, totpCode, ua, ip,
 		return "", "", 0, s.backoffOrAuthFailed(fails)
 	}
 
-	if ts, _ := s.Repo.GetTotp(u.ID); ts != nil && ts.ConfirmedAt != nil {
-		if totpCode == "" || !totp.Validate(totpCode, ts.Secret) {
-			locked, fails := s.bumpFail(u.ID, email, ip)
-			if locked {
-				return "", "", 0, errs.AccountLocked
-			}
-			return "", "", 0, s.backoffOrAuthFailed(fails)
+	recovery, err := verifySecondFactor(s.Repo, u.ID, totpCode, time.Now())
+	if err != nil {
+		locked, fails := s.bumpFail(u.ID, email, ip)
+		if locked {
+			return "", "", 0, errs.AccountLocked
 		}
+		return "", "", 0, s.backoffOrAuthFailed(fails)
+	}
+	if recovery && s.Audit != nil {
+		s.Audit.Log(u.ID, "auth.totp.recovery_used", "user", &u.ID, map[string]any{"ip": ip})
 	}
 
 	s.resetFail(email, ip)
+	s.Account.CancelOnLogin(u.ID)
 	if s.Audit != nil {
 		s.Audit.Log(u.ID, "auth.login", "user", &u.ID, map[string]any{"deviceId": deviceID, "deviceName": deviceName, "ip": ip})
 	}
 
-	access, err = security.SignAccess(s.JWTSecret, u.ID, s.AccessTTL)
+	access, refresh, err = s.issueTokens(u.ID, deviceID, deviceName, ua, ip)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return access, refresh, u.ID, nil
+}
+
+// issueTokens cihaz için yeni access/refresh çifti üretir ve refresh oturumunu kaydeder.
+func (s *AuthService) issueTokens(uid int64, deviceID, deviceName, ua, ip string) (access, refresh string, err error) {
+	access, err = security.SignAccess(s.JWTSecret, uid, s.AccessTTL)
 	if err != nil {
 		return
 	}
-	refresh, err = security.SignRefresh(s.JWTSecret, u.ID, s.RefreshTTL)
+	refresh, err = security.SignRefresh(s.JWTSecret, uid, s.RefreshTTL)
 	if err != nil {
 		return
 	}
@@ -142,16 +176,15 @@ func (s *AuthService) Login(email, pass, deviceID, deviceName, totpCode, ua, ip,
 	exp := now.Add(s.RefreshTTL)
 
 	if s.Sess != nil {
-		_ = s.Sess.StoreRefreshMeta(u.ID, hash, ua, ip, exp, now)
+		_ = s.Sess.StoreRefreshMeta(uid, hash, ua, ip, exp, now)
 	} else {
-		_ = s.Repo.StoreRefresh(u.ID, hash, exp)
+		_ = s.Repo.StoreRefresh(uid, hash, exp)
 	}
 
 	if deviceID != "" {
-		_ = s.Repo.UpsertDevice(u.ID, deviceID, deviceName, now)
+		_ = s.Repo.UpsertDevice(uid, deviceID, deviceName, now)
 	}
-
-	return access, refresh, u.ID, nil
+	return access, refresh, nil
 }
 
 func (s *AuthService) Refresh(userID int64, oldRefresh, ua, ip string) (newAccess, newRefresh string, err error) {
@@ -210,9 +243,13 @@ func (s *AuthService) Logout(userID int64, refresh string) error {
 	return s.Repo.InvalidateRefresh(userID, security.SHA256Hex(refresh))
 }
 
+// TotpSetup yeni sır üretir; etkin TOTP ancak DisableTotp ile kapatıldıktan sonra değiştirilebilir.
 func (s *AuthService) TotpSetup(userID int64, email string) (secret, otpauth string, err error) {
+	if ts, _ := s.Repo.GetTotp(userID); ts != nil && ts.ConfirmedAt != nil {
+		return "", "", errs.Conflict
+	}
 	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer: s.Issuer, AccountName: email, Period: 30, Digits: otp.DigitsSix,
+		Issuer: s.Issuer, AccountName: email, Period: totpPeriod, Digits: otp.DigitsSix,
 	})
 	if err != nil {
 		return "", "", err
@@ -227,18 +264,33 @@ func (s *AuthService) TotpSetup(userID int64, email string) (secret, otpauth str
 	return secret, key.URL(), nil
 }
 
-func (s *AuthService) TotpConfirm(userID int64, code string) error {
+// TotpConfirm ilk kodla TOTP'yi etkinleştirir ve bir kez gösterilecek kurtarma kodlarını döner.
+func (s *AuthService) TotpConfirm(userID int64, code string) ([]string, error) {
 	ts, err := s.Repo.GetTotp(userID)
 	if err != nil || ts == nil {
-		return errs.ValidationFailed("no totp")
+		return nil, errs.ValidationFailed("no totp")
+	}
+	if ts.ConfirmedAt != nil {
+		return nil, errs.Conflict
+	}
+	step, ok := totpStep(ts.Secret, code, time.Now())
+	if !ok {
+		return nil, errs.TOTPInvalid
 	}
-	if !totp.Validate(code, ts.Secret) {
-		return errs.TOTPInvalid
+	if fresh, err := s.Repo.UseTotpStep(userID, step); err != nil || !fresh {
+		return nil, errs.TOTPInvalid
+	}
+	if err := s.Repo.ConfirmTotp(userID); err != nil {
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
 	}
 	if s.Audit != nil {
 		s.Audit.Log(userID, "auth.totp.confirm", "user", &userID, nil)
 	}
-	return s.Repo.ConfirmTotp(userID)
+	return codes, nil
 }
 
 func (s *AuthService) Sessions(userID int64, page, size int) ([]ports.Session, int, error) {
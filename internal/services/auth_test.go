@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -18,6 +19,8 @@ type fakeAuthRepo struct {
 	totp          map[int64]*ports.TotpSecret
 	refreshByUser map[int64]map[string]time.Time // hash -> exp
 	devices       map[int64]map[string]string
+	lastStep      map[int64]int64
+	recovery      map[int64]map[string]bool // hash -> used
 }
 
 func newFakeRepo() *fakeAuthRepo {
@@ -28,6 +31,8 @@ func newFakeRepo() *fakeAuthRepo {
 		totp:          map[int64]*ports.TotpSecret{},
 		refreshByUser: map[int64]map[string]time.Time{},
 		devices:       map[int64]map[string]string{},
+		lastStep:      map[int64]int64{},
+		recovery:      map[int64]map[string]bool{},
 	}
 }
 
@@ -47,6 +52,22 @@ func (r *fakeAuthRepo) FindUserByEmail(email string) (*ports.User, error) {
 	}
 	return nil, errs.NotFound
 }
+func (r *fakeAuthRepo) FindUserByID(id int64) (*ports.User, error) {
+	if u, ok := r.users[id]; ok {
+		cp := *u
+		return &cp, nil
+	}
+	return nil, errs.NotFound
+}
+func (r *fakeAuthRepo) SetPassword(uid int64, passHash string) error {
+	u, ok := r.users[uid]
+	if !ok {
+		return errs.NotFound
+	}
+	u.PassHash = passHash
+	r.byEmail[u.Email] = u
+	return nil
+}
 func (r *fakeAuthRepo) GetTotp(uid int64) (*ports.TotpSecret, error) {
 	if ts, ok := r.totp[uid]; ok {
 		cp := *ts
@@ -107,11 +128,42 @@ func (r *fakeAuthRepo) ConfirmTotp(uid int64) error {
 	return errs.NotFound
 }
 
+func (r *fakeAuthRepo) UseTotpStep(uid, step int64) (bool, error) {
+	if last, ok := r.lastStep[uid]; ok && step <= last {
+		return false, nil
+	}
+	r.lastStep[uid] = step
+	return true, nil
+}
+func (r *fakeAuthRepo) ReplaceRecoveryCodes(uid int64, hashes []string) error {
+	m := map[string]bool{}
+	for _, h := range hashes {
+		m[h] = false
+	}
+	r.recovery[uid] = m
+	return nil
+}
+func (r *fakeAuthRepo) UseRecoveryCode(uid int64, hash string, _ time.Time) (bool, error) {
+	used, ok := r.recovery[uid][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[uid][hash] = true
+	return true, nil
+}
+func (r *fakeAuthRepo) DeleteTotp(uid int64) error {
+	delete(r.totp, uid)
+	delete(r.recovery, uid)
+	delete(r.lastStep, uid)
+	return nil
+}
+
 // compile-time check
 var _ ports.AuthRepo = (*fakeAuthRepo)(nil)
 
 func newSvc() (*AuthService, *fakeAuthRepo) {
 	repo := newFakeRepo()
+	security.InitKeyRing([]byte("test-secret"), nil, "", "", 0)
 	return &AuthService{
 		Repo:       repo,
 		JWTSecret:  []byte("test-secret"),
@@ -123,7 +175,7 @@ func newSvc() (*AuthService, *fakeAuthRepo) {
 
 func mustRegister(t *testing.T, s *AuthService, email, pass string) int64 {
 	t.Helper()
-	id, err := s.Register("Veysel", email, pass)
+	id, err := s.Register("Veysel", email, pass, "")
 	if err != nil {
 		t.Fatalf("register error: %v", err)
 	}
@@ -145,7 +197,7 @@ func TestRegister_OK(t *testing.T) {
 func TestLogin_OK_NoTOTP(t *testing.T) {
 	s, r := newSvc()
 	id := mustRegister(t, s, "v@e.com", "A1complex!")
-	access, refresh, uid, err := s.Login("v@e.com", "A1complex!", "dev-1", "Pixel", "")
+	access, refresh, uid, err := s.Login("v@e.com", "A1complex!", "dev-1", "Pixel", "", "ua", "ip", "")
 	if err != nil || access == "" || refresh == "" || uid != id {
 		t.Fatalf("login failed: %v", err)
 	}
@@ -162,14 +214,15 @@ func TestLogin_TOTPRequired_And_Invalid(t *testing.T) {
 	_ = s.Repo.SetTotp(id, key.Secret())
 	_ = s.Repo.ConfirmTotp(id)
 
-	_, _, _, err := s.Login("v@e.com", "A1complex!", "dev", "X", "")
-	if err != errs.TOTPRequired {
-		t.Fatalf("expected TOTPRequired, got %v", err)
+	_, _, _, err := s.Login("v@e.com", "A1complex!", "dev", "X", "", "ua", "ip", "")
+	if err != errs.AuthFailed {
+		t.Fatalf("expected AuthFailed, got %v", err)
 	}
 
-	_, _, _, err = s.Login("v@e.com", "A1complex!", "dev", "X", "000000")
-	if err != errs.TOTPInvalid {
-		t.Fatalf("expected TOTPInvalid, got %v", err)
+	_, _, _, err = s.Login("v@e.com", "A1complex!", "dev", "X", "000000", "ua", "ip", "")
+	var ra *errs.RetryAfterError
+	if !errors.As(err, &ra) {
+		t.Fatalf("expected SlowDown, got %v", err)
 	}
 }
 
@@ -181,7 +234,7 @@ func TestLogin_TOTPOK(t *testing.T) {
 	_ = s.Repo.ConfirmTotp(id)
 	code, _ := totp.GenerateCode(key.Secret(), time.Now())
 
-	a, r, uid, err := s.Login("v@e.com", "A1complex!", "dev", "X", code)
+	a, r, uid, err := s.Login("v@e.com", "A1complex!", "dev", "X", code, "ua", "ip", "")
 	if err != nil || a == "" || r == "" || uid != id {
 		t.Fatalf("login with totp failed: %v", err)
 	}
@@ -190,12 +243,15 @@ func TestLogin_TOTPOK(t *testing.T) {
 func TestRefresh_OK_Rotates(t *testing.T) {
 	s, repo := newSvc()
 	id := mustRegister(t, s, "v@e.com", "A1complex!")
-	_, refresh, _, _ := s.Login("v@e.com", "A1complex!", "", "", "")
+	_, refresh, _, _ := s.Login("v@e.com", "A1complex!", "", "", "", "ua", "ip", "")
 
-	newA, newR, err := s.Refresh(id, refresh)
+	newA, newR, err := s.Refresh(id, refresh, "ua", "ip")
 	if err != nil || newA == "" || newR == "" {
 		t.Fatalf("refresh failed: %v", err)
 	}
+	if newR == refresh {
+		t.Fatalf("refresh token not rotated")
+	}
 
 	oldHash := security.SHA256Hex(refresh)
 	ok, _ := repo.HasValidRefresh(id, oldHash, time.Now())
@@ -207,7 +263,7 @@ func TestRefresh_OK_Rotates(t *testing.T) {
 func TestLogout_InvalidateRefresh(t *testing.T) {
 	s, repo := newSvc()
 	id := mustRegister(t, s, "v@e.com", "A1complex!")
-	_, refresh, _, _ := s.Login("v@e.com", "A1complex!", "", "", "")
+	_, refresh, _, _ := s.Login("v@e.com", "A1complex!", "", "", "", "ua", "ip", "")
 	hash := security.SHA256Hex(refresh)
 
 	if err := s.Logout(id, refresh); err != nil {
@@ -227,7 +283,7 @@ func TestTotpSetupConfirm_OK(t *testing.T) {
 		t.Fatalf("setup err: %v", err)
 	}
 	code, _ := totp.GenerateCode(secret, time.Now())
-	if err := s.TotpConfirm(id, code); err != nil {
+	if _, err := s.TotpConfirm(id, code); err != nil {
 		t.Fatalf("confirm err: %v", err)
 	}
 }
@@ -3,16 +3,120 @@ package services
 import (
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/obs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
 )
 
 type WalletService struct {
 	Repo  ports.WalletRepo
 	Audit *AuditService
+	// Roles hane cüzdanlarında rolü doğrular: düzenleme/arşiv editor, silme owner ister.
+	Roles WalletRoles
 }
 
-func (s *WalletService) List(uid int64) ([]ports.Wallet, error) { return s.Repo.List(uid) }
+func (s *WalletService) require(uid, id int64, min string) error {
+	if s.Roles == nil {
+		return nil
+	}
+	return requireWalletRole(s.Roles, uid, id, min)
+}
+
+type WalletFilter struct {
+	Kind            string
+	IncludeArchived bool
+}
+
+var walletKinds = map[string]bool{
+	ports.WalletCash: true, ports.WalletBank: true, ports.WalletCreditCard: true,
+	ports.WalletSavings: true, ports.WalletLoan: true,
+}
+
+// List cüzdanları bakiyeleriyle döner; kredi kartlarında kullanılabilir limit
+// (limit + bakiye, bakiye borç olduğunda negatiftir) hesaplanır.
+func (s *WalletService) List(uid int64, f WalletFilter) ([]ports.Wallet, error) {
+	rows, err := s.Repo.List(uid)
+	if err != nil {
+		return nil, err
+	}
+	bal, err := s.Repo.Balances(uid)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.Wallet, 0, len(rows))
+	for _, w := range rows {
+		if f.Kind != "" && w.Kind != f.Kind {
+			continue
+		}
+		if !f.IncludeArchived && w.ArchivedAt != nil {
+			continue
+		}
+		fillWalletBalance(&w, bal[w.ID])
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+func (s *WalletService) Get(uid, id int64) (*ports.Wallet, error) {
+	w, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	bal, err := s.Repo.Balances(uid)
+	if err != nil {
+		return nil, err
+	}
+	fillWalletBalance(w, bal[w.ID])
+	return w, nil
+}
+
+func fillWalletBalance(w *ports.Wallet, balance float64) {
+	w.Balance = balance
+	if w.Kind == ports.WalletCreditCard && w.CreditLimit != nil {
+		avail := *w.CreditLimit + balance
+		w.AvailableCredit = &avail
+	}
+}
+
+// normalizeWallet yeni cüzdanlara varsayılan türü atar ve doğrular.
+func normalizeWallet(w *ports.Wallet) error {
+	if w.Kind == "" {
+		w.Kind = ports.WalletCash
+	}
+	return validateWallet(w)
+}
+
+func validateWallet(w *ports.Wallet) error {
+	if !walletKinds[w.Kind] {
+		return errs.ValidationFailed("bad_wallet_kind")
+	}
+	if w.CreditLimit != nil {
+		if w.Kind != ports.WalletCreditCard {
+			return errs.ValidationFailed("credit_limit_not_allowed")
+		}
+		if *w.CreditLimit < 0 {
+			return errs.ValidationFailed("credit_limit_negative")
+		}
+	}
+	if w.StatementDay != nil || w.DueDay != nil {
+		if w.Kind != ports.WalletCreditCard {
+			return errs.ValidationFailed("statement_cycle_not_allowed")
+		}
+		if w.StatementDay == nil || w.DueDay == nil {
+			return errs.ValidationFailed("statement_cycle_incomplete")
+		}
+		if *w.StatementDay < 1 || *w.StatementDay > 31 || *w.DueDay < 1 || *w.DueDay > 31 {
+			return errs.ValidationFailed("statement_cycle_bad_day")
+		}
+	}
+	if w.AccountMask != nil {
+		m := obs.MaskAccountNumber(*w.AccountMask)
+		w.AccountMask = &m
+	}
+	return nil
+}
 
 func (s *WalletService) Create(uid int64, w *ports.Wallet) error {
 	w.Name = strings.TrimSpace(w.Name)
@@ -22,12 +126,15 @@ func (s *WalletService) Create(uid int64, w *ports.Wallet) error {
 	if w.Currency == "" {
 		w.Currency = "TRY"
 	}
+	if err := normalizeWallet(w); err != nil {
+		return err
+	}
 	if err := s.Repo.Create(uid, w); err != nil {
 		return err
 	}
 	if s.Audit != nil {
 		s.Audit.Log(uid, "wallet.create", "wallet", &w.ID, map[string]any{
-			"name": w.Name, "currency": w.Currency,
+			"name": w.Name, "currency": w.Currency, "kind": w.Kind,
 		})
 	}
 	return nil
@@ -41,18 +148,74 @@ func (s *WalletService) Update(uid int64, w *ports.Wallet) error {
 	if w.Currency == "" {
 		return errors.New("currency_required")
 	}
+	if err := s.require(uid, w.ID, ports.RoleEditor); err != nil {
+		return err
+	}
+	cur, err := s.Repo.Get(uid, w.ID)
+	if err != nil {
+		return err
+	}
+	mergeWallet(w, cur)
+	if err := validateWallet(w); err != nil {
+		return err
+	}
 	if err := s.Repo.Update(uid, w); err != nil {
 		return err
 	}
 	if s.Audit != nil {
 		s.Audit.Log(uid, "wallet.update", "wallet", &w.ID, map[string]any{
-			"name": w.Name, "currency": w.Currency,
+			"name": w.Name, "currency": w.Currency, "kind": w.Kind,
 		})
 	}
 	return nil
 }
 
+// mergeWallet güncellemede gönderilmeyen alanları kayıtlı cüzdandan alır; eski istemciler
+// kind/limit/kesim günü göndermez. Tür kredi kartından çıkarsa kart alanları temizlenir.
+func mergeWallet(w, cur *ports.Wallet) {
+	if w.Kind == "" {
+		w.Kind = cur.Kind
+	}
+	if w.Kind == ports.WalletCreditCard {
+		if w.CreditLimit == nil {
+			w.CreditLimit = cur.CreditLimit
+		}
+		if w.StatementDay == nil && w.DueDay == nil {
+			w.StatementDay, w.DueDay = cur.StatementDay, cur.DueDay
+		}
+	}
+	if w.Institution == nil {
+		w.Institution = cur.Institution
+	}
+	if w.AccountMask == nil {
+		w.AccountMask = cur.AccountMask
+	}
+}
+
+func (s *WalletService) Archive(uid, id int64) error   { return s.setArchived(uid, id, true) }
+func (s *WalletService) Unarchive(uid, id int64) error { return s.setArchived(uid, id, false) }
+
+func (s *WalletService) setArchived(uid, id int64, archived bool) error {
+	if err := s.require(uid, id, ports.RoleEditor); err != nil {
+		return err
+	}
+	if err := s.Repo.SetArchived(uid, id, archived); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		act := "wallet.unarchive"
+		if archived {
+			act = "wallet.archive"
+		}
+		s.Audit.Log(uid, act, "wallet", &id, nil)
+	}
+	return nil
+}
+
 func (s *WalletService) Delete(uid, id int64) error {
+	if err := s.require(uid, id, ports.RoleOwner); err != nil {
+		return err
+	}
 	if err := s.Repo.Delete(uid, id); err != nil {
 		return err
 	}
@@ -67,8 +230,107 @@ type CategoryService struct {
 	Audit *AuditService
 }
 
-func (s *CategoryService) List(uid int64, typ string) ([]ports.Category, error) {
-	return s.Repo.List(uid, typ)
+// List arşivlenmiş kategorileri seçicilerde göstermemek için varsayılan olarak gizler.
+func (s *CategoryService) List(uid int64, typ string, includeArchived bool) ([]ports.Category, error) {
+	rows, err := s.Repo.List(uid, typ)
+	if err != nil || includeArchived {
+		return rows, err
+	}
+	out := make([]ports.Category, 0, len(rows))
+	for _, c := range rows {
+		if c.ArchivedAt == nil {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (s *CategoryService) Tree(uid int64, typ string, includeArchived bool) ([]ports.Category, error) {
+	rows, err := s.List(uid, typ, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCategoryTree(rows), nil
+}
+
+func (s *CategoryService) Since(uid int64, since time.Time) ([]ports.Category, error) {
+	return s.Repo.ListSince(uid, since)
+}
+
+func (s *CategoryService) Archive(uid, id int64) error   { return s.setArchived(uid, id, true) }
+func (s *CategoryService) Unarchive(uid, id int64) error { return s.setArchived(uid, id, false) }
+
+func (s *CategoryService) setArchived(uid, id int64, archived bool) error {
+	if err := s.Repo.SetArchived(uid, id, archived); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		act := "category.unarchive"
+		if archived {
+			act = "category.archive"
+		}
+		s.Audit.Log(uid, act, "category", &id, nil)
+	}
+	return nil
+}
+
+// Merge src kategorisindeki tüm işlemleri dst'ye aktarır; src arşivlenir ve
+// alt kategorileri dst'nin altına taşınır.
+func (s *CategoryService) Merge(uid, srcID, dstID int64) (int64, error) {
+	if srcID == dstID {
+		return 0, errs.ValidationFailed("merge_same_category")
+	}
+	all, err := s.Repo.List(uid, "")
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[int64]ports.Category, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	src, okSrc := byID[srcID]
+	dst, okDst := byID[dstID]
+	if !okSrc || !okDst {
+		return 0, errs.NotFound
+	}
+	if src.Type != dst.Type {
+		return 0, errs.ValidationFailed("merge_type_mismatch")
+	}
+	if dst.ArchivedAt != nil {
+		return 0, errs.ValidationFailed("merge_target_archived")
+	}
+	// Hedef, kaynağın alt ağacında olamaz; alt kategoriler hedefin altına
+	// taşındığında derinlik sınırı da korunmalı.
+	depth := 0
+	for cur := dstID; ; {
+		if cur == srcID {
+			return 0, errs.ValidationFailed("merge_into_descendant")
+		}
+		depth++
+		p := byID[cur].ParentID
+		if p == nil || depth > maxCategoryDepth {
+			break
+		}
+		cur = *p
+	}
+	for _, c := range all {
+		if c.ParentID != nil && *c.ParentID == srcID {
+			if err := checkSubtreeDepth(all, c.ID, depth+1); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	moved, err := s.Repo.Merge(uid, srcID, dstID)
+	if err != nil {
+		return 0, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "category.merge", "category", &srcID, map[string]any{
+			"targetId": dstID, "moved": moved,
+		})
+	}
+	return moved, nil
 }
 
 func (s *CategoryService) Create(uid int64, c *ports.Category) error {
@@ -79,37 +341,97 @@ func (s *CategoryService) Create(uid int64, c *ports.Category) error {
 	if c.Type != "income" && c.Type != "expense" {
 		return errors.New("bad_type")
 	}
+	if c.ParentID != nil {
+		all, err := s.Repo.List(uid, "")
+		if err != nil {
+			return err
+		}
+		if err := validateParent(all, c); err != nil {
+			return err
+		}
+	}
 	if err := s.Repo.Create(uid, c); err != nil {
 		return err
 	}
 	if s.Audit != nil {
 		s.Audit.Log(uid, "category.create", "category", &c.ID, map[string]any{
-			"name": c.Name, "type": c.Type,
+			"name": c.Name, "type": c.Type, "parentId": c.ParentID,
 		})
 	}
 	return nil
 }
 
-func (s *CategoryService) Update(uid int64, c *ports.Category) error {
-	if c.ID == 0 {
-		return errors.New("id_required")
+// CategoryInput güncelleme girdisi: nil alanlar mevcut değeri korur (eski istemciler
+// parentId/icon/color göndermez); ParentID 0 kategoriyi köke taşır.
+type CategoryInput struct {
+	Name      string
+	Type      string
+	ParentID  *int64
+	SortOrder *int
+	Icon      *string
+	Color     *string
+}
+
+func (s *CategoryService) Update(uid, id int64, in CategoryInput) (*ports.Category, error) {
+	if id == 0 {
+		return nil, errors.New("id_required")
 	}
-	c.Name = strings.TrimSpace(c.Name)
-	if c.Name == "" {
-		return errors.New("name_required")
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" {
+		return nil, errors.New("name_required")
 	}
-	if c.Type != "income" && c.Type != "expense" {
-		return errors.New("bad_type")
+	if in.Type != "income" && in.Type != "expense" {
+		return nil, errors.New("bad_type")
+	}
+	all, err := s.Repo.List(uid, "")
+	if err != nil {
+		return nil, err
+	}
+	var c *ports.Category
+	for i := range all {
+		if all[i].ID == id {
+			cp := all[i]
+			c = &cp
+		}
+	}
+	if c == nil {
+		return nil, errs.NotFound
+	}
+	c.Name, c.Type = in.Name, in.Type
+	switch {
+	case in.ParentID == nil:
+	case *in.ParentID == 0:
+		c.ParentID = nil
+	default:
+		c.ParentID = in.ParentID
+	}
+	if in.SortOrder != nil {
+		c.SortOrder = *in.SortOrder
+	}
+	if in.Icon != nil {
+		c.Icon = in.Icon
+	}
+	if in.Color != nil {
+		c.Color = in.Color
+	}
+
+	for _, x := range all {
+		if x.ParentID != nil && *x.ParentID == c.ID && x.Type != c.Type {
+			return nil, errs.ValidationFailed("children_type_mismatch")
+		}
+	}
+	if err := validateParent(all, c); err != nil {
+		return nil, err
 	}
 	if err := s.Repo.Update(uid, c); err != nil {
-		return err
+		return nil, err
 	}
 	if s.Audit != nil {
 		s.Audit.Log(uid, "category.update", "category", &c.ID, map[string]any{
-			"name": c.Name, "type": c.Type,
+			"name": c.Name, "type": c.Type, "parentId": c.ParentID,
 		})
 	}
-	return nil
+	return c, nil
 }
 
 func (s *CategoryService) Delete(uid, id int64) error {
@@ -1,18 +1,33 @@
 package services
 
 import (
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/Veysel440/finance-master-api/internal/ports"
 )
 
 type fakeWalletRepo struct {
+	rows       []ports.Wallet
+	balances   map[int64]float64
 	lastCreate ports.Wallet
 	lastUpdate ports.Wallet
 	deleted    int64
 }
 
-func (r *fakeWalletRepo) List(int64) ([]ports.Wallet, error) { return nil, nil }
+func (r *fakeWalletRepo) List(int64) ([]ports.Wallet, error) { return r.rows, nil }
+func (r *fakeWalletRepo) Get(_ int64, id int64) (*ports.Wallet, error) {
+	for i := range r.rows {
+		if r.rows[i].ID == id {
+			w := r.rows[i]
+			return &w, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+func (r *fakeWalletRepo) SetArchived(int64, int64, bool) error      { return nil }
+func (r *fakeWalletRepo) Balances(int64) (map[int64]float64, error) { return r.balances, nil }
 func (r *fakeWalletRepo) Create(_ int64, w *ports.Wallet) error {
 	r.lastCreate = *w
 	w.ID = 1
@@ -22,12 +37,24 @@ func (r *fakeWalletRepo) Update(_ int64, w *ports.Wallet) error { r.lastUpdate =
 func (r *fakeWalletRepo) Delete(_ int64, id int64) error        { r.deleted = id; return nil }
 
 type fakeCategoryRepo struct {
+	rows       []ports.Category
 	lastCreate ports.Category
 	lastUpdate ports.Category
 	deleted    int64
+	archived   map[int64]bool
+	merged     [2]int64
 }
 
-func (r *fakeCategoryRepo) List(int64, string) ([]ports.Category, error) { return nil, nil }
+func (r *fakeCategoryRepo) List(int64, string) ([]ports.Category, error) { return r.rows, nil }
+func (r *fakeCategoryRepo) Get(_ int64, id int64) (*ports.Category, error) {
+	for i := range r.rows {
+		if r.rows[i].ID == id {
+			c := r.rows[i]
+			return &c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
 func (r *fakeCategoryRepo) Create(_ int64, c *ports.Category) error {
 	r.lastCreate = *c
 	c.ID = 2
@@ -35,6 +62,17 @@ func (r *fakeCategoryRepo) Create(_ int64, c *ports.Category) error {
 }
 func (r *fakeCategoryRepo) Update(_ int64, c *ports.Category) error { r.lastUpdate = *c; return nil }
 func (r *fakeCategoryRepo) Delete(_ int64, id int64) error          { r.deleted = id; return nil }
+func (r *fakeCategoryRepo) ListSince(int64, time.Time) ([]ports.Category, error) {
+	return r.rows, nil
+}
+func (r *fakeCategoryRepo) SetArchived(_ int64, id int64, archived bool) error {
+	r.archived = map[int64]bool{id: archived}
+	return nil
+}
+func (r *fakeCategoryRepo) Merge(_ int64, src, dst int64) (int64, error) {
+	r.merged = [2]int64{src, dst}
+	return 3, nil
+}
 
 func TestWallet_Create_DefaultCurrencyAndAudit(t *testing.T) {
 	wr := &fakeWalletRepo{}
@@ -64,6 +102,83 @@ func TestWallet_Update_Validations(t *testing.T) {
 	}
 }
 
+func TestWallet_Create_KindAndMask(t *testing.T) {
+	wr := &fakeWalletRepo{}
+	ws := &WalletService{Repo: wr}
+
+	limit := 5000.0
+	if err := ws.Create(1, &ports.Wallet{Name: "Nakit", CreditLimit: &limit}); err == nil {
+		t.Fatalf("expected credit_limit_not_allowed")
+	}
+	if err := ws.Create(1, &ports.Wallet{Name: "X", Kind: "crypto"}); err == nil {
+		t.Fatalf("expected bad_wallet_kind")
+	}
+
+	acct := "TR12 0006 2000 1234 5678 9012 34"
+	err := ws.Create(1, &ports.Wallet{Name: "Kart", Kind: ports.WalletCreditCard, CreditLimit: &limit, AccountMask: &acct})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if wr.lastCreate.AccountMask == nil || *wr.lastCreate.AccountMask != "**** 1234" {
+		t.Fatalf("account number not masked: %v", wr.lastCreate.AccountMask)
+	}
+}
+
+func TestWallet_Update_KeepsCardFields(t *testing.T) {
+	limit, closeDay, dueDay := 20000.0, 15, 25
+	mask := "**** 1234"
+	wr := &fakeWalletRepo{rows: []ports.Wallet{{
+		ID: 2, Name: "Kart", Currency: "TRY", Kind: ports.WalletCreditCard,
+		CreditLimit: &limit, StatementDay: &closeDay, DueDay: &dueDay, AccountMask: &mask,
+	}}}
+	ws := &WalletService{Repo: wr}
+
+	// Eski istemci yalnızca ad ve para birimi gönderir.
+	if err := ws.Update(1, &ports.Wallet{ID: 2, Name: "Bonus", Currency: "TRY"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	u := wr.lastUpdate
+	if u.Kind != ports.WalletCreditCard || u.CreditLimit == nil || *u.CreditLimit != limit ||
+		u.StatementDay == nil || *u.StatementDay != closeDay || u.DueDay == nil || *u.DueDay != dueDay ||
+		u.AccountMask == nil || *u.AccountMask != mask {
+		t.Fatalf("card fields dropped: %+v", u)
+	}
+
+	if err := ws.Update(1, &ports.Wallet{ID: 2, Name: "Hesap", Currency: "TRY", Kind: ports.WalletBank}); err != nil {
+		t.Fatalf("kind change err: %v", err)
+	}
+	if u := wr.lastUpdate; u.CreditLimit != nil || u.StatementDay != nil {
+		t.Fatalf("card fields should be cleared: %+v", u)
+	}
+}
+
+func TestWallet_List_FilterAndAvailableCredit(t *testing.T) {
+	limit := 10000.0
+	now := time.Now()
+	wr := &fakeWalletRepo{
+		rows: []ports.Wallet{
+			{ID: 1, Name: "Nakit", Kind: ports.WalletCash},
+			{ID: 2, Name: "Kart", Kind: ports.WalletCreditCard, CreditLimit: &limit},
+			{ID: 3, Name: "Eski", Kind: ports.WalletBank, ArchivedAt: &now},
+		},
+		balances: map[int64]float64{1: 250, 2: -1500},
+	}
+	ws := &WalletService{Repo: wr}
+
+	all, _ := ws.List(1, WalletFilter{})
+	if len(all) != 2 {
+		t.Fatalf("archived wallet not hidden: %d", len(all))
+	}
+	cards, _ := ws.List(1, WalletFilter{Kind: ports.WalletCreditCard})
+	if len(cards) != 1 || cards[0].AvailableCredit == nil || *cards[0].AvailableCredit != 8500 {
+		t.Fatalf("available credit wrong: %+v", cards)
+	}
+	withArchived, _ := ws.List(1, WalletFilter{IncludeArchived: true})
+	if len(withArchived) != 3 {
+		t.Fatalf("includeArchived ignored")
+	}
+}
+
 func TestCategory_Create_Validations(t *testing.T) {
 	cr := &fakeCategoryRepo{}
 	cs := &CategoryService{Repo: cr}
@@ -76,10 +191,10 @@ func TestCategory_Create_Validations(t *testing.T) {
 }
 
 func TestCategory_Update_Audit(t *testing.T) {
-	cr := &fakeCategoryRepo{}
+	cr := &fakeCategoryRepo{rows: []ports.Category{{ID: 5, Name: "Market", Type: "expense"}}}
 	ar := &fakeAuditRepo{}
 	cs := &CategoryService{Repo: cr, Audit: &AuditService{Repo: ar}}
-	err := cs.Update(1, &ports.Category{ID: 5, Name: "Market", Type: "expense"})
+	_, err := cs.Update(1, 5, CategoryInput{Name: "Market", Type: "expense"})
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -87,3 +202,46 @@ func TestCategory_Update_Audit(t *testing.T) {
 		t.Fatalf("audit not called")
 	}
 }
+
+func TestCategory_Merge_ValidatesAndAudits(t *testing.T) {
+	cr := &fakeCategoryRepo{rows: sampleCats()}
+	ar := &fakeAuditRepo{}
+	cs := &CategoryService{Repo: cr, Audit: &AuditService{Repo: ar}}
+
+	if _, err := cs.Merge(1, 1, 1); err == nil {
+		t.Fatalf("expected merge_same_category")
+	}
+	if _, err := cs.Merge(1, 3, 5); err == nil {
+		t.Fatalf("expected merge_type_mismatch")
+	}
+	if _, err := cs.Merge(1, 1, 4); err == nil {
+		t.Fatalf("expected merge_into_descendant")
+	}
+
+	moved, err := cs.Merge(1, 3, 2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if moved != 3 || cr.merged != [2]int64{3, 2} {
+		t.Fatalf("repo merge not called: %v", cr.merged)
+	}
+	if ar.last.action != "category.merge" {
+		t.Fatalf("audit not called")
+	}
+}
+
+func TestCategory_List_HidesArchived(t *testing.T) {
+	now := time.Now()
+	rows := sampleCats()
+	rows[2].ArchivedAt = &now
+	cs := &CategoryService{Repo: &fakeCategoryRepo{rows: rows}}
+
+	visible, _ := cs.List(1, "", false)
+	if len(visible) != len(rows)-1 {
+		t.Fatalf("archived category not hidden")
+	}
+	all, _ := cs.List(1, "", true)
+	if len(all) != len(rows) {
+		t.Fatalf("includeArchived should return all")
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// maxCategoryDepth: kök dahil izin verilen en fazla seviye (Yemek > Restoran > Fast food).
+const maxCategoryDepth = 3
+
+// BuildCategoryTree düz listeyi parent_id'ye göre ağaca çevirir.
+// Ebeveyni listede olmayan kategoriler köke düşer.
+func BuildCategoryTree(rows []ports.Category) []ports.Category {
+	byParent := map[int64][]ports.Category{}
+	ids := make(map[int64]struct{}, len(rows))
+	for _, c := range rows {
+		ids[c.ID] = struct{}{}
+	}
+	var roots []ports.Category
+	for _, c := range rows {
+		if c.ParentID != nil {
+			if _, ok := ids[*c.ParentID]; ok {
+				byParent[*c.ParentID] = append(byParent[*c.ParentID], c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	var attach func(list []ports.Category, depth int) []ports.Category
+	attach = func(list []ports.Category, depth int) []ports.Category {
+		sortCategories(list)
+		for i := range list {
+			if depth < maxCategoryDepth+1 {
+				list[i].Children = attach(byParent[list[i].ID], depth+1)
+			}
+		}
+		return list
+	}
+	return attach(roots, 1)
+}
+
+func sortCategories(list []ports.Category) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		if list[i].SortOrder != list[j].SortOrder {
+			return list[i].SortOrder < list[j].SortOrder
+		}
+		return list[i].Name < list[j].Name
+	})
+}
+
+// RollupCategoryTotals her kategorinin toplamına alt kategorilerininkini ekler.
+// Hareketi olmayan ama altında hareket olan ebeveynler de sonuca girer.
+func RollupCategoryTotals(cats []ports.Category, rows []ports.CategoryTotal) []ports.CategoryTotal {
+	parent := make(map[int64]*int64, len(cats))
+	typ := make(map[int64]string, len(cats))
+	for _, c := range cats {
+		parent[c.ID] = c.ParentID
+		typ[c.ID] = c.Type
+	}
+	acc := map[int64]*ports.CategoryTotal{}
+	get := func(id int64, t string) *ports.CategoryTotal {
+		if ct, ok := acc[id]; ok {
+			return ct
+		}
+		if tt, ok := typ[id]; ok {
+			t = tt
+		}
+		ct := &ports.CategoryTotal{CategoryID: id, ParentID: parent[id], Type: t}
+		acc[id] = ct
+		return ct
+	}
+	for _, r := range rows {
+		get(r.CategoryID, r.Type).Own += r.Total
+		seen := map[int64]bool{}
+		for id := &r.CategoryID; id != nil && !seen[*id]; id = parent[*id] {
+			seen[*id] = true
+			get(*id, r.Type).Total += r.Total
+		}
+	}
+	out := make([]ports.CategoryTotal, 0, len(acc))
+	for _, ct := range acc {
+		out = append(out, *ct)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CategoryID < out[j].CategoryID })
+	return out
+}
+
+// validateParent: ebeveyn var mı, tipi aynı mı, döngü ya da derinlik aşımı var mı.
+func validateParent(all []ports.Category, c *ports.Category) error {
+	if c.ParentID == nil {
+		return checkSubtreeDepth(all, c.ID, 1)
+	}
+	byID := make(map[int64]ports.Category, len(all))
+	for _, x := range all {
+		byID[x.ID] = x
+	}
+	p, ok := byID[*c.ParentID]
+	if !ok {
+		return errs.ValidationFailed("parent_not_found")
+	}
+	if p.Type != c.Type {
+		return errs.ValidationFailed("parent_type_mismatch")
+	}
+	depth := 1
+	seen := map[int64]bool{}
+	for cur := &p; cur != nil; {
+		if c.ID != 0 && cur.ID == c.ID {
+			return errs.ValidationFailed("parent_cycle")
+		}
+		if seen[cur.ID] {
+			return errs.ValidationFailed("parent_cycle")
+		}
+		seen[cur.ID] = true
+		depth++
+		if cur.ParentID == nil {
+			break
+		}
+		next, ok := byID[*cur.ParentID]
+		if !ok {
+			break
+		}
+		cur = &next
+	}
+	return checkSubtreeDepth(all, c.ID, depth)
+}
+
+// checkSubtreeDepth: id'nin alt ağacı, id'nin kendi seviyesi depth iken sınırı aşıyor mu.
+func checkSubtreeDepth(all []ports.Category, id int64, depth int) error {
+	if depth > maxCategoryDepth {
+		return errs.ValidationFailed("category_too_deep")
+	}
+	if id == 0 {
+		return nil
+	}
+	for _, x := range all {
+		if x.ParentID != nil && *x.ParentID == id && x.ID != id {
+			if err := checkSubtreeDepth(all, x.ID, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
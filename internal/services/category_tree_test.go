@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func pid(v int64) *int64 { return &v }
+
+func sampleCats() []ports.Category {
+	return []ports.Category{
+		{ID: 1, Name: "Yemek", Type: "expense"},
+		{ID: 2, Name: "Restoran", Type: "expense", ParentID: pid(1), SortOrder: 2},
+		{ID: 3, Name: "Market", Type: "expense", ParentID: pid(1), SortOrder: 1},
+		{ID: 4, Name: "Fast food", Type: "expense", ParentID: pid(2)},
+		{ID: 5, Name: "Maaş", Type: "income"},
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	tree := BuildCategoryTree(sampleCats())
+	if len(tree) != 2 {
+		t.Fatalf("want 2 roots, got %d", len(tree))
+	}
+	food := tree[0]
+	if food.ID != 1 || len(food.Children) != 2 {
+		t.Fatalf("unexpected root: %+v", food)
+	}
+	if food.Children[0].ID != 3 {
+		t.Fatalf("children not ordered by sortOrder")
+	}
+	if len(food.Children[1].Children) != 1 || food.Children[1].Children[0].ID != 4 {
+		t.Fatalf("grandchild missing")
+	}
+}
+
+func TestCategory_Update_ParentValidation(t *testing.T) {
+	cr := &fakeCategoryRepo{rows: sampleCats()}
+	cs := &CategoryService{Repo: cr}
+
+	cases := []struct {
+		name string
+		id   int64
+		in   CategoryInput
+		want string
+	}{
+		{"cycle", 1, CategoryInput{Name: "Yemek", Type: "expense", ParentID: pid(4)}, "parent_cycle"},
+		{"self", 2, CategoryInput{Name: "Restoran", Type: "expense", ParentID: pid(2)}, "parent_cycle"},
+		{"type", 3, CategoryInput{Name: "Market", Type: "expense", ParentID: pid(5)}, "parent_type_mismatch"},
+		{"missing", 3, CategoryInput{Name: "Market", Type: "expense", ParentID: pid(99)}, "parent_not_found"},
+		{"too deep", 2, CategoryInput{Name: "Restoran", Type: "expense", ParentID: pid(3)}, "category_too_deep"},
+		{"children type", 1, CategoryInput{Name: "Yemek", Type: "income"}, "children_type_mismatch"},
+	}
+	for _, tc := range cases {
+		_, err := cs.Update(1, tc.id, tc.in)
+		ae, ok := err.(*errs.AppError)
+		if !ok || ae.Message != tc.want {
+			t.Fatalf("%s: want %s, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	if _, err := cs.Update(1, 3, CategoryInput{Name: "Market", Type: "expense", ParentID: pid(2)}); err != nil {
+		t.Fatalf("valid move rejected: %v", err)
+	}
+}
+
+func TestCategory_Update_KeepsAbsentFields(t *testing.T) {
+	icon, color := "cart", "#00aa00"
+	rows := sampleCats()
+	rows[2].Icon, rows[2].Color = &icon, &color
+	cr := &fakeCategoryRepo{rows: rows}
+	cs := &CategoryService{Repo: cr}
+
+	// Eski istemci yalnızca ad ve tür gönderir.
+	if _, err := cs.Update(1, 3, CategoryInput{Name: "Süpermarket", Type: "expense"}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	u := cr.lastUpdate
+	if u.Name != "Süpermarket" || u.ParentID == nil || *u.ParentID != 1 || u.SortOrder != 1 ||
+		u.Icon == nil || *u.Icon != icon || u.Color == nil || *u.Color != color {
+		t.Fatalf("absent fields not kept: %+v", u)
+	}
+
+	if _, err := cs.Update(1, 3, CategoryInput{Name: "Market", Type: "expense", ParentID: pid(0)}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if cr.lastUpdate.ParentID != nil {
+		t.Fatalf("parentId 0 should move to root: %v", *cr.lastUpdate.ParentID)
+	}
+}
+
+func TestTx_SummaryByCategory_Rollup(t *testing.T) {
+	txr := &fakeTxRepo{catTotals: []ports.CategoryTotal{
+		{CategoryID: 1, Type: "expense", Total: 10},
+		{CategoryID: 3, Type: "expense", Total: 20},
+		{CategoryID: 4, Type: "expense", Total: 5},
+	}}
+	svc := &TxService{Repo: txr, Cats: &fakeCategoryRepo{rows: sampleCats()}}
+
+	rows, err := svc.SummaryByCategory(1, time.Time{}, time.Now(), true, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	got := map[int64]ports.CategoryTotal{}
+	for _, r := range rows {
+		got[r.CategoryID] = r
+	}
+	if got[1].Total != 35 || got[1].Own != 10 {
+		t.Fatalf("root rollup wrong: %+v", got[1])
+	}
+	if got[2].Total != 5 || got[2].Own != 0 {
+		t.Fatalf("intermediate rollup wrong: %+v", got[2])
+	}
+	if got[4].Total != 5 {
+		t.Fatalf("leaf wrong: %+v", got[4])
+	}
+}
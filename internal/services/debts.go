@@ -0,0 +1,220 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type ContactService struct {
+	Repo  ports.ContactRepo
+	Audit *AuditService
+}
+
+func (s *ContactService) List(uid int64) ([]ports.Contact, error) { return s.Repo.List(uid) }
+
+func (s *ContactService) Create(uid int64, c *ports.Contact) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return errs.ValidationFailed("name_required")
+	}
+	if err := s.Repo.Create(uid, c); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "contact.create", "contact", &c.ID, nil)
+	}
+	return nil
+}
+
+func (s *ContactService) Update(uid int64, c *ports.Contact) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return errs.ValidationFailed("name_required")
+	}
+	if err := s.Repo.Update(uid, c); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "contact.update", "contact", &c.ID, nil)
+	}
+	return nil
+}
+
+func (s *ContactService) Delete(uid, id int64) error {
+	if err := s.Repo.Delete(uid, id); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "contact.delete", "contact", &id, nil)
+	}
+	return nil
+}
+
+type DebtService struct {
+	Repo     ports.DebtRepo
+	Contacts ports.ContactRepo
+	Audit    *AuditService
+	Access   WalletAccess
+	Wallets  ports.WalletRepo
+	Cats     ports.CategoryRepo
+	// NetWorth bağlı işlem geçmiş tarihliyse net değer görüntülerini bayat işaretler.
+	NetWorth StaleMarker
+
+	Now func() time.Time
+}
+
+// DebtPaymentInput: WalletID verilirse ödeme cüzdanda bağlı bir işlem olarak da kaydedilir
+// (verdiğimiz borcun tahsili gelir, aldığımız borcun ödemesi gider).
+type DebtPaymentInput struct {
+	Amount     float64
+	PaidAt     time.Time
+	Note       *string
+	WalletID   *int64
+	CategoryID *int64
+}
+
+func (s *DebtService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *DebtService) Create(uid int64, d *ports.Debt) error {
+	if d.Direction != ports.DebtLent && d.Direction != ports.DebtBorrowed {
+		return errs.ValidationFailed("bad_direction")
+	}
+	if d.Amount <= 0 {
+		return errs.ValidationFailed("bad_amount")
+	}
+	if _, err := s.Contacts.Get(uid, d.ContactID); err != nil {
+		return errs.ValidationFailed("contact_not_found")
+	}
+	d.Status = ports.DebtOpen
+	if err := s.Repo.Create(uid, d); err != nil {
+		return err
+	}
+	d.Remaining = d.Amount
+	if s.Audit != nil {
+		s.Audit.Log(uid, "debt.create", "debt", &d.ID, map[string]any{
+			"direction": d.Direction, "amount": d.Amount, "currency": d.Currency,
+		})
+	}
+	return nil
+}
+
+func (s *DebtService) List(uid int64, f ports.DebtFilter) ([]ports.Debt, error) {
+	rows, err := s.Repo.List(uid, f)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Remaining = round2(rows[i].Amount - rows[i].Paid)
+	}
+	return rows, nil
+}
+
+func (s *DebtService) Overdue(uid int64) ([]ports.Debt, error) {
+	now := s.now()
+	return s.List(uid, ports.DebtFilter{OverdueAt: &now})
+}
+
+func (s *DebtService) Get(uid, id int64) (*ports.Debt, error) {
+	d, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.Payments, err = s.Repo.Payments(uid, id); err != nil {
+		return nil, err
+	}
+	d.Remaining = round2(d.Amount - d.Paid)
+	return d, nil
+}
+
+func (s *DebtService) AddPayment(uid, debtID int64, in DebtPaymentInput) (*ports.DebtPayment, error) {
+	d, err := s.Repo.Get(uid, debtID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Status == ports.DebtSettled {
+		return nil, errs.ValidationFailed("debt_settled")
+	}
+	if in.Amount <= 0 || round2(in.Amount) > round2(d.Amount-d.Paid) {
+		return nil, errs.ValidationFailed("bad_amount")
+	}
+	if in.PaidAt.IsZero() {
+		in.PaidAt = s.now()
+	}
+
+	var t *ports.Transaction
+	if in.WalletID != nil {
+		if in.CategoryID == nil {
+			return nil, errs.ValidationFailed("category_required")
+		}
+		if s.Access != nil {
+			if err := s.Access.CanWrite(uid, *in.WalletID); err != nil {
+				return nil, err
+			}
+		}
+		typ := "income"
+		if d.Direction == ports.DebtBorrowed {
+			typ = "expense"
+		}
+		if s.Cats != nil {
+			c, err := s.Cats.Get(uid, *in.CategoryID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errs.ValidationFailed("category_not_found")
+			}
+			if err != nil {
+				return nil, err
+			}
+			if c.Type != typ {
+				return nil, errs.ValidationFailed("category_type_mismatch")
+			}
+		}
+		// Ödeme borcun para biriminde; farklı para birimli cüzdana çevrimsiz yazılamaz.
+		if s.Wallets != nil {
+			w, err := s.Wallets.Get(uid, *in.WalletID)
+			if err != nil {
+				return nil, err
+			}
+			if w.Currency != d.Currency {
+				return nil, errs.ValidationFailed("currency_mismatch")
+			}
+		}
+		t = &ports.Transaction{
+			WalletID: *in.WalletID, CategoryID: *in.CategoryID, Type: typ,
+			Amount: in.Amount, Currency: d.Currency, Note: in.Note, OccurredAt: in.PaidAt,
+			Status: statusFor(in.PaidAt, s.now()),
+		}
+	}
+	p := &ports.DebtPayment{DebtID: debtID, Amount: in.Amount, PaidAt: in.PaidAt.UTC(), Note: in.Note}
+	if err := s.Repo.AddPayment(uid, p, t); err != nil {
+		return nil, err
+	}
+	if t != nil && s.NetWorth != nil {
+		_ = s.NetWorth.MarkStale(uid, t.OccurredAt)
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "debt.payment", "debt", &debtID, map[string]any{
+			"amount": in.Amount, "transactionId": p.TransactionID,
+		})
+	}
+	return p, nil
+}
+
+func (s *DebtService) Summary(uid int64) ([]ports.DebtBalance, error) {
+	rows, err := s.Repo.Summary(uid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].OwedToMe, rows[i].IOwe, rows[i].Net = round2(rows[i].OwedToMe), round2(rows[i].IOwe), round2(rows[i].Net)
+	}
+	return rows, nil
+}
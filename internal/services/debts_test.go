@@ -0,0 +1,154 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeContactRepo struct{ rows []ports.Contact }
+
+func (r *fakeContactRepo) List(int64) ([]ports.Contact, error) { return r.rows, nil }
+func (r *fakeContactRepo) Get(_ int64, id int64) (*ports.Contact, error) {
+	for _, c := range r.rows {
+		if c.ID == id {
+			c := c
+			return &c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (r *fakeContactRepo) Create(_ int64, c *ports.Contact) error {
+	c.ID = int64(len(r.rows) + 1)
+	r.rows = append(r.rows, *c)
+	return nil
+}
+func (r *fakeContactRepo) Update(int64, *ports.Contact) error { return nil }
+func (r *fakeContactRepo) Delete(int64, int64) error          { return nil }
+
+type fakeDebtRepo struct {
+	debt     ports.Debt
+	payments []ports.DebtPayment
+	lastTx   *ports.Transaction
+}
+
+func (r *fakeDebtRepo) List(int64, ports.DebtFilter) ([]ports.Debt, error) {
+	return []ports.Debt{r.debt}, nil
+}
+func (r *fakeDebtRepo) Get(int64, int64) (*ports.Debt, error) {
+	d := r.debt
+	return &d, nil
+}
+func (r *fakeDebtRepo) Create(_ int64, d *ports.Debt) error {
+	d.ID = 1
+	r.debt = *d
+	return nil
+}
+func (r *fakeDebtRepo) Payments(int64, int64) ([]ports.DebtPayment, error) { return r.payments, nil }
+func (r *fakeDebtRepo) AddPayment(_ int64, p *ports.DebtPayment, t *ports.Transaction) error {
+	if t != nil {
+		t.ID = 99
+		p.TransactionID = &t.ID
+		r.lastTx = t
+	}
+	r.payments = append(r.payments, *p)
+	r.debt.Paid = round2(r.debt.Paid + p.Amount)
+	if r.debt.Paid >= r.debt.Amount {
+		r.debt.Status = ports.DebtSettled
+	}
+	return nil
+}
+func (r *fakeDebtRepo) Summary(int64) ([]ports.DebtBalance, error) { return nil, nil }
+
+func newDebtSvc() (*DebtService, *fakeDebtRepo) {
+	cr := &fakeContactRepo{rows: []ports.Contact{{ID: 1, Name: "Ali"}}}
+	dr := &fakeDebtRepo{}
+	return &DebtService{Repo: dr, Contacts: cr}, dr
+}
+
+func TestDebt_Create_Validates(t *testing.T) {
+	s, _ := newDebtSvc()
+	if err := s.Create(1, &ports.Debt{ContactID: 1, Direction: "gift", Amount: 10, Currency: "TRY"}); !failedWith(err, "bad_direction") {
+		t.Fatalf("want bad_direction, got %v", err)
+	}
+	if err := s.Create(1, &ports.Debt{ContactID: 7, Direction: ports.DebtLent, Amount: 10, Currency: "TRY"}); !failedWith(err, "contact_not_found") {
+		t.Fatalf("want contact_not_found, got %v", err)
+	}
+	d := ports.Debt{ContactID: 1, Direction: ports.DebtLent, Amount: 10, Currency: "TRY"}
+	if err := s.Create(1, &d); err != nil || d.Status != ports.DebtOpen || d.Remaining != 10 {
+		t.Fatalf("create failed: %v %+v", err, d)
+	}
+}
+
+func TestDebt_PartialPaymentsSettle(t *testing.T) {
+	s, dr := newDebtSvc()
+	d := ports.Debt{ContactID: 1, Direction: ports.DebtLent, Amount: 100, Currency: "TRY"}
+	if err := s.Create(1, &d); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 70}); !failedWith(err, "bad_amount") {
+		t.Fatalf("overpayment should fail, got %v", err)
+	}
+	got, _ := s.Get(1, d.ID)
+	if got.Remaining != 60 || len(got.Payments) != 1 {
+		t.Fatalf("remaining=%v payments=%d", got.Remaining, len(got.Payments))
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if dr.debt.Status != ports.DebtSettled {
+		t.Fatalf("debt should be settled, got %s", dr.debt.Status)
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 1}); !failedWith(err, "debt_settled") {
+		t.Fatalf("want debt_settled, got %v", err)
+	}
+}
+
+func TestDebt_PaymentCreatesLinkedTx(t *testing.T) {
+	s, dr := newDebtSvc()
+	stale := &staleRec{}
+	s.Cats = &fakeCategoryRepo{rows: []ports.Category{{ID: 4, Type: "expense"}, {ID: 5, Type: "income"}}}
+	s.Wallets = &fakeWalletRepo{rows: []ports.Wallet{{ID: 3, Currency: "USD"}, {ID: 6, Currency: "TRY"}}}
+	s.NetWorth = stale
+	d := ports.Debt{ContactID: 1, Direction: ports.DebtBorrowed, Amount: 50, Currency: "USD"}
+	_ = s.Create(1, &d)
+	w, c := int64(3), int64(4)
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 20, WalletID: &w}); !failedWith(err, "category_required") {
+		t.Fatalf("want category_required, got %v", err)
+	}
+	income, missing, try := int64(5), int64(9), int64(6)
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 20, WalletID: &w, CategoryID: &income}); !failedWith(err, "category_type_mismatch") {
+		t.Fatalf("want category_type_mismatch, got %v", err)
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 20, WalletID: &w, CategoryID: &missing}); !failedWith(err, "category_not_found") {
+		t.Fatalf("want category_not_found, got %v", err)
+	}
+	if _, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 20, WalletID: &try, CategoryID: &c}); !failedWith(err, "currency_mismatch") {
+		t.Fatalf("want currency_mismatch, got %v", err)
+	}
+	p, err := s.AddPayment(1, d.ID, DebtPaymentInput{Amount: 20, PaidAt: at, WalletID: &w, CategoryID: &c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TransactionID == nil || dr.lastTx == nil {
+		t.Fatal("linked transaction missing")
+	}
+	if tx := dr.lastTx; tx.Type != "expense" || tx.Currency != "USD" || tx.WalletID != 3 || !tx.OccurredAt.Equal(at) {
+		t.Fatalf("bad linked tx: %+v", tx)
+	}
+	if len(stale.since) != 1 || !stale.since[0].Equal(at) {
+		t.Fatalf("net worth not marked stale: %v", stale.since)
+	}
+}
+
+func failedWith(err error, msg string) bool {
+	ae, ok := err.(*errs.AppError)
+	return ok && ae.Message == msg
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+const (
+	defaultVerifyTTL      = 48 * time.Hour
+	defaultEmailChangeTTL = 24 * time.Hour
+)
+
+// EmailService adres doğrulama ve iki taraflı onaylı e-posta değişikliğini yönetir.
+// Doğrulama bağlantıları durumsuzdur: kullanıcı, adres ve bitiş zamanı üzerinden HMAC ile imzalanır,
+// böylece adres değişince eski bağlantılar kendiliğinden geçersizleşir.
+type EmailService struct {
+	Repo   ports.EmailRepo
+	Mailer ports.Mailer
+	Audit  *AuditService
+	Secret []byte
+
+	VerifyTTL time.Duration
+	ChangeTTL time.Duration
+	VerifyURL string
+	ChangeURL string
+	// Required doğrulanmış adres isteyen eylemler (ör. "account.export").
+	Required []string
+
+	Now func() time.Time
+}
+
+func (s *EmailService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *EmailService) verifyTTL() time.Duration {
+	if s.VerifyTTL > 0 {
+		return s.VerifyTTL
+	}
+	return defaultVerifyTTL
+}
+
+func (s *EmailService) changeTTL() time.Duration {
+	if s.ChangeTTL > 0 {
+		return s.ChangeTTL
+	}
+	return defaultEmailChangeTTL
+}
+
+func (s *EmailService) sign(format string, args ...any) string {
+	m := hmac.New(sha256.New, s.Secret)
+	_, _ = fmt.Fprintf(m, format, args...)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+func (s *EmailService) Status(uid int64) (*ports.EmailStatus, error) {
+	return s.Repo.EmailStatus(uid)
+}
+
+// Require eylem politikada doğrulama istiyorsa ve adres doğrulanmamışsa hata döner.
+func (s *EmailService) Require(uid int64, action string) error {
+	if s == nil || s.Repo == nil || !slices.Contains(s.Required, action) {
+		return nil
+	}
+	st, err := s.Repo.EmailStatus(uid)
+	if err != nil {
+		return err
+	}
+	if st.VerifiedAt == nil {
+		return errs.EmailNotVerified
+	}
+	return nil
+}
+
+// SendVerification kayıtlı adrese imzalı doğrulama bağlantısı postalar.
+func (s *EmailService) SendVerification(uid int64) error {
+	if s == nil || s.Repo == nil || s.Mailer == nil {
+		return nil
+	}
+	st, err := s.Repo.EmailStatus(uid)
+	if err != nil {
+		return err
+	}
+	if st.VerifiedAt != nil {
+		return errs.ValidationFailed("already_verified")
+	}
+	exp := s.now().Add(s.verifyTTL()).Unix()
+	token := fmt.Sprintf("%d.%d.%s", uid, exp, s.sign("verify:%d:%s:%d", uid, strings.ToLower(st.Email), exp))
+	if err := s.Mailer.Send(ports.Mail{
+		To:      st.Email,
+		Subject: "Verify your Finance Master email",
+		Text: fmt.Sprintf("Please confirm that this address belongs to your Finance Master account:\n\n%s\n\n"+
+			"The link is valid for %d hours. If you did not create an account, you can ignore this email.\n",
+			tokenLink(s.VerifyURL, token), int(s.verifyTTL().Hours())),
+	}); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.email_verify_sent", "user", &uid, nil)
+	}
+	return nil
+}
+
+// Verify doğrulama bağlantısını tüketir; zaten doğrulanmış hesapta da başarılı sayılır.
+func (s *EmailService) Verify(token string) error {
+	invalid := errs.ValidationFailed("invalid_verification_token")
+	p := strings.Split(strings.TrimSpace(token), ".")
+	if len(p) != 3 {
+		return invalid
+	}
+	uid, err1 := strconv.ParseInt(p[0], 10, 64)
+	exp, err2 := strconv.ParseInt(p[1], 10, 64)
+	if err1 != nil || err2 != nil || s.now().Unix() > exp {
+		return invalid
+	}
+	st, err := s.Repo.EmailStatus(uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalid
+	}
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(p[2]), []byte(s.sign("verify:%d:%s:%d", uid, strings.ToLower(st.Email), exp))) {
+		return invalid
+	}
+	if st.VerifiedAt != nil {
+		return nil
+	}
+	if err := s.Repo.MarkVerified(uid, st.Email, s.now()); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.email_verified", "user", &uid, map[string]any{"email": st.Email})
+	}
+	return nil
+}
+
+// RequestChange parolayla yeniden doğrulayıp değişiklik talebi açar; eski adrese onay,
+// yeni adrese doğrulama bağlantısı gider. users.email ancak iki taraf da onaylayınca değişir.
+func (s *EmailService) RequestChange(uid int64, password, newEmail string) (*ports.EmailChange, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	st, err := s.Repo.EmailStatus(uid)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := security.ArgonCheck(password, st.PassHash); !ok {
+		return nil, errs.InvalidCredentials
+	}
+	if strings.EqualFold(newEmail, st.Email) {
+		return nil, errs.ValidationFailed("same_email")
+	}
+	taken, err := s.Repo.EmailTaken(newEmail, uid)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errs.Conflict
+	}
+	if s.Mailer == nil {
+		return nil, errs.Forbidden
+	}
+	now := s.now().Truncate(time.Second)
+	c := &ports.EmailChange{UserID: uid, NewEmail: newEmail, CreatedAt: now, ExpiresAt: now.Add(s.changeTTL())}
+	if err := s.Repo.CreateChange(c); err != nil {
+		return nil, err
+	}
+	hours := int(s.changeTTL().Hours())
+	if err := s.Mailer.Send(ports.Mail{
+		To:      st.Email,
+		Subject: "Confirm your Finance Master email change",
+		Text: fmt.Sprintf("A request was made to change your Finance Master email to %s.\n\n"+
+			"Confirm it within %d hours:\n\n%s\n\n"+
+			"If you did not request this, ignore this email and change your password; the address stays unchanged.\n",
+			newEmail, hours, tokenLink(s.ChangeURL, s.changeToken(c, ports.EmailSideOld))),
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.Mailer.Send(ports.Mail{
+		To:      newEmail,
+		Subject: "Verify your new Finance Master email",
+		Text: fmt.Sprintf("Confirm that this address should be used for your Finance Master account within %d hours:\n\n%s\n",
+			hours, tokenLink(s.ChangeURL, s.changeToken(c, ports.EmailSideNew))),
+	}); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.email_change_request", "email_change", &c.ID, map[string]any{"newEmail": newEmail})
+	}
+	return c, nil
+}
+
+func (s *EmailService) changeToken(c *ports.EmailChange, side string) string {
+	exp := c.ExpiresAt.Unix()
+	return fmt.Sprintf("%d.%s.%d.%s", c.ID, side, exp, s.sign("email-change:%d:%s:%d", c.ID, side, exp))
+}
+
+// ConfirmChange bir tarafın onayını işler; CompletedAt doluysa adres değişmiştir.
+func (s *EmailService) ConfirmChange(token string) (*ports.EmailChange, error) {
+	invalid := errs.ValidationFailed("invalid_email_change_token")
+	p := strings.Split(strings.TrimSpace(token), ".")
+	if len(p) != 4 {
+		return nil, invalid
+	}
+	id, err1 := strconv.ParseInt(p[0], 10, 64)
+	exp, err2 := strconv.ParseInt(p[2], 10, 64)
+	side := p[1]
+	if err1 != nil || err2 != nil || s.now().Unix() > exp ||
+		!hmac.Equal([]byte(p[3]), []byte(s.sign("email-change:%d:%s:%d", id, side, exp))) {
+		return nil, invalid
+	}
+	c, err := s.Repo.ConfirmChange(id, side, s.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		if c.CompletedAt != nil {
+			s.Audit.Log(c.UserID, "auth.email_changed", "user", &c.UserID, map[string]any{"email": c.NewEmail})
+		} else {
+			s.Audit.Log(c.UserID, "auth.email_change_confirm", "email_change", &c.ID, map[string]any{"side": side})
+		}
+	}
+	return c, nil
+}
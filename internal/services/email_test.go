@@ -0,0 +1,170 @@
+package services
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+type fakeEmailRepo struct {
+	users   map[int64]*ports.EmailStatus
+	changes map[int64]*ports.EmailChange
+}
+
+func (f *fakeEmailRepo) EmailStatus(uid int64) (*ports.EmailStatus, error) {
+	u, ok := f.users[uid]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	cp := *u
+	return &cp, nil
+}
+func (f *fakeEmailRepo) MarkVerified(uid int64, email string, at time.Time) error {
+	if u := f.users[uid]; u != nil && u.Email == email && u.VerifiedAt == nil {
+		u.VerifiedAt = &at
+	}
+	return nil
+}
+func (f *fakeEmailRepo) EmailTaken(email string, except int64) (bool, error) {
+	for _, u := range f.users {
+		if u.Email == email && u.UserID != except {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakeEmailRepo) CreateChange(c *ports.EmailChange) error {
+	for id, o := range f.changes {
+		if o.UserID == c.UserID && o.CompletedAt == nil {
+			delete(f.changes, id)
+		}
+	}
+	c.ID = int64(len(f.changes) + 100)
+	cp := *c
+	f.changes[c.ID] = &cp
+	return nil
+}
+func (f *fakeEmailRepo) ConfirmChange(id int64, side string, now time.Time) (*ports.EmailChange, error) {
+	c, ok := f.changes[id]
+	if !ok || c.CompletedAt != nil || !now.Before(c.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	if side == ports.EmailSideOld {
+		c.OldConfirmedAt = &now
+	} else {
+		c.NewConfirmedAt = &now
+	}
+	if c.OldConfirmedAt != nil && c.NewConfirmedAt != nil {
+		if taken, _ := f.EmailTaken(c.NewEmail, c.UserID); taken {
+			return nil, errs.Conflict
+		}
+		u := f.users[c.UserID]
+		u.Email, u.VerifiedAt = c.NewEmail, &now
+		c.CompletedAt = &now
+	}
+	cp := *c
+	return &cp, nil
+}
+
+var reEmailToken = regexp.MustCompile(`token=([A-Za-z0-9._-]+)`)
+
+func mailToken(t *testing.T, m ports.Mail) string {
+	t.Helper()
+	g := reEmailToken.FindStringSubmatch(m.Text)
+	if g == nil {
+		t.Fatalf("mail without link: %+v", m)
+	}
+	return g[1]
+}
+
+func newEmailSvc(t *testing.T) (*EmailService, *fakeEmailRepo, *memMailer) {
+	t.Helper()
+	hash, err := security.ArgonHash("A1complex!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeEmailRepo{
+		users: map[int64]*ports.EmailStatus{
+			1: {UserID: 1, Email: "a@e.com", PassHash: hash},
+			2: {UserID: 2, Email: "b@e.com", PassHash: hash},
+		},
+		changes: map[int64]*ports.EmailChange{},
+	}
+	mails := &memMailer{}
+	return &EmailService{Repo: repo, Mailer: mails, Secret: []byte("test-secret"),
+		VerifyURL: "https://app.example/verify", ChangeURL: "https://app.example/email",
+		Required: []string{"account.export"}}, repo, mails
+}
+
+func TestEmail_VerifyAndPolicy(t *testing.T) {
+	s, repo, mails := newEmailSvc(t)
+
+	if err := s.Require(1, "account.export"); err != errs.EmailNotVerified {
+		t.Fatalf("unverified must be blocked: %v", err)
+	}
+	if err := s.Require(1, "transactions.create"); err != nil {
+		t.Fatalf("action outside policy: %v", err)
+	}
+	if err := s.SendVerification(1); err != nil || len(mails.sent) != 1 || mails.sent[0].To != "a@e.com" {
+		t.Fatalf("send: %v %+v", err, mails.sent)
+	}
+	token := mailToken(t, mails.sent[0])
+
+	if err := s.Verify(token + "0"); !failedWith(err, "invalid_verification_token") {
+		t.Fatalf("tampered: %v", err)
+	}
+	if err := s.Verify(token); err != nil {
+		t.Fatal(err)
+	}
+	if repo.users[1].VerifiedAt == nil || s.Require(1, "account.export") != nil {
+		t.Fatalf("not verified")
+	}
+	if err := s.SendVerification(1); !failedWith(err, "already_verified") {
+		t.Fatalf("resend after verify: %v", err)
+	}
+
+	s.Now = func() time.Time { return time.Now().Add(72 * time.Hour) }
+	_ = s.SendVerification(2)
+	s.Now = func() time.Time { return time.Now().Add(200 * time.Hour) }
+	if err := s.Verify(mailToken(t, mails.sent[len(mails.sent)-1])); !failedWith(err, "invalid_verification_token") {
+		t.Fatalf("expired: %v", err)
+	}
+}
+
+func TestEmail_ChangeNeedsBothAddresses(t *testing.T) {
+	s, repo, mails := newEmailSvc(t)
+
+	if _, err := s.RequestChange(1, "wrong-pass", "n@e.com"); err != errs.InvalidCredentials {
+		t.Fatalf("password: %v", err)
+	}
+	if _, err := s.RequestChange(1, "A1complex!", "b@e.com"); err != errs.Conflict {
+		t.Fatalf("taken: %v", err)
+	}
+	if _, err := s.RequestChange(1, "A1complex!", "n@e.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(mails.sent) != 2 || mails.sent[0].To != "a@e.com" || mails.sent[1].To != "n@e.com" {
+		t.Fatalf("mails: %+v", mails.sent)
+	}
+	oldTok, newTok := mailToken(t, mails.sent[0]), mailToken(t, mails.sent[1])
+
+	c, err := s.ConfirmChange(newTok)
+	if err != nil || c.CompletedAt != nil || repo.users[1].Email != "a@e.com" {
+		t.Fatalf("one side must not change the address: %v %+v", err, c)
+	}
+	c, err = s.ConfirmChange(oldTok)
+	if err != nil || c.CompletedAt == nil {
+		t.Fatalf("confirm old: %v %+v", err, c)
+	}
+	if u := repo.users[1]; u.Email != "n@e.com" || u.VerifiedAt == nil {
+		t.Fatalf("address not changed: %+v", u)
+	}
+	if _, err := s.ConfirmChange(oldTok); !failedWith(err, "invalid_email_change_token") {
+		t.Fatalf("completed change reused: %v", err)
+	}
+}
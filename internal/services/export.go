@@ -0,0 +1,372 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const (
+	defaultExportLinkTTL = time.Hour
+	defaultExportFileTTL = 7 * 24 * time.Hour
+	// exportBatch tek çalıştırmada işlenen en fazla iş sayısı.
+	exportBatch = 10
+	// defaultExportStaleAfter bu süreden uzun running kalan iş (çöken worker) yeniden
+	// denenir; exportMaxAttempts denemeden sonra failed olur.
+	defaultExportStaleAfter = 30 * time.Minute
+	exportMaxAttempts       = 3
+	// ExportSchemaVersion arşiv biçiminin sürümü; içe aktarma bu sürüme göre okur.
+	ExportSchemaVersion = 1
+)
+
+type ExportService struct {
+	Repo  ports.ExportRepo
+	Store ports.BlobStore
+	Audit *AuditService
+	// Secret indirme bağlantılarını imzalar.
+	Secret     []byte
+	LinkTTL    time.Duration
+	FileTTL    time.Duration
+	StaleAfter time.Duration
+
+	Now func() time.Time
+}
+
+// ExportManifest arşivdeki manifest.json; her dosyanın satır sayısı ve SHA-256 özeti.
+type ExportManifest struct {
+	Version     int            `json:"version"`
+	UserID      int64          `json:"userId"`
+	ExportID    int64          `json:"exportId"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Files       []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Rows   int    `json:"rows"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+func (s *ExportService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *ExportService) linkTTL() time.Duration {
+	if s.LinkTTL > 0 {
+		return s.LinkTTL
+	}
+	return defaultExportLinkTTL
+}
+
+func (s *ExportService) fileTTL() time.Duration {
+	if s.FileTTL > 0 {
+		return s.FileTTL
+	}
+	return defaultExportFileTTL
+}
+
+// Request yeni dışa aktarım kuyruğa alır; bekleyen iş varsa onu döner.
+func (s *ExportService) Request(uid int64) (*ports.DataExport, error) {
+	cur, err := s.Repo.Active(uid)
+	if err == nil {
+		return cur, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	e := &ports.DataExport{UserID: uid}
+	if err := s.Repo.Create(e); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "account.export", "data_export", &e.ID, nil)
+	}
+	return e, nil
+}
+
+func (s *ExportService) List(uid int64) ([]ports.DataExport, error) {
+	rows, err := s.Repo.List(uid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		s.withLink(&rows[i])
+	}
+	return rows, nil
+}
+
+func (s *ExportService) Get(uid, id int64) (*ports.DataExport, error) {
+	e, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	s.withLink(e)
+	return e, nil
+}
+
+// withLink hazır arşive, dosyanın ömrünü aşmayan süreli imzalı bağlantı ekler.
+func (s *ExportService) withLink(e *ports.DataExport) {
+	if e.Status != ports.ExportReady || e.ExpiresAt == nil {
+		return
+	}
+	exp := s.now().Add(s.linkTTL())
+	if e.ExpiresAt.Before(exp) {
+		exp = *e.ExpiresAt
+	}
+	exp = exp.Truncate(time.Second)
+	e.LinkExpiresAt = &exp
+	e.DownloadURL = fmt.Sprintf("/v1/account/exports/%d/download?exp=%d&sig=%s", e.ID, exp.Unix(), s.sign(e.ID, exp.Unix()))
+}
+
+func (s *ExportService) sign(id, exp int64) string {
+	m := hmac.New(sha256.New, s.Secret)
+	_, _ = fmt.Fprintf(m, "export:%d:%d", id, exp)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Open imzalı bağlantıyı doğrular ve arşivi açar; süresi geçmiş ya da sahte bağlantı 404 döner.
+func (s *ExportService) Open(id, exp int64, sig string) (io.ReadCloser, *ports.DataExport, error) {
+	if !hmac.Equal([]byte(sig), []byte(s.sign(id, exp))) || s.now().Unix() > exp {
+		return nil, nil, errs.NotFound
+	}
+	e, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.Status != ports.ExportReady || e.FileName == nil || (e.ExpiresAt != nil && s.now().After(*e.ExpiresAt)) {
+		return nil, nil, errs.NotFound
+	}
+	f, err := s.Store.Open(*e.FileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(e.UserID, "account.export_download", "data_export", &e.ID, nil)
+	}
+	return f, e, nil
+}
+
+// RunPending önce takılı kalmış işleri geri alır, sonra kuyruktaki işleri üretir;
+// üretilen arşiv sayısını döner.
+func (s *ExportService) RunPending() (int, error) {
+	staleAfter := s.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultExportStaleAfter
+	}
+	if _, _, err := s.Repo.ReclaimStale(s.now().Add(-staleAfter), exportMaxAttempts); err != nil {
+		return 0, err
+	}
+	var (
+		n       int
+		lastErr error
+	)
+	for i := 0; i < exportBatch; i++ {
+		e, err := s.Repo.Claim()
+		if err != nil {
+			return n, err
+		}
+		if e == nil {
+			break
+		}
+		if err := s.Build(e); err != nil {
+			lastErr = err
+			_ = s.Repo.Fail(e.ID, err.Error())
+			continue
+		}
+		n++
+	}
+	return n, lastErr
+}
+
+// Cleanup ömrü dolan arşiv dosyalarını siler.
+func (s *ExportService) Cleanup() (int, error) {
+	rows, err := s.Repo.ExpiredBefore(s.now())
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, e := range rows {
+		if e.FileName != nil {
+			if err := s.Store.Remove(*e.FileName); err != nil {
+				return n, err
+			}
+		}
+		if err := s.Repo.MarkExpired(e.ID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Build arşivi doğrudan depoya akıtarak yazar: her veri kümesi için JSON ve CSV, sonda manifest.
+func (s *ExportService) Build(e *ports.DataExport) (err error) {
+	now := s.now()
+	name := fmt.Sprintf("export-%d-%d.zip", e.UserID, e.ID)
+	f, err := s.Store.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = s.Store.Remove(name)
+		}
+	}()
+
+	sum := sha256.New()
+	cnt := &countingWriter{}
+	zw := zip.NewWriter(io.MultiWriter(f, sum, cnt))
+	man := ExportManifest{Version: ExportSchemaVersion, UserID: e.UserID, ExportID: e.ID, GeneratedAt: now}
+	for _, ds := range ports.ExportDatasets {
+		jf, err := writeZipEntry(zw, ds+".json", func(w io.Writer) (int, error) { return s.writeJSON(w, e.UserID, ds) })
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		cf, err := writeZipEntry(zw, ds+".csv", func(w io.Writer) (int, error) { return s.writeCSV(w, e.UserID, ds) })
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		man.Files = append(man.Files, jf, cf)
+	}
+	if _, err := writeZipEntry(zw, "manifest.json", func(w io.Writer) (int, error) {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return 0, enc.Encode(man)
+	}); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return s.Repo.Finish(e.ID, name, cnt.n, hex.EncodeToString(sum.Sum(nil)), now, now.Add(s.fileTTL()))
+}
+
+func writeZipEntry(zw *zip.Writer, name string, body func(io.Writer) (int, error)) (ManifestFile, error) {
+	w, err := zw.Create(name)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	sum := sha256.New()
+	cnt := &countingWriter{}
+	rows, err := body(io.MultiWriter(w, sum, cnt))
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{Name: name, Rows: rows, Bytes: cnt.n, SHA256: hex.EncodeToString(sum.Sum(nil))}, nil
+}
+
+// writeJSON satırları tek tek nesne olarak yazar; sütun sırası korunur.
+func (s *ExportService) writeJSON(w io.Writer, uid int64, ds string) (int, error) {
+	var n int
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	err := s.Repo.Stream(uid, ds, func(cols []string, vals []any) error {
+		sep := ",\n"
+		if n == 0 {
+			sep = "\n"
+		}
+		buf := []byte(sep + "{")
+		for i, c := range cols {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			k, _ := json.Marshal(c)
+			v, err := json.Marshal(exportValue(c, vals[i]))
+			if err != nil {
+				return err
+			}
+			buf = append(append(append(buf, k...), ':'), v...)
+		}
+		buf = append(buf, '}')
+		n++
+		_, err := w.Write(buf)
+		return err
+	})
+	if err != nil {
+		return n, err
+	}
+	_, err = io.WriteString(w, "\n]\n")
+	return n, err
+}
+
+func (s *ExportService) writeCSV(w io.Writer, uid int64, ds string) (int, error) {
+	cw := csv.NewWriter(w)
+	var n int
+	err := s.Repo.Stream(uid, ds, func(cols []string, vals []any) error {
+		if n == 0 {
+			if err := cw.Write(cols); err != nil {
+				return err
+			}
+		}
+		rec := make([]string, len(vals))
+		for i, v := range vals {
+			rec[i] = csvValue(exportValue(cols[i], v))
+		}
+		n++
+		return cw.Write(rec)
+	})
+	if err != nil {
+		return n, err
+	}
+	cw.Flush()
+	return n, cw.Error()
+}
+
+// exportValue sürücü değerlerini taşınabilir biçime çevirir; JSON sütunları gömülü kalır.
+func exportValue(col string, v any) any {
+	switch x := v.(type) {
+	case []byte:
+		if col == "details" && json.Valid(x) {
+			return json.RawMessage(x)
+		}
+		return string(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	}
+	return v
+}
+
+func csvValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case json.RawMessage:
+		return string(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+type countingWriter struct{ n int64 }
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeExportRepo struct {
+	e        ports.DataExport
+	finished bool
+	stale    []ports.DataExport
+}
+
+func (f *fakeExportRepo) Create(e *ports.DataExport) error {
+	e.ID, e.Status = 5, ports.ExportQueued
+	f.e = *e
+	return nil
+}
+func (f *fakeExportRepo) Active(int64) (*ports.DataExport, error)   { return nil, sql.ErrNoRows }
+func (f *fakeExportRepo) Get(_, _ int64) (*ports.DataExport, error) { e := f.e; return &e, nil }
+func (f *fakeExportRepo) GetByID(int64) (*ports.DataExport, error)  { e := f.e; return &e, nil }
+func (f *fakeExportRepo) List(int64) ([]ports.DataExport, error)    { return []ports.DataExport{f.e}, nil }
+func (f *fakeExportRepo) Claim() (*ports.DataExport, error)         { return nil, nil }
+func (f *fakeExportRepo) ReclaimStale(before time.Time, maxAttempts int) (int64, int64, error) {
+	var requeued, failed int64
+	for i := range f.stale {
+		e := &f.stale[i]
+		if e.Status != ports.ExportRunning || e.StartedAt == nil || !e.StartedAt.Before(before) {
+			continue
+		}
+		if e.Attempts >= maxAttempts {
+			e.Status = ports.ExportFailed
+			failed++
+		} else {
+			e.Status = ports.ExportQueued
+			requeued++
+		}
+	}
+	return requeued, failed, nil
+}
+func (f *fakeExportRepo) Fail(int64, string) error                            { return nil }
+func (f *fakeExportRepo) ExpiredBefore(time.Time) ([]ports.DataExport, error) { return nil, nil }
+func (f *fakeExportRepo) MarkExpired(int64) error                             { return nil }
+func (f *fakeExportRepo) Finish(id int64, file string, size int64, sha string, completed, expires time.Time) error {
+	f.finished = true
+	f.e.Status, f.e.FileName, f.e.Size, f.e.SHA256, f.e.ExpiresAt = ports.ExportReady, &file, &size, &sha, &expires
+	return nil
+}
+func (f *fakeExportRepo) Stream(_ int64, ds string, fn func([]string, []any) error) error {
+	if ds != "transactions" {
+		return nil
+	}
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	rows := [][]any{
+		{int64(1), []byte("120.50"), []byte("Market, \"Migros\""), at, nil},
+		{int64(2), []byte("9.99"), nil, at, at},
+	}
+	for _, r := range rows {
+		if err := fn([]string{"id", "amount", "note", "occurred_at", "deleted_at"}, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memStore struct{ files map[string]*bytes.Buffer }
+
+type memFile struct{ *bytes.Buffer }
+
+func (memFile) Close() error { return nil }
+
+func (m *memStore) Create(name string) (io.WriteCloser, error) {
+	m.files[name] = &bytes.Buffer{}
+	return memFile{m.files[name]}, nil
+}
+func (m *memStore) Open(name string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.files[name].Bytes())), nil
+}
+func (m *memStore) Remove(name string) error { delete(m.files, name); return nil }
+
+func TestExport_BuildArchive(t *testing.T) {
+	now := day(2026, 10, 19)
+	repo, store := &fakeExportRepo{}, &memStore{files: map[string]*bytes.Buffer{}}
+	s := &ExportService{Repo: repo, Store: store, Secret: []byte("k"), Now: func() time.Time { return now }}
+
+	e, err := s.Request(3)
+	if err != nil || e.Status != ports.ExportQueued {
+		t.Fatalf("request: %v %+v", err, e)
+	}
+	e.UserID = 3
+	if err := s.Build(e); err != nil || !repo.finished {
+		t.Fatalf("build: %v", err)
+	}
+	data := store.files["export-3-5.zip"].Bytes()
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != *repo.e.SHA256 || int64(len(data)) != *repo.e.Size {
+		t.Fatalf("archive checksum/size mismatch")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		files[f.Name], _ = io.ReadAll(rc)
+		_ = rc.Close()
+	}
+	if len(files) != 2*len(ports.ExportDatasets)+1 {
+		t.Fatalf("want json+csv per dataset plus manifest, got %d files", len(files))
+	}
+	var txs []map[string]any
+	if err := json.Unmarshal(files["transactions.json"], &txs); err != nil || len(txs) != 2 || txs[1]["deleted_at"] == nil {
+		t.Fatalf("transactions.json must include deleted rows: %v %s", err, files["transactions.json"])
+	}
+	if !strings.Contains(string(files["transactions.csv"]), `"Market, ""Migros"""`) {
+		t.Fatalf("csv not escaped: %s", files["transactions.csv"])
+	}
+	var man ExportManifest
+	if err := json.Unmarshal(files["manifest.json"], &man); err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range man.Files {
+		sum := sha256.Sum256(files[mf.Name])
+		if hex.EncodeToString(sum[:]) != mf.SHA256 {
+			t.Fatalf("manifest checksum mismatch for %s", mf.Name)
+		}
+		if mf.Name == "transactions.csv" && mf.Rows != 2 {
+			t.Fatalf("bad row count: %+v", mf)
+		}
+	}
+}
+
+func TestExport_SignedLink(t *testing.T) {
+	now := day(2026, 10, 19)
+	exp := now.Add(48 * time.Hour)
+	file := "export-3-5.zip"
+	repo := &fakeExportRepo{e: ports.DataExport{ID: 5, UserID: 3, Status: ports.ExportReady, FileName: &file, ExpiresAt: &exp}}
+	store := &memStore{files: map[string]*bytes.Buffer{file: bytes.NewBufferString("zip")}}
+	s := &ExportService{Repo: repo, Store: store, Secret: []byte("k"), Now: func() time.Time { return now }}
+
+	e, _ := s.Get(3, 5)
+	u, err := url.Parse(e.DownloadURL)
+	if err != nil || e.LinkExpiresAt == nil || !e.LinkExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("bad link: %q %v", e.DownloadURL, e.LinkExpiresAt)
+	}
+	ts, _ := strconv.ParseInt(u.Query().Get("exp"), 10, 64)
+	if f, _, err := s.Open(5, ts, u.Query().Get("sig")); err != nil {
+		t.Fatalf("valid link rejected: %v", err)
+	} else {
+		_ = f.Close()
+	}
+	if _, _, err := s.Open(5, ts+3600, u.Query().Get("sig")); err == nil {
+		t.Fatalf("tampered expiry must be rejected")
+	}
+	s.Now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, _, err := s.Open(5, ts, u.Query().Get("sig")); err == nil {
+		t.Fatalf("expired link must be rejected")
+	}
+}
+
+func TestExport_RunPendingReclaimsStale(t *testing.T) {
+	now := day(2026, 10, 19)
+	long, recent := now.Add(-2*time.Hour), now.Add(-5*time.Minute)
+	repo := &fakeExportRepo{stale: []ports.DataExport{
+		{ID: 1, Status: ports.ExportRunning, StartedAt: &long, Attempts: 1},
+		{ID: 2, Status: ports.ExportRunning, StartedAt: &long, Attempts: exportMaxAttempts},
+		{ID: 3, Status: ports.ExportRunning, StartedAt: &recent, Attempts: 1},
+	}}
+	s := &ExportService{Repo: repo, Now: func() time.Time { return now }}
+
+	if _, err := s.RunPending(); err != nil {
+		t.Fatal(err)
+	}
+	got := []string{repo.stale[0].Status, repo.stale[1].Status, repo.stale[2].Status}
+	if got[0] != ports.ExportQueued || got[1] != ports.ExportFailed || got[2] != ports.ExportRunning {
+		t.Fatalf("bad statuses: %v", got)
+	}
+}
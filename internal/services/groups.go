@@ -0,0 +1,551 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+type GroupService struct {
+	Repo   ports.GroupRepo
+	Cats   ports.CategoryRepo
+	Access WalletAccess
+	Audit  *AuditService
+
+	InviteTTL time.Duration
+	Now       func() time.Time
+}
+
+// SplitPart bir üyenin paydaki karşılığı: shares modunda ağırlık, exact modunda tutar;
+// equal modunda yok sayılır.
+type SplitPart struct {
+	MemberID int64
+	Value    float64
+}
+
+type GroupExpenseInput struct {
+	PaidBy      int64
+	Amount      float64
+	Description string
+	Mode        string
+	Parts       []SplitPart
+	OccurredAt  time.Time
+	// Ödeyen isteği yapan kullanıcıysa gider kişisel deftere de işlenebilir.
+	WalletID   *int64
+	CategoryID *int64
+}
+
+type SettlementInput struct {
+	FromMember int64
+	ToMember   int64
+	Amount     float64
+	SettledAt  time.Time
+	// Verilirse ilgili katılımcının cüzdanına gider/gelir işlemi yazılır.
+	FromWalletID   *int64
+	FromCategoryID *int64
+	ToWalletID     *int64
+	ToCategoryID   *int64
+}
+
+func (s *GroupService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func toCents(v float64) int64   { return int64(math.Round(v * 100)) }
+func fromCents(c int64) float64 { return float64(c) / 100 }
+
+// SplitExpense tutarı kuruş hassasiyetinde paylara böler; paylar toplamı her zaman tutara eşittir.
+func SplitExpense(amount float64, mode string, parts []SplitPart) ([]ports.GroupShare, error) {
+	if len(parts) == 0 {
+		return nil, errs.ValidationFailed("split_members_required")
+	}
+	seen := map[int64]bool{}
+	for _, p := range parts {
+		if seen[p.MemberID] {
+			return nil, errs.ValidationFailed("split_duplicate_member")
+		}
+		seen[p.MemberID] = true
+	}
+	total := toCents(amount)
+	if total <= 0 {
+		return nil, errs.ValidationFailed("bad_amount")
+	}
+	cents := make([]int64, len(parts))
+
+	switch mode {
+	case ports.SplitEqual:
+		n := int64(len(parts))
+		for i := range cents {
+			cents[i] = total / n
+			if int64(i) < total%n {
+				cents[i]++
+			}
+		}
+	case ports.SplitShares:
+		var sum float64
+		for _, p := range parts {
+			if p.Value <= 0 {
+				return nil, errs.ValidationFailed("split_bad_share")
+			}
+			sum += p.Value
+		}
+		// En büyük kalan yöntemi: taban paylar dağıtılır, artan kuruşlar kesri en büyük olanlara verilir.
+		rem := make([]float64, len(parts))
+		var used int64
+		for i, p := range parts {
+			exact := float64(total) * p.Value / sum
+			cents[i] = int64(math.Floor(exact))
+			rem[i] = exact - float64(cents[i])
+			used += cents[i]
+		}
+		idx := make([]int, len(parts))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return rem[idx[a]] > rem[idx[b]] })
+		for k := 0; used < total; k++ {
+			cents[idx[k%len(idx)]]++
+			used++
+		}
+	case ports.SplitExact:
+		var sum int64
+		for i, p := range parts {
+			if p.Value < 0 {
+				return nil, errs.ValidationFailed("split_bad_amount")
+			}
+			cents[i] = toCents(p.Value)
+			sum += cents[i]
+		}
+		if sum != total {
+			return nil, errs.ValidationFailed("split_sum_mismatch")
+		}
+	default:
+		return nil, errs.ValidationFailed("bad_split_mode")
+	}
+
+	out := make([]ports.GroupShare, len(parts))
+	for i, p := range parts {
+		out[i] = ports.GroupShare{MemberID: p.MemberID, Amount: fromCents(cents[i])}
+	}
+	return out, nil
+}
+
+// SettleUpPlan bakiyeleri kapatan ödemeleri üretir: her adımda en büyük borçlu en
+// büyük alacaklıya öder; böylece en fazla n-1 ödeme önerilir.
+func SettleUpPlan(balances []ports.GroupBalance) []ports.SettleUp {
+	type acc struct {
+		id    int64
+		cents int64
+	}
+	var cred, debt []acc
+	for _, b := range balances {
+		c := toCents(b.Net)
+		switch {
+		case c > 0:
+			cred = append(cred, acc{b.MemberID, c})
+		case c < 0:
+			debt = append(debt, acc{b.MemberID, -c})
+		}
+	}
+	order := func(xs []acc) {
+		sort.Slice(xs, func(i, j int) bool {
+			if xs[i].cents != xs[j].cents {
+				return xs[i].cents > xs[j].cents
+			}
+			return xs[i].id < xs[j].id
+		})
+	}
+	out := []ports.SettleUp{}
+	for len(cred) > 0 && len(debt) > 0 {
+		order(cred)
+		order(debt)
+		amt := min(cred[0].cents, debt[0].cents)
+		out = append(out, ports.SettleUp{FromMember: debt[0].id, ToMember: cred[0].id, Amount: fromCents(amt)})
+		cred[0].cents -= amt
+		debt[0].cents -= amt
+		if cred[0].cents == 0 {
+			cred = cred[1:]
+		}
+		if debt[0].cents == 0 {
+			debt = debt[1:]
+		}
+	}
+	return out
+}
+
+// member isteği yapan kullanıcının gruptaki üyeliğini döner; üye olmayanlar için not_found.
+func (s *GroupService) member(uid, groupID int64) (*ports.GroupMember, error) {
+	m, err := s.Repo.MemberByUser(groupID, uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.NotFound
+	}
+	return m, err
+}
+
+func (s *GroupService) Create(uid int64, g *ports.ExpenseGroup, memberName string) error {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" {
+		return errs.ValidationFailed("name_required")
+	}
+	memberName = strings.TrimSpace(memberName)
+	if memberName == "" {
+		return errs.ValidationFailed("member_name_required")
+	}
+	owner := &ports.GroupMember{UserID: &uid, Name: memberName}
+	if err := s.Repo.Create(uid, g, owner); err != nil {
+		return err
+	}
+	g.Members = []ports.GroupMember{*owner}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "group.create", "expense_group", &g.ID, nil)
+	}
+	return nil
+}
+
+func (s *GroupService) List(uid int64) ([]ports.ExpenseGroup, error) { return s.Repo.ListForUser(uid) }
+
+func (s *GroupService) Get(uid, groupID int64) (*ports.ExpenseGroup, error) {
+	if _, err := s.member(uid, groupID); err != nil {
+		return nil, err
+	}
+	g, err := s.Repo.Get(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if g.Members, err = s.Repo.Members(groupID); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// AddMember yalnızca grup sahibine açıktır. E-posta verilirse üye bağlanmadan davet
+// olarak yazılır; hesabın var olup olmadığına bakılmaz, yanıt her durumda aynıdır.
+func (s *GroupService) AddMember(uid, groupID int64, name, email string) (*ports.GroupMember, error) {
+	g, err := s.Repo.Get(groupID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if g.OwnerID != uid {
+		if _, err := s.member(uid, groupID); err != nil {
+			return nil, err
+		}
+		return nil, errs.Forbidden
+	}
+	m := &ports.GroupMember{GroupID: groupID, Name: strings.TrimSpace(name), CreatedAt: s.now()}
+	if m.Name == "" {
+		return nil, errs.ValidationFailed("name_required")
+	}
+	var token, hash string
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		ttl := s.InviteTTL
+		if ttl <= 0 {
+			ttl = 7 * 24 * time.Hour
+		}
+		if token, err = randomToken(); err != nil {
+			return nil, err
+		}
+		hash = security.SHA256Hex(token)
+		exp := s.now().Add(ttl)
+		m.InviteEmail, m.InviteExpiresAt = &email, &exp
+	}
+	if err := s.Repo.AddMember(m, hash); err != nil {
+		return nil, err
+	}
+	m.InviteToken = token
+	if s.Audit != nil {
+		s.Audit.Log(uid, "group.add_member", "expense_group", &groupID, map[string]any{"member": m.ID, "invited": token != ""})
+	}
+	return m, nil
+}
+
+// Accept grup davetini, davet edilen e-posta ile oturum açmış kullanıcı adına kabul eder
+// ve bekleyen üyeyi o kullanıcıya bağlar.
+func (s *GroupService) Accept(uid int64, token string) (*ports.ExpenseGroup, error) {
+	m, err := s.Repo.MemberByInvite(security.SHA256Hex(strings.TrimSpace(token)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.InviteExpiresAt == nil || !s.now().Before(*m.InviteExpiresAt) {
+		return nil, errs.ValidationFailed("invite_expired")
+	}
+	if _, err := s.Repo.MemberByUser(m.GroupID, uid); err == nil {
+		return nil, errs.ValidationFailed("already_member")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err := s.Repo.LinkInvited(m.ID, uid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.Forbidden
+		}
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "group.join", "expense_group", &m.GroupID, map[string]any{"member": m.ID})
+	}
+	return s.Get(uid, m.GroupID)
+}
+
+func (s *GroupService) AddExpense(uid, groupID int64, in GroupExpenseInput) (*ports.GroupExpense, error) {
+	me, err := s.member(uid, groupID)
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.Repo.Get(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberSet(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := members[in.PaidBy]; !ok {
+		return nil, errs.ValidationFailed("payer_not_in_group")
+	}
+	parts := in.Parts
+	if in.Mode == ports.SplitEqual && len(parts) == 0 {
+		for id := range members {
+			parts = append(parts, SplitPart{MemberID: id})
+		}
+		sort.Slice(parts, func(i, j int) bool { return parts[i].MemberID < parts[j].MemberID })
+	}
+	for _, p := range parts {
+		if _, ok := members[p.MemberID]; !ok {
+			return nil, errs.ValidationFailed("split_member_not_in_group")
+		}
+	}
+	shares, err := SplitExpense(in.Amount, in.Mode, parts)
+	if err != nil {
+		return nil, err
+	}
+	if in.OccurredAt.IsZero() {
+		in.OccurredAt = s.now()
+	}
+	e := &ports.GroupExpense{
+		GroupID: groupID, PaidBy: in.PaidBy, Amount: round2(in.Amount),
+		Description: strings.TrimSpace(in.Description), SplitMode: in.Mode,
+		OccurredAt: in.OccurredAt.UTC(), CreatedBy: uid, Shares: shares,
+	}
+
+	var t *ports.Transaction
+	if in.WalletID != nil {
+		if in.PaidBy != me.ID {
+			return nil, errs.ValidationFailed("wallet_only_for_own_payment")
+		}
+		t, err = s.ledgerTx(uid, in.WalletID, in.CategoryID, "expense", e.Amount, g.Currency, e.OccurredAt, e.Description)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.AddExpense(e, t); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "group.expense", "expense_group", &groupID, map[string]any{
+			"expense": e.ID, "amount": e.Amount, "mode": e.SplitMode,
+		})
+	}
+	return e, nil
+}
+
+func (s *GroupService) Expenses(uid, groupID int64) ([]ports.GroupExpense, error) {
+	if _, err := s.member(uid, groupID); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.Expenses(groupID)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := s.Repo.Shares(groupID)
+	if err != nil {
+		return nil, err
+	}
+	byExp := map[int64][]ports.GroupShare{}
+	for _, sh := range shares {
+		byExp[sh.ExpenseID] = append(byExp[sh.ExpenseID], sh)
+	}
+	for i := range rows {
+		rows[i].Shares = byExp[rows[i].ID]
+	}
+	return rows, nil
+}
+
+// Balances üye başına ödenen, payına düşen ve net durumu; yapılan ödemeler nete yansır.
+func (s *GroupService) Balances(uid, groupID int64) ([]ports.GroupBalance, []ports.SettleUp, error) {
+	if _, err := s.member(uid, groupID); err != nil {
+		return nil, nil, err
+	}
+	members, err := s.Repo.Members(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	exps, err := s.Repo.Expenses(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares, err := s.Repo.Shares(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	settles, err := s.Repo.Settlements(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bal := GroupBalances(members, exps, shares, settles)
+	return bal, SettleUpPlan(bal), nil
+}
+
+// GroupBalances bakiyeleri kuruş üzerinden hesaplar.
+func GroupBalances(members []ports.GroupMember, exps []ports.GroupExpense, shares []ports.GroupShare, settles []ports.GroupSettlement) []ports.GroupBalance {
+	paid := map[int64]int64{}
+	owed := map[int64]int64{}
+	moved := map[int64]int64{}
+	for _, e := range exps {
+		paid[e.PaidBy] += toCents(e.Amount)
+	}
+	for _, sh := range shares {
+		owed[sh.MemberID] += toCents(sh.Amount)
+	}
+	for _, st := range settles {
+		c := toCents(st.Amount)
+		moved[st.FromMember] += c
+		moved[st.ToMember] -= c
+	}
+	out := make([]ports.GroupBalance, len(members))
+	for i, m := range members {
+		out[i] = ports.GroupBalance{
+			MemberID: m.ID, Name: m.Name,
+			Paid: fromCents(paid[m.ID]), Owed: fromCents(owed[m.ID]),
+			Net: fromCents(paid[m.ID] - owed[m.ID] + moved[m.ID]),
+		}
+	}
+	return out
+}
+
+func (s *GroupService) Settle(uid, groupID int64, in SettlementInput) (*ports.GroupSettlement, error) {
+	if _, err := s.member(uid, groupID); err != nil {
+		return nil, err
+	}
+	g, err := s.Repo.Get(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberSet(groupID)
+	if err != nil {
+		return nil, err
+	}
+	from, okFrom := members[in.FromMember]
+	to, okTo := members[in.ToMember]
+	if !okFrom || !okTo {
+		return nil, errs.ValidationFailed("member_not_in_group")
+	}
+	if in.FromMember == in.ToMember {
+		return nil, errs.ValidationFailed("settle_same_member")
+	}
+	if toCents(in.Amount) <= 0 {
+		return nil, errs.ValidationFailed("bad_amount")
+	}
+	if in.SettledAt.IsZero() {
+		in.SettledAt = s.now()
+	}
+	st := &ports.GroupSettlement{
+		GroupID: groupID, FromMember: from.ID, ToMember: to.ID, Amount: round2(in.Amount),
+		SettledAt: in.SettledAt.UTC(), CreatedBy: uid,
+	}
+	note := "Settle-up: " + g.Name
+
+	// Kişisel deftere yalnızca isteği yapan kendi tarafını yazabilir.
+	var fromTx, toTx *ports.Transaction
+	if in.FromWalletID != nil {
+		if from.UserID == nil || *from.UserID != uid {
+			return nil, errs.Forbidden
+		}
+		if fromTx, err = s.ledgerTx(*from.UserID, in.FromWalletID, in.FromCategoryID, "expense", st.Amount, g.Currency, st.SettledAt, note); err != nil {
+			return nil, err
+		}
+	}
+	if in.ToWalletID != nil {
+		if to.UserID == nil || *to.UserID != uid {
+			return nil, errs.Forbidden
+		}
+		if toTx, err = s.ledgerTx(*to.UserID, in.ToWalletID, in.ToCategoryID, "income", st.Amount, g.Currency, st.SettledAt, note); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.AddSettlement(st, fromTx, toTx); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "group.settle", "expense_group", &groupID, map[string]any{
+			"from": st.FromMember, "to": st.ToMember, "amount": st.Amount,
+		})
+	}
+	return st, nil
+}
+
+func (s *GroupService) Settlements(uid, groupID int64) ([]ports.GroupSettlement, error) {
+	if _, err := s.member(uid, groupID); err != nil {
+		return nil, err
+	}
+	return s.Repo.Settlements(groupID)
+}
+
+func (s *GroupService) memberSet(groupID int64) (map[int64]ports.GroupMember, error) {
+	rows, err := s.Repo.Members(groupID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]ports.GroupMember, len(rows))
+	for _, m := range rows {
+		out[m.ID] = m
+	}
+	return out, nil
+}
+
+// ledgerTx kişisel deftere yazılacak işlemi hazırlar; cüzdan sahibe yazılabilir, kategori sahibin
+// ve işlem tipiyle aynı tipte olmalı.
+func (s *GroupService) ledgerTx(owner int64, walletID, categoryID *int64, typ string, amount float64, currency string, at time.Time, note string) (*ports.Transaction, error) {
+	if categoryID == nil {
+		return nil, errs.ValidationFailed("category_required")
+	}
+	if s.Cats != nil {
+		c, err := s.Cats.Get(owner, *categoryID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ValidationFailed("category_not_found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c.Type != typ {
+			return nil, errs.ValidationFailed("category_type_mismatch")
+		}
+	}
+	if s.Access != nil {
+		if err := s.Access.CanWrite(owner, *walletID); err != nil {
+			return nil, err
+		}
+	}
+	t := &ports.Transaction{
+		UserID: owner, WalletID: *walletID, CategoryID: *categoryID, Type: typ,
+		Amount: amount, Currency: currency, OccurredAt: at, Status: statusFor(at, s.now()),
+	}
+	if note != "" {
+		t.Note = &note
+	}
+	return t, nil
+}
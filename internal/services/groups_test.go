@@ -0,0 +1,203 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func splitAmounts(t *testing.T, amount float64, mode string, parts []SplitPart) []float64 {
+	t.Helper()
+	shares, err := SplitExpense(amount, mode, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]float64, len(shares))
+	for i, s := range shares {
+		out[i] = s.Amount
+	}
+	return out
+}
+
+func TestSplitExpense_Modes(t *testing.T) {
+	parts := []SplitPart{{MemberID: 1}, {MemberID: 2}, {MemberID: 3}}
+	eq := splitAmounts(t, 100, ports.SplitEqual, parts)
+	if eq[0] != 33.34 || eq[1] != 33.33 || eq[2] != 33.33 {
+		t.Fatalf("equal: %v", eq)
+	}
+
+	weighted := []SplitPart{{MemberID: 1, Value: 1}, {MemberID: 2, Value: 1}, {MemberID: 3, Value: 2}}
+	sh := splitAmounts(t, 10, ports.SplitShares, weighted)
+	if sh[0] != 2.5 || sh[1] != 2.5 || sh[2] != 5 {
+		t.Fatalf("shares: %v", sh)
+	}
+}
+
+func TestSplitExpense_SharesRoundingSumsToTotal(t *testing.T) {
+	parts := []SplitPart{{MemberID: 1, Value: 1}, {MemberID: 2, Value: 1}, {MemberID: 3, Value: 1}}
+	got := splitAmounts(t, 0.10, ports.SplitShares, parts)
+	var sum int64
+	for _, v := range got {
+		sum += toCents(v)
+	}
+	if sum != 10 {
+		t.Fatalf("shares must sum to total, got %v", got)
+	}
+}
+
+func TestSplitExpense_ExactMismatch(t *testing.T) {
+	parts := []SplitPart{{MemberID: 1, Value: 30}, {MemberID: 2, Value: 60}}
+	if _, err := SplitExpense(100, ports.SplitExact, parts); !failedWith(err, "split_sum_mismatch") {
+		t.Fatalf("want split_sum_mismatch, got %v", err)
+	}
+	dup := []SplitPart{{MemberID: 1}, {MemberID: 1}}
+	if _, err := SplitExpense(100, ports.SplitEqual, dup); !failedWith(err, "split_duplicate_member") {
+		t.Fatalf("want split_duplicate_member, got %v", err)
+	}
+}
+
+func TestGroupBalances_AndSettleUp(t *testing.T) {
+	members := []ports.GroupMember{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}}
+	// A 90 öder (eşit), B 30 öder (eşit) → paylar kişi başı 40.
+	exps := []ports.GroupExpense{{ID: 1, PaidBy: 1, Amount: 90}, {ID: 2, PaidBy: 2, Amount: 30}}
+	shares := []ports.GroupShare{
+		{ExpenseID: 1, MemberID: 1, Amount: 30}, {ExpenseID: 1, MemberID: 2, Amount: 30}, {ExpenseID: 1, MemberID: 3, Amount: 30},
+		{ExpenseID: 2, MemberID: 1, Amount: 10}, {ExpenseID: 2, MemberID: 2, Amount: 10}, {ExpenseID: 2, MemberID: 3, Amount: 10},
+	}
+	bal := GroupBalances(members, exps, shares, nil)
+	if bal[0].Net != 50 || bal[1].Net != -10 || bal[2].Net != -40 {
+		t.Fatalf("bad balances: %+v", bal)
+	}
+	plan := SettleUpPlan(bal)
+	if len(plan) != 2 {
+		t.Fatalf("want 2 payments, got %+v", plan)
+	}
+	if plan[0] != (ports.SettleUp{FromMember: 3, ToMember: 1, Amount: 40}) ||
+		plan[1] != (ports.SettleUp{FromMember: 2, ToMember: 1, Amount: 10}) {
+		t.Fatalf("bad plan: %+v", plan)
+	}
+
+	settled := GroupBalances(members, exps, shares, []ports.GroupSettlement{{FromMember: 3, ToMember: 1, Amount: 40}})
+	if settled[0].Net != 10 || settled[2].Net != 0 {
+		t.Fatalf("settlement not applied: %+v", settled)
+	}
+}
+
+type fakeGroupRepo struct {
+	ports.GroupRepo
+	ownerID        int64
+	members        []ports.GroupMember
+	invites        map[string]int64
+	emails         map[int64]string
+	fromTx, toTx   *ports.Transaction
+	settlementDone bool
+}
+
+func (f *fakeGroupRepo) Get(id int64) (*ports.ExpenseGroup, error) {
+	return &ports.ExpenseGroup{ID: id, OwnerID: f.ownerID, Name: "Tatil", Currency: "TRY"}, nil
+}
+func (f *fakeGroupRepo) AddMember(m *ports.GroupMember, tokenHash string) error {
+	m.ID = int64(100 + len(f.members))
+	f.members = append(f.members, *m)
+	if tokenHash != "" {
+		if f.invites == nil {
+			f.invites = map[string]int64{}
+		}
+		f.invites[tokenHash] = m.ID
+	}
+	return nil
+}
+func (f *fakeGroupRepo) MemberByInvite(hash string) (*ports.GroupMember, error) {
+	for _, m := range f.members {
+		if id, ok := f.invites[hash]; ok && id == m.ID && m.UserID == nil {
+			cp := m
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (f *fakeGroupRepo) LinkInvited(memberID, userID int64) error {
+	for i, m := range f.members {
+		if m.ID == memberID && m.InviteEmail != nil && *m.InviteEmail == f.emails[userID] {
+			f.members[i].UserID, f.members[i].InviteEmail = &userID, nil
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+func (f *fakeGroupRepo) Members(int64) ([]ports.GroupMember, error) { return f.members, nil }
+func (f *fakeGroupRepo) MemberByUser(_, uid int64) (*ports.GroupMember, error) {
+	for _, m := range f.members {
+		if m.UserID != nil && *m.UserID == uid {
+			cp := m
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (f *fakeGroupRepo) AddSettlement(_ *ports.GroupSettlement, fromTx, toTx *ports.Transaction) error {
+	f.fromTx, f.toTx, f.settlementDone = fromTx, toTx, true
+	return nil
+}
+
+func TestGroupSettle_LedgerOnlyForCaller(t *testing.T) {
+	alice, bob := int64(1), int64(2)
+	repo := &fakeGroupRepo{members: []ports.GroupMember{{ID: 10, UserID: &alice}, {ID: 11, UserID: &bob}}}
+	cats := &fakeCategoryRepo{rows: []ports.Category{{ID: 5, Type: "expense"}, {ID: 6, Type: "income"}}}
+	s := &GroupService{Repo: repo, Cats: cats}
+	wallet, expCat, incCat := int64(7), int64(5), int64(6)
+
+	// Alice, Bob'un cüzdanına gelir yazamaz.
+	_, err := s.Settle(alice, 1, SettlementInput{FromMember: 10, ToMember: 11, Amount: 20, ToWalletID: &wallet, ToCategoryID: &incCat})
+	if err != errs.Forbidden || repo.settlementDone {
+		t.Fatalf("foreign wallet: %v", err)
+	}
+	_, err = s.Settle(alice, 1, SettlementInput{FromMember: 10, ToMember: 11, Amount: 20, FromWalletID: &wallet, FromCategoryID: &incCat})
+	if !failedWith(err, "category_type_mismatch") {
+		t.Fatalf("category type: %v", err)
+	}
+	if _, err := s.Settle(alice, 1, SettlementInput{FromMember: 10, ToMember: 11, Amount: 20, FromWalletID: &wallet, FromCategoryID: &expCat}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.fromTx == nil || repo.fromTx.UserID != alice || repo.toTx != nil {
+		t.Fatalf("unexpected ledger rows: %+v %+v", repo.fromTx, repo.toTx)
+	}
+}
+
+func TestGroupAddMember_InviteNeedsConsent(t *testing.T) {
+	alice, bob, eve := int64(1), int64(2), int64(3)
+	repo := &fakeGroupRepo{
+		ownerID: alice,
+		members: []ports.GroupMember{{ID: 10, UserID: &alice}},
+		emails:  map[int64]string{bob: "bob@example.com", eve: "eve@example.com"},
+	}
+	s := &GroupService{Repo: repo}
+
+	// Kayıtlı olsun olmasın e-posta ile eklenen üye bağlanmaz, yanıt aynı biçimdedir.
+	known, err := s.AddMember(alice, 1, "Bob", " Bob@Example.com ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := s.AddMember(alice, 1, "Ghost", "nobody@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*ports.GroupMember{known, unknown} {
+		if m.UserID != nil || m.InviteEmail == nil || m.InviteToken == "" {
+			t.Fatalf("member should be a pending invite: %+v", m)
+		}
+	}
+
+	// Davet yalnızca davet edilen e-postanın sahibi tarafından kabul edilebilir.
+	if _, err := s.Accept(eve, known.InviteToken); err != errs.Forbidden {
+		t.Fatalf("foreign accept: %v", err)
+	}
+	if _, err := s.Accept(bob, known.InviteToken); err != nil {
+		t.Fatal(err)
+	}
+	if linked := repo.members[1]; linked.UserID == nil || *linked.UserID != bob {
+		t.Fatalf("member not linked after accept: %+v", linked)
+	}
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+var roleRank = map[string]int{ports.RoleViewer: 1, ports.RoleEditor: 2, ports.RoleOwner: 3}
+
+// HouseholdService ortak (hane) cüzdanlarını yönetir. Yetki kontrolleri burada
+// yapılır; repo sorguları yalnızca kapsamı daraltır.
+type HouseholdService struct {
+	Repo    ports.HouseholdRepo
+	Wallets ports.WalletRepo
+	Tx      ports.TxRepo
+	Audit   *AuditService
+
+	InviteTTL time.Duration
+	Now       func() time.Time
+}
+
+func (s *HouseholdService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// require kullanıcının hanede en az min rolüne sahip olduğunu doğrular.
+// Üye olmayanlara hanenin varlığı sızdırılmaz (not_found).
+func (s *HouseholdService) require(uid, hid int64, min string) (string, error) {
+	role, err := s.Repo.Role(hid, uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errs.NotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if roleRank[role] < roleRank[min] {
+		return "", errs.Forbidden
+	}
+	return role, nil
+}
+
+// WalletRoles kullanıcının cüzdandaki rolünü verir (HouseholdRepo.WalletRole);
+// kişisel cüzdanın sahibi owner sayılır, erişim yoksa sql.ErrNoRows.
+type WalletRoles interface {
+	WalletRole(userID, walletID int64) (string, error)
+}
+
+// requireWalletRole kullanıcının cüzdanda en az min rolüne sahip olduğunu doğrular.
+func requireWalletRole(roles WalletRoles, uid, walletID int64, min string) error {
+	role, err := roles.WalletRole(uid, walletID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.NotFound
+	}
+	if err != nil {
+		return err
+	}
+	if roleRank[role] < roleRank[min] {
+		return errs.Forbidden
+	}
+	return nil
+}
+
+// CanWrite kişisel ya da hane cüzdanına işlem yazma yetkisini doğrular (TxService kullanır).
+func (s *HouseholdService) CanWrite(uid, walletID int64) error {
+	return requireWalletRole(s.Repo, uid, walletID, ports.RoleEditor)
+}
+
+func (s *HouseholdService) Create(uid int64, h *ports.Household) error {
+	h.Name = strings.TrimSpace(h.Name)
+	if h.Name == "" {
+		return errs.ValidationFailed("name_required")
+	}
+	if err := s.Repo.Create(uid, h); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.create", "household", &h.ID, nil)
+	}
+	return nil
+}
+
+func (s *HouseholdService) List(uid int64) ([]ports.Household, error) {
+	return s.Repo.ListForUser(uid)
+}
+
+func (s *HouseholdService) Get(uid, hid int64) (*ports.Household, error) {
+	h, err := s.Repo.Get(uid, hid)
+	if err != nil {
+		return nil, err
+	}
+	if h.Members, err = s.Repo.Members(hid); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *HouseholdService) Delete(uid, hid int64) error {
+	if _, err := s.require(uid, hid, ports.RoleOwner); err != nil {
+		return err
+	}
+	if err := s.Repo.Delete(hid); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.delete", "household", &hid, nil)
+	}
+	return nil
+}
+
+func (s *HouseholdService) Invite(uid, hid int64, email, role string) (*ports.HouseholdInvite, error) {
+	if _, err := s.require(uid, hid, ports.RoleOwner); err != nil {
+		return nil, err
+	}
+	if role != ports.RoleEditor && role != ports.RoleViewer {
+		return nil, errs.ValidationFailed("bad_role")
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, errs.ValidationFailed("email_required")
+	}
+	ttl := s.InviteTTL
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	inv := &ports.HouseholdInvite{
+		HouseholdID: hid, Email: email, Role: role, InvitedBy: uid,
+		ExpiresAt: s.now().Add(ttl), CreatedAt: s.now(),
+	}
+	if err := s.Repo.CreateInvite(inv, security.SHA256Hex(token)); err != nil {
+		return nil, err
+	}
+	inv.Token = token
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.invite", "household", &hid, map[string]any{"role": role})
+	}
+	return inv, nil
+}
+
+// Accept daveti, davet edilen e-posta ile oturum açmış kullanıcı adına kabul eder.
+func (s *HouseholdService) Accept(uid int64, token string) (*ports.Household, error) {
+	inv, err := s.Repo.InviteByHash(security.SHA256Hex(strings.TrimSpace(token)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if inv.AcceptedAt != nil {
+		return nil, errs.ValidationFailed("invite_used")
+	}
+	if !s.now().Before(inv.ExpiresAt) {
+		return nil, errs.ValidationFailed("invite_expired")
+	}
+	email, err := s.Repo.UserEmail(uid)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(email, inv.Email) {
+		return nil, errs.Forbidden
+	}
+	if err := s.Repo.AcceptInvite(inv, uid); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ValidationFailed("invite_used")
+		}
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.join", "household", &inv.HouseholdID, map[string]any{"role": inv.Role})
+	}
+	return s.Get(uid, inv.HouseholdID)
+}
+
+func (s *HouseholdService) SetRole(uid, hid, memberID int64, role string) error {
+	if _, err := s.require(uid, hid, ports.RoleOwner); err != nil {
+		return err
+	}
+	if roleRank[role] == 0 {
+		return errs.ValidationFailed("bad_role")
+	}
+	if memberID == uid && role != ports.RoleOwner {
+		if err := s.ensureOtherOwner(hid, uid); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.SetRole(hid, memberID, role); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.role", "household", &hid, map[string]any{"member": memberID, "role": role})
+	}
+	return nil
+}
+
+// RemoveMember: sahipler herkesi çıkarabilir; diğer üyeler yalnızca kendileri ayrılabilir.
+func (s *HouseholdService) RemoveMember(uid, hid, memberID int64) error {
+	min := ports.RoleOwner
+	if memberID == uid {
+		min = ports.RoleViewer
+	}
+	role, err := s.require(uid, hid, min)
+	if err != nil {
+		return err
+	}
+	if memberID == uid && role == ports.RoleOwner {
+		if err := s.ensureOtherOwner(hid, uid); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.RemoveMember(hid, memberID); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "household.remove_member", "household", &hid, map[string]any{"member": memberID})
+	}
+	return nil
+}
+
+func (s *HouseholdService) ensureOtherOwner(hid, uid int64) error {
+	members, err := s.Repo.Members(hid)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.UserID != uid && m.Role == ports.RoleOwner {
+			return nil
+		}
+	}
+	return errs.ValidationFailed("last_owner")
+}
+
+func (s *HouseholdService) ListWallets(uid, hid int64) ([]ports.Wallet, error) {
+	if _, err := s.require(uid, hid, ports.RoleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.Wallets(hid)
+	if err != nil {
+		return nil, err
+	}
+	bal, err := s.Repo.WalletBalances(hid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		fillWalletBalance(&rows[i], bal[rows[i].ID])
+	}
+	return rows, nil
+}
+
+func (s *HouseholdService) CreateWallet(uid, hid int64, w *ports.Wallet) error {
+	if _, err := s.require(uid, hid, ports.RoleEditor); err != nil {
+		return err
+	}
+	if err := normalizeWallet(w); err != nil {
+		return err
+	}
+	w.HouseholdID = &hid
+	if err := s.Wallets.Create(uid, w); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "wallet.create", "wallet", &w.ID, map[string]any{"household": hid})
+	}
+	return nil
+}
+
+func (s *HouseholdService) WalletTransactions(uid, hid, walletID int64, page, size int) ([]ports.Transaction, int, error) {
+	if _, err := s.wallet(uid, hid, walletID, ports.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	return s.Repo.WalletTransactions(walletID, page, size)
+}
+
+// CreateWalletTx hane cüzdanına işlem ekler; işlem ekleyen kullanıcıya yazılır (created_by).
+func (s *HouseholdService) CreateWalletTx(uid, hid, walletID int64, t *ports.Transaction) error {
+	w, err := s.wallet(uid, hid, walletID, ports.RoleEditor)
+	if err != nil {
+		return err
+	}
+	t.WalletID = w.ID
+	if t.Currency == "" {
+		t.Currency = w.Currency
+	}
+	if err := s.Tx.Create(uid, t); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "tx.create", "transaction", &t.ID, map[string]any{
+			"amount": t.Amount, "currency": t.Currency, "type": t.Type, "household": hid,
+		})
+	}
+	return nil
+}
+
+func (s *HouseholdService) DeleteWalletTx(uid, hid, walletID, txID int64) error {
+	if _, err := s.wallet(uid, hid, walletID, ports.RoleEditor); err != nil {
+		return err
+	}
+	t, err := s.Repo.WalletTransaction(walletID, txID)
+	if err != nil {
+		return err
+	}
+	if t.Status == ports.TxReconciled {
+		return errs.TxLocked
+	}
+	if err := s.Repo.SoftDeleteWalletTx(walletID, txID); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "tx.delete", "transaction", &txID, map[string]any{"household": hid})
+	}
+	return nil
+}
+
+func (s *HouseholdService) wallet(uid, hid, walletID int64, min string) (*ports.Wallet, error) {
+	if _, err := s.require(uid, hid, min); err != nil {
+		return nil, err
+	}
+	return s.Repo.Wallet(hid, walletID)
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+)
+
+type fakeHouseRepo struct {
+	roles   map[int64]string // userID -> rol (tek hane, id=1)
+	emails  map[int64]string
+	invites map[string]*ports.HouseholdInvite
+	wallets map[int64]int64 // walletID -> sahibi (hane cüzdanı için 0)
+}
+
+func newFakeHouseRepo() *fakeHouseRepo {
+	return &fakeHouseRepo{
+		roles:   map[int64]string{1: ports.RoleOwner},
+		emails:  map[int64]string{1: "a@x.io", 2: "b@x.io", 3: "c@x.io"},
+		invites: map[string]*ports.HouseholdInvite{},
+		wallets: map[int64]int64{10: 0, 20: 2},
+	}
+}
+
+func (r *fakeHouseRepo) Create(owner int64, h *ports.Household) error {
+	h.ID, h.Role = 1, ports.RoleOwner
+	r.roles[owner] = ports.RoleOwner
+	return nil
+}
+func (r *fakeHouseRepo) ListForUser(int64) ([]ports.Household, error) { return nil, nil }
+func (r *fakeHouseRepo) Get(uid, id int64) (*ports.Household, error) {
+	if _, ok := r.roles[uid]; !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &ports.Household{ID: id, Role: r.roles[uid]}, nil
+}
+func (r *fakeHouseRepo) Delete(int64) error { return nil }
+func (r *fakeHouseRepo) Members(int64) ([]ports.HouseholdMember, error) {
+	var out []ports.HouseholdMember
+	for uid, role := range r.roles {
+		out = append(out, ports.HouseholdMember{HouseholdID: 1, UserID: uid, Role: role})
+	}
+	return out, nil
+}
+func (r *fakeHouseRepo) Role(_, uid int64) (string, error) {
+	role, ok := r.roles[uid]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return role, nil
+}
+func (r *fakeHouseRepo) SetRole(_, uid int64, role string) error {
+	r.roles[uid] = role
+	return nil
+}
+func (r *fakeHouseRepo) RemoveMember(_, uid int64) error {
+	delete(r.roles, uid)
+	return nil
+}
+func (r *fakeHouseRepo) CreateInvite(inv *ports.HouseholdInvite, hash string) error {
+	inv.ID = int64(len(r.invites) + 1)
+	r.invites[hash] = inv
+	return nil
+}
+func (r *fakeHouseRepo) InviteByHash(hash string) (*ports.HouseholdInvite, error) {
+	inv, ok := r.invites[hash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	c := *inv
+	return &c, nil
+}
+func (r *fakeHouseRepo) AcceptInvite(inv *ports.HouseholdInvite, uid int64) error {
+	for _, v := range r.invites {
+		if v.ID == inv.ID {
+			now := time.Now()
+			v.AcceptedAt = &now
+		}
+	}
+	r.roles[uid] = inv.Role
+	return nil
+}
+func (r *fakeHouseRepo) UserEmail(uid int64) (string, error) { return r.emails[uid], nil }
+func (r *fakeHouseRepo) Wallets(int64) ([]ports.Wallet, error) {
+	return []ports.Wallet{{ID: 10, Currency: "TRY"}}, nil
+}
+func (r *fakeHouseRepo) Wallet(_, wid int64) (*ports.Wallet, error) {
+	if owner, ok := r.wallets[wid]; !ok || owner != 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &ports.Wallet{ID: wid, Currency: "TRY"}, nil
+}
+func (r *fakeHouseRepo) WalletBalances(int64) (map[int64]float64, error) {
+	return map[int64]float64{10: 25}, nil
+}
+func (r *fakeHouseRepo) WalletRole(uid, wid int64) (string, error) {
+	owner, ok := r.wallets[wid]
+	switch {
+	case !ok:
+		return "", sql.ErrNoRows
+	case owner == uid:
+		return ports.RoleOwner, nil
+	case owner == 0:
+		return r.Role(1, uid)
+	}
+	return "", sql.ErrNoRows
+}
+func (r *fakeHouseRepo) WalletTransactions(int64, int, int) ([]ports.Transaction, int, error) {
+	return nil, 0, nil
+}
+func (r *fakeHouseRepo) WalletTransaction(wid, id int64) (*ports.Transaction, error) {
+	return &ports.Transaction{ID: id, WalletID: wid}, nil
+}
+func (r *fakeHouseRepo) SoftDeleteWalletTx(int64, int64) error { return nil }
+
+func newHouseSvc() (*HouseholdService, *fakeHouseRepo, *fakeTxRepo) {
+	hr := newFakeHouseRepo()
+	tr := &fakeTxRepo{}
+	return &HouseholdService{Repo: hr, Tx: tr}, hr, tr
+}
+
+func TestHousehold_InviteAndAccept(t *testing.T) {
+	s, hr, _ := newHouseSvc()
+	if _, err := s.Invite(2, 1, "b@x.io", ports.RoleEditor); err != errs.NotFound {
+		t.Fatalf("non-member invite: want not_found, got %v", err)
+	}
+	inv, err := s.Invite(1, 1, " B@x.io ", ports.RoleEditor)
+	if err != nil || inv.Token == "" || inv.Email != "b@x.io" {
+		t.Fatalf("invite failed: %v %+v", err, inv)
+	}
+	if _, ok := hr.invites[security.SHA256Hex(inv.Token)]; !ok {
+		t.Fatal("token must be stored hashed")
+	}
+	if _, err := s.Accept(3, inv.Token); err != errs.Forbidden {
+		t.Fatalf("wrong user accept: want forbidden, got %v", err)
+	}
+	if _, err := s.Accept(2, inv.Token); err != nil {
+		t.Fatal(err)
+	}
+	if hr.roles[2] != ports.RoleEditor {
+		t.Fatalf("member role = %q", hr.roles[2])
+	}
+	if _, err := s.Accept(2, inv.Token); !failedWith(err, "invite_used") {
+		t.Fatalf("want invite_used, got %v", err)
+	}
+}
+
+func TestHousehold_InviteExpired(t *testing.T) {
+	s, _, _ := newHouseSvc()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Now = func() time.Time { return now }
+	inv, _ := s.Invite(1, 1, "b@x.io", ports.RoleViewer)
+	now = now.Add(8 * 24 * time.Hour)
+	if _, err := s.Accept(2, inv.Token); !failedWith(err, "invite_expired") {
+		t.Fatalf("want invite_expired, got %v", err)
+	}
+}
+
+func TestHousehold_RoleChecks(t *testing.T) {
+	s, hr, tr := newHouseSvc()
+	hr.roles[2] = ports.RoleViewer
+	hr.roles[3] = ports.RoleEditor
+
+	if _, _, err := s.WalletTransactions(2, 1, 10, 1, 20); err != nil {
+		t.Fatalf("viewer should read: %v", err)
+	}
+	tx := ports.Transaction{CategoryID: 1, Type: "expense", Amount: 5}
+	if err := s.CreateWalletTx(2, 1, 10, &tx); err != errs.Forbidden {
+		t.Fatalf("viewer write: want forbidden, got %v", err)
+	}
+	if err := s.CreateWalletTx(3, 1, 10, &tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.WalletID != 10 || tx.Currency != "TRY" || tr.created != &tx {
+		t.Fatalf("bad shared tx: %+v", tx)
+	}
+	if err := s.SetRole(3, 1, 2, ports.RoleEditor); err != errs.Forbidden {
+		t.Fatalf("editor set role: want forbidden, got %v", err)
+	}
+	if err := s.RemoveMember(2, 1, 2); err != nil {
+		t.Fatalf("member should be able to leave: %v", err)
+	}
+	if err := s.RemoveMember(1, 1, 1); !failedWith(err, "last_owner") {
+		t.Fatalf("want last_owner, got %v", err)
+	}
+}
+
+func TestTx_Create_WalletAccess(t *testing.T) {
+	hs, hr, _ := newHouseSvc()
+	hr.roles[2] = ports.RoleViewer
+	tr := &fakeTxRepo{}
+	s := &TxService{Repo: tr, Access: hs}
+
+	if err := s.Create(2, &ports.Transaction{WalletID: 20}); err != nil {
+		t.Fatalf("own wallet: %v", err)
+	}
+	if err := s.Create(2, &ports.Transaction{WalletID: 10}); err != errs.Forbidden {
+		t.Fatalf("viewer on shared wallet: want forbidden, got %v", err)
+	}
+	if err := s.Create(3, &ports.Transaction{WalletID: 20}); err != errs.NotFound {
+		t.Fatalf("foreign wallet: want not_found, got %v", err)
+	}
+	if err := s.UpsertBatch(2, []ports.Transaction{{WalletID: 20}, {WalletID: 10}}); err != errs.Forbidden {
+		t.Fatalf("batch with shared wallet: want forbidden, got %v", err)
+	}
+}
+
+func TestWallet_HouseholdRoles(t *testing.T) {
+	hr := newFakeHouseRepo()
+	hr.roles[2], hr.roles[3] = ports.RoleEditor, ports.RoleViewer
+	wr := &fakeWalletRepo{rows: []ports.Wallet{{ID: 10, Name: "Ev", Currency: "TRY", Kind: ports.WalletCash}}}
+	ws := &WalletService{Repo: wr, Roles: hr}
+
+	upd := func(uid int64) error {
+		return ws.Update(uid, &ports.Wallet{ID: 10, Name: "Ortak", Currency: "TRY"})
+	}
+	if err := upd(3); err != errs.Forbidden {
+		t.Fatalf("viewer update: want forbidden, got %v", err)
+	}
+	if err := upd(2); err != nil {
+		t.Fatalf("editor update: %v", err)
+	}
+	if err := upd(4); err != errs.NotFound {
+		t.Fatalf("non-member: want not_found, got %v", err)
+	}
+	if err := ws.Delete(2, 10); err != errs.Forbidden {
+		t.Fatalf("editor delete: want forbidden, got %v", err)
+	}
+	if err := ws.Delete(1, 10); err != nil || wr.deleted != 10 {
+		t.Fatalf("owner delete: %v", err)
+	}
+}
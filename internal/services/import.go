@@ -0,0 +1,276 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// importMaxEntry arşivdeki tek dosyanın açılmış hâli için üst sınır (zip bombası koruması).
+const importMaxEntry = 256 << 20
+
+type ImportService struct {
+	Repo  ports.ImportRepo
+	Audit *AuditService
+	// NetWorth geriye dönük eklenen işlemler için net değer görüntülerini bayat işaretler.
+	NetWorth StaleMarker
+}
+
+// Import dışa aktarım arşivini kullanıcı hesabına yeniden kurar. Kimlikler yeniden eşlenir;
+// silinmiş işlemler atlanır, mutabakat ve taksit bağları taşınmaz (reconciled → cleared).
+func (s *ImportService) Import(uid int64, archive []byte, conflict string, dryRun bool) (*ports.ImportResult, error) {
+	if conflict == "" {
+		conflict = ports.ImportMerge
+	}
+	if conflict != ports.ImportMerge && conflict != ports.ImportRename {
+		return nil, errs.ValidationFailed("bad_conflict_mode")
+	}
+	a, skipped, err := ReadImportArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Repo.Import(uid, a, conflict, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	res.Transactions.Skipped += skipped
+	if !dryRun && res.Transactions.Created > 0 && s.NetWorth != nil {
+		since := a.Transactions[0].OccurredAt
+		for _, t := range a.Transactions[1:] {
+			if t.OccurredAt.Before(since) {
+				since = t.OccurredAt
+			}
+		}
+		_ = s.NetWorth.MarkStale(uid, since)
+	}
+	if s.Audit != nil && !dryRun {
+		s.Audit.Log(uid, "account.import", "user", &uid, map[string]any{
+			"version": a.Version, "conflict": conflict,
+			"wallets": res.Wallets.Created + res.Wallets.Renamed, "categories": res.Categories.Created + res.Categories.Renamed,
+			"transactions": res.Transactions.Created,
+		})
+	}
+	return &res, nil
+}
+
+// ReadImportArchive arşivi açar, manifest sürümünü ve SHA-256 özetlerini doğrular. Sürüm alanı
+// olmayan (sürümlemeden önceki) arşivler 1 kabul edilir. İkinci değer atlanan silinmiş işlem sayısı.
+func ReadImportArchive(b []byte) (*ports.ImportArchive, int, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, 0, errs.ValidationFailed("bad_archive")
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	raw, err := readZipEntry(files["manifest.json"])
+	if err != nil {
+		return nil, 0, errs.ValidationFailed("archive_manifest_missing")
+	}
+	var man ExportManifest
+	if err := json.Unmarshal(raw, &man); err != nil {
+		return nil, 0, errs.ValidationFailed("bad_archive_manifest")
+	}
+	if man.Version == 0 {
+		man.Version = 1
+	}
+	if man.Version > ExportSchemaVersion {
+		return nil, 0, errs.ValidationFailed("unsupported_archive_version")
+	}
+	sums := map[string]string{}
+	for _, mf := range man.Files {
+		sums[mf.Name] = mf.SHA256
+	}
+	load := func(name string, v any) error {
+		raw, err := readZipEntry(files[name])
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(raw)
+		if want, ok := sums[name]; !ok || want != hex.EncodeToString(sum[:]) {
+			return errs.ValidationFailed("archive_checksum_mismatch")
+		}
+		if err := json.Unmarshal(raw, v); err != nil {
+			return errs.ValidationFailed("bad_archive_" + strings.TrimSuffix(name, ".json"))
+		}
+		return nil
+	}
+
+	var (
+		ws []archWallet
+		cs []archCategory
+		ts []archTx
+	)
+	if err := load("wallets.json", &ws); err != nil {
+		return nil, 0, err
+	}
+	if err := load("categories.json", &cs); err != nil {
+		return nil, 0, err
+	}
+	if err := load("transactions.json", &ts); err != nil {
+		return nil, 0, err
+	}
+
+	a := &ports.ImportArchive{Version: man.Version}
+	for _, x := range ws {
+		w := ports.Wallet{
+			ID: x.ID, Name: strings.TrimSpace(x.Name), Currency: strings.ToUpper(x.Currency), Kind: x.Kind,
+			CreditLimit: x.CreditLimit.ptr(), StatementDay: x.StatementDay, DueDay: x.DueDay,
+			Institution: x.Institution, AccountMask: x.AccountMask, ArchivedAt: x.ArchivedAt,
+		}
+		if w.Name == "" || len([]rune(w.Name)) > 80 || len(w.Currency) != 3 {
+			return nil, 0, errs.ValidationFailed("bad_archive_wallet")
+		}
+		if err := normalizeWallet(&w); err != nil {
+			return nil, 0, err
+		}
+		a.Wallets = append(a.Wallets, w)
+	}
+	for _, x := range cs {
+		c := ports.Category{
+			ID: x.ID, ParentID: x.ParentID, Name: strings.TrimSpace(x.Name), Type: x.Type,
+			SortOrder: x.SortOrder, Icon: x.Icon, Color: x.Color, ArchivedAt: x.ArchivedAt,
+		}
+		if c.Name == "" || len([]rune(c.Name)) > 80 || (c.Type != "income" && c.Type != "expense") {
+			return nil, 0, errs.ValidationFailed("bad_archive_category")
+		}
+		a.Categories = append(a.Categories, c)
+	}
+	var skipped int
+	for _, x := range ts {
+		if x.DeletedAt != nil {
+			skipped++
+			continue
+		}
+		deductible := bool(x.Deductible)
+		t := ports.Transaction{
+			ID: x.ID, WalletID: x.WalletID, CategoryID: x.CategoryID, Type: x.Type,
+			Amount: float64(x.Amount), Currency: strings.ToUpper(x.Currency), Note: x.Note, OccurredAt: x.OccurredAt,
+			Status: x.Status, TaxRate: x.TaxRate.ptr(), TaxAmount: x.TaxAmount.ptr(), Deductible: &deductible,
+		}
+		switch t.Status {
+		case "":
+			t.Status = ports.TxPending
+		case ports.TxReconciled:
+			t.Status = ports.TxCleared
+		case ports.TxScheduled, ports.TxPending, ports.TxCleared:
+		default:
+			return nil, 0, errs.ValidationFailed("bad_archive_transaction")
+		}
+		if (t.Type != "income" && t.Type != "expense") || t.Amount <= 0 || len(t.Currency) != 3 || t.OccurredAt.IsZero() {
+			return nil, 0, errs.ValidationFailed("bad_archive_transaction")
+		}
+		if err := checkTax(&t); err != nil {
+			return nil, 0, err
+		}
+		a.Transactions = append(a.Transactions, t)
+	}
+	return a, skipped, nil
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	if f == nil {
+		return nil, errs.ValidationFailed("archive_incomplete")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errs.ValidationFailed("bad_archive")
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(io.LimitReader(rc, importMaxEntry+1))
+	if err != nil {
+		return nil, errs.ValidationFailed("bad_archive")
+	}
+	if len(b) > importMaxEntry {
+		return nil, errs.ValidationFailed("archive_too_large")
+	}
+	return b, nil
+}
+
+/* ---- arşiv satırları: exportValue'nun yazdığı biçim ---- */
+
+type archWallet struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Currency     string     `json:"currency"`
+	Kind         string     `json:"kind"`
+	CreditLimit  *archNum   `json:"credit_limit"`
+	StatementDay *int       `json:"statement_day"`
+	DueDay       *int       `json:"due_day"`
+	Institution  *string    `json:"institution"`
+	AccountMask  *string    `json:"account_mask"`
+	ArchivedAt   *time.Time `json:"archived_at"`
+}
+
+type archCategory struct {
+	ID         int64      `json:"id"`
+	ParentID   *int64     `json:"parent_id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	SortOrder  int        `json:"sort_order"`
+	Icon       *string    `json:"icon"`
+	Color      *string    `json:"color"`
+	ArchivedAt *time.Time `json:"archived_at"`
+}
+
+type archTx struct {
+	ID         int64      `json:"id"`
+	WalletID   int64      `json:"wallet_id"`
+	CategoryID int64      `json:"category_id"`
+	Type       string     `json:"type"`
+	Amount     archNum    `json:"amount"`
+	Currency   string     `json:"currency"`
+	Note       *string    `json:"note"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+	Status     string     `json:"status"`
+	TaxRate    *archNum   `json:"tax_rate"`
+	TaxAmount  *archNum   `json:"tax_amount"`
+	Deductible archBool   `json:"deductible"`
+}
+
+// archNum DECIMAL sütunları sürücüden metin olarak gelir; hem sayı hem metin kabul edilir.
+type archNum float64
+
+func (n *archNum) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = archNum(f)
+	return nil
+}
+
+func (n *archNum) ptr() *float64 {
+	if n == nil {
+		return nil
+	}
+	f := float64(*n)
+	return &f
+}
+
+// archBool TINYINT sütunları 0/1 olarak yazılır.
+type archBool bool
+
+func (v *archBool) UnmarshalJSON(b []byte) error {
+	switch strings.Trim(string(b), `"`) {
+	case "1", "true":
+		*v = true
+	case "0", "false", "null":
+		*v = false
+	default:
+		return errs.ValidationFailed("bad_bool")
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// archiveExportRepo gerçek sütun adları ve sürücü tipleriyle (DECIMAL → []byte) satır verir.
+type archiveExportRepo struct{ fakeExportRepo }
+
+func (f *archiveExportRepo) Stream(_ int64, ds string, fn func([]string, []any) error) error {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	var (
+		cols []string
+		rows [][]any
+	)
+	switch ds {
+	case "wallets":
+		cols = []string{"id", "household_id", "name", "currency", "kind", "credit_limit", "statement_day", "due_day",
+			"institution", "account_mask", "archived_at", "updated_at"}
+		rows = [][]any{
+			{int64(10), nil, []byte("Nakit"), []byte("TRY"), []byte("cash"), nil, nil, nil, nil, nil, nil, at},
+			{int64(11), nil, []byte("Kart"), []byte("TRY"), []byte("credit_card"), []byte("5000.00"), int64(5), int64(15), nil, nil, nil, at},
+		}
+	case "categories":
+		cols = []string{"id", "user_id", "parent_id", "name", "type", "sort_order", "icon", "color", "archived_at", "merged_into_id", "updated_at"}
+		rows = [][]any{
+			{int64(21), int64(3), int64(20), []byte("Market"), []byte("expense"), int64(0), nil, nil, nil, nil, at},
+			{int64(20), int64(3), nil, []byte("Gıda"), []byte("expense"), int64(0), nil, nil, nil, nil, at},
+		}
+	case "transactions":
+		cols = []string{"id", "wallet_id", "category_id", "type", "amount", "currency", "note", "occurred_at", "updated_at",
+			"deleted_at", "status", "reconciliation_id", "installment_plan_id", "installment_no", "tax_rate", "tax_amount", "deductible"}
+		rows = [][]any{
+			{int64(1), int64(11), int64(21), []byte("expense"), []byte("120.00"), []byte("TRY"), nil, at, at,
+				nil, []byte("reconciled"), int64(4), nil, nil, []byte("20.00"), []byte("20.00"), int64(1)},
+			{int64(2), int64(10), int64(20), []byte("expense"), []byte("9.99"), []byte("TRY"), []byte("silindi"), at, at,
+				at, []byte("pending"), nil, nil, nil, nil, nil, int64(0)},
+		}
+	}
+	for _, r := range rows {
+		if err := fn(cols, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildTestArchive(t *testing.T) []byte {
+	t.Helper()
+	store := &memStore{files: map[string]*bytes.Buffer{}}
+	s := &ExportService{Repo: &archiveExportRepo{}, Store: store}
+	if err := s.Build(&ports.DataExport{ID: 1, UserID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	return store.files["export-3-1.zip"].Bytes()
+}
+
+// rewriteArchive arşivi dosya bazında değiştirerek yeniden yazar.
+func rewriteArchive(t *testing.T, b []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		w, _ := zw.Create(f.Name)
+		_, _ = w.Write(edit(f.Name, data))
+	}
+	_ = zw.Close()
+	return out.Bytes()
+}
+
+func TestImport_ReadsExportArchive(t *testing.T) {
+	a, skipped, err := ReadImportArchive(buildTestArchive(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Version != ExportSchemaVersion || len(a.Wallets) != 2 || len(a.Categories) != 2 || len(a.Transactions) != 1 || skipped != 1 {
+		t.Fatalf("unexpected archive: v=%d w=%d c=%d t=%d skipped=%d", a.Version, len(a.Wallets), len(a.Categories), len(a.Transactions), skipped)
+	}
+	if w := a.Wallets[1]; w.CreditLimit == nil || *w.CreditLimit != 5000 || w.StatementDay == nil || *w.StatementDay != 5 {
+		t.Fatalf("card wallet not decoded: %+v", w)
+	}
+	if c := a.Categories[0]; c.ParentID == nil || *c.ParentID != 20 {
+		t.Fatalf("parent not kept: %+v", c)
+	}
+	tx := a.Transactions[0]
+	if tx.Amount != 120 || tx.Status != ports.TxCleared || tx.Deductible == nil || !*tx.Deductible || tx.TaxAmount == nil || *tx.TaxAmount != 20 {
+		t.Fatalf("transaction not decoded: %+v", tx)
+	}
+}
+
+func TestImport_RejectsTamperedOrNewerArchive(t *testing.T) {
+	b := buildTestArchive(t)
+
+	tampered := rewriteArchive(t, b, func(name string, data []byte) []byte {
+		if name == "wallets.json" {
+			return bytes.Replace(data, []byte("Nakit"), []byte("Nakid"), 1)
+		}
+		return data
+	})
+	if _, _, err := ReadImportArchive(tampered); !failedWith(err, "archive_checksum_mismatch") {
+		t.Fatalf("tampered: %v", err)
+	}
+
+	newer := rewriteArchive(t, b, func(name string, data []byte) []byte {
+		if name != "manifest.json" {
+			return data
+		}
+		var m map[string]any
+		_ = json.Unmarshal(data, &m)
+		m["version"] = ExportSchemaVersion + 1
+		out, _ := json.Marshal(m)
+		return out
+	})
+	if _, _, err := ReadImportArchive(newer); !failedWith(err, "unsupported_archive_version") {
+		t.Fatalf("newer: %v", err)
+	}
+
+	legacy := rewriteArchive(t, b, func(name string, data []byte) []byte {
+		if name != "manifest.json" {
+			return data
+		}
+		var m map[string]any
+		_ = json.Unmarshal(data, &m)
+		delete(m, "version")
+		out, _ := json.Marshal(m)
+		return out
+	})
+	if a, _, err := ReadImportArchive(legacy); err != nil || a.Version != 1 {
+		t.Fatalf("unversioned archive should read as v1: %v", err)
+	}
+}
+
+type fakeImportRepo struct {
+	got    *ports.ImportArchive
+	dryRun bool
+}
+
+func (f *fakeImportRepo) Import(_ int64, a *ports.ImportArchive, conflict string, dryRun bool) (ports.ImportResult, error) {
+	f.got, f.dryRun = a, dryRun
+	return ports.ImportResult{DryRun: dryRun, SchemaVersion: a.Version, Conflict: conflict,
+		Transactions: ports.ImportCounts{Created: len(a.Transactions)}}, nil
+}
+
+func TestImport_ServiceModes(t *testing.T) {
+	repo := &fakeImportRepo{}
+	stale := &staleRec{}
+	s := &ImportService{Repo: repo, NetWorth: stale}
+	b := buildTestArchive(t)
+
+	if _, err := s.Import(3, b, "overwrite", false); !failedWith(err, "bad_conflict_mode") {
+		t.Fatalf("bad mode: %v", err)
+	}
+	res, err := s.Import(3, b, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repo.dryRun || res.Conflict != ports.ImportMerge || res.Transactions.Created != 1 || res.Transactions.Skipped != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(stale.since) != 0 {
+		t.Fatalf("dry run must not touch net worth: %v", stale.since)
+	}
+	if _, err := s.Import(3, b, ports.ImportRename, false); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC); len(stale.since) != 1 || !stale.since[0].Equal(want) {
+		t.Fatalf("net worth not marked stale from earliest import: %v", stale.since)
+	}
+	if _, err := s.Import(3, []byte("not a zip"), "", false); !failedWith(err, "bad_archive") {
+		t.Fatalf("garbage: %v", err)
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const (
+	// insightMonths YoY karşılaştırması için geçen yılın aynı ayını da kapsayan pencere.
+	insightMonths = 13
+	// spikeWindow anomali için ortalama/sapma hesaplanan tamamlanmış ay sayısı.
+	spikeWindow    = 6
+	spikeMinMonths = 3
+	spikeSigma     = 2.0
+	spikeAlert     = 3.0
+
+	largeMinSamples = 20
+	largeSigma      = 3.0
+	largeAlert      = 5.0
+
+	// changeMinPct / changeMinShare MoM-YoY değişiminin raporlanması için oransal ve
+	// toplam harcamaya göre önemlilik eşikleri (para biriminden bağımsız).
+	changeMinPct   = 0.25
+	changeWarnPct  = 0.50
+	changeMinShare = 0.05
+
+	newMerchantMax = 5
+)
+
+type InsightService struct {
+	Repo ports.InsightRepo
+
+	Now func() time.Time
+}
+
+func (s *InsightService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *InsightService) List(uid int64) ([]ports.Insight, error) {
+	return s.Repo.List(uid)
+}
+
+func (s *InsightService) Dismiss(uid, id int64) error {
+	if id <= 0 {
+		return errs.ValidationFailed("bad_id")
+	}
+	return s.Repo.Dismiss(uid, id)
+}
+
+// Generate gideri değişen ya da bu ay henüz taranmamış kullanıcılar için bulguları
+// yeniden üretir; işlenen kullanıcı sayısını döner.
+func (s *InsightService) Generate() (int, error) {
+	ids, err := s.Repo.ChangedUsers(monthOf(s.now()))
+	if err != nil {
+		return 0, err
+	}
+	var (
+		n       int
+		lastErr error
+	)
+	for _, uid := range ids {
+		if err := s.GenerateUser(uid); err != nil {
+			lastErr = err
+			continue
+		}
+		n++
+	}
+	return n, lastErr
+}
+
+func (s *InsightService) GenerateUser(uid int64) error {
+	now := s.now()
+	txs, err := s.Repo.Expenses(uid, monthOf(now).AddDate(0, -insightMonths, 0))
+	if err != nil {
+		return err
+	}
+	return s.Repo.Replace(uid, ComputeInsights(txs, now), now)
+}
+
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthsAgo işlemin içinde bulunulan aya göre kaç ay önce olduğu (0 = bu ay).
+func monthsAgo(cur, t time.Time) int {
+	t = t.UTC()
+	return (cur.Year()-t.Year())*12 + int(cur.Month()) - int(t.Month())
+}
+
+func meanStd(xs []float64) (mean, std float64) {
+	if len(xs) == 0 {
+		return 0, 0
+	}
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		std += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(xs)))
+}
+
+// catCur bulguların gruplandığı (kategori, para birimi) çifti; farklı para birimlerindeki
+// tutarlar toplanmaz.
+type catCur struct {
+	cat int64
+	cur string
+}
+
+func (k catCur) String() string { return fmt.Sprintf("%d|%s", k.cat, k.cur) }
+
+// ComputeInsights son 13 ayın giderlerinden bulguları çıkarır: kategori anomalisi ve
+// alışılmadık büyük işlemler içinde bulunulan ay için, MoM/YoY değişimleri son
+// tamamlanmış ay için hesaplanır. Tüm hesaplar para birimi başına ayrı yapılır.
+func ComputeInsights(txs []ports.Transaction, now time.Time) []ports.Insight {
+	cur := monthOf(now)
+	byCat := map[catCur]map[int]float64{}
+	monthTotal := map[string]map[int]float64{}
+	var cats []catCur
+	for _, t := range txs {
+		if t.Type != "expense" {
+			continue
+		}
+		k := monthsAgo(cur, t.OccurredAt)
+		if k < 0 || k > insightMonths {
+			continue
+		}
+		key := catCur{t.CategoryID, strings.ToUpper(t.Currency)}
+		if _, ok := byCat[key]; !ok {
+			byCat[key] = map[int]float64{}
+			cats = append(cats, key)
+		}
+		if monthTotal[key.cur] == nil {
+			monthTotal[key.cur] = map[int]float64{}
+		}
+		byCat[key][k] += t.Amount
+		monthTotal[key.cur][k] += t.Amount
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		if cats[i].cat != cats[j].cat {
+			return cats[i].cat < cats[j].cat
+		}
+		return cats[i].cur < cats[j].cur
+	})
+
+	out := []ports.Insight{}
+	out = append(out, categorySpikes(cats, byCat, cur)...)
+	out = append(out, largeTransactions(txs, cur)...)
+	out = append(out, newMerchants(txs, cur)...)
+	out = append(out, categoryChanges(ports.InsightCategoryMoM, 2, cats, byCat, monthTotal, cur)...)
+	out = append(out, categoryChanges(ports.InsightCategoryYoY, 13, cats, byCat, monthTotal, cur)...)
+	return out
+}
+
+func categorySpikes(cats []catCur, byCat map[catCur]map[int]float64, cur time.Time) []ports.Insight {
+	var out []ports.Insight
+	for _, key := range cats {
+		m := byCat[key]
+		hist := make([]float64, 0, spikeWindow)
+		var active int
+		for k := 1; k <= spikeWindow; k++ {
+			hist = append(hist, m[k])
+			if m[k] > 0 {
+				active++
+			}
+		}
+		if active < spikeMinMonths {
+			continue
+		}
+		mean, std := meanStd(hist)
+		total := m[0]
+		if std == 0 || total <= mean+spikeSigma*std {
+			continue
+		}
+		z := (total - mean) / std
+		sev := ports.SeverityWarning
+		if z > spikeAlert {
+			sev = ports.SeverityAlert
+		}
+		id := key.cat
+		out = append(out, ports.Insight{
+			Kind: ports.InsightCategorySpike, Severity: sev,
+			Fingerprint: fmt.Sprintf("%s:%s:%s", ports.InsightCategorySpike, key, cur.Format("2006-01")),
+			Title:       "Category spending is unusually high this month",
+			Period:      cur, CategoryID: &id, Currency: key.cur,
+			Data: map[string]float64{
+				"total": round2(total), "mean": round2(mean), "stddev": round2(std),
+				"zScore": round2(z), "months": spikeWindow,
+			},
+		})
+	}
+	return out
+}
+
+// largeTransactions bu ayın işlemlerini aynı para birimindeki son aylarla karşılaştırır.
+func largeTransactions(txs []ports.Transaction, cur time.Time) []ports.Insight {
+	hist := map[string][]float64{}
+	for _, t := range txs {
+		if k := monthsAgo(cur, t.OccurredAt); t.Type == "expense" && k >= 1 && k <= spikeWindow {
+			c := strings.ToUpper(t.Currency)
+			hist[c] = append(hist[c], t.Amount)
+		}
+	}
+	type stat struct{ mean, std float64 }
+	stats := map[string]stat{}
+	for c, xs := range hist {
+		if len(xs) < largeMinSamples {
+			continue
+		}
+		if mean, std := meanStd(xs); std > 0 {
+			stats[c] = stat{mean, std}
+		}
+	}
+	var out []ports.Insight
+	for _, t := range txs {
+		c := strings.ToUpper(t.Currency)
+		st, ok := stats[c]
+		if !ok || t.Type != "expense" || monthsAgo(cur, t.OccurredAt) != 0 {
+			continue
+		}
+		mean, std := st.mean, st.std
+		if t.Amount <= mean+largeSigma*std {
+			continue
+		}
+		z := (t.Amount - mean) / std
+		sev := ports.SeverityWarning
+		if z > largeAlert {
+			sev = ports.SeverityAlert
+		}
+		id, cid := t.ID, t.CategoryID
+		out = append(out, ports.Insight{
+			Kind: ports.InsightLargeTx, Severity: sev,
+			Fingerprint: fmt.Sprintf("%s:%d", ports.InsightLargeTx, t.ID),
+			Title:       "Unusually large transaction",
+			Period:      cur, CategoryID: &cid, TransactionID: &id, Currency: c,
+			Data: map[string]float64{
+				"amount": t.Amount, "mean": round2(mean), "stddev": round2(std), "zScore": round2(z),
+			},
+		})
+	}
+	return out
+}
+
+// newMerchants bu ay ilk kez görülen notları (subKey ile normalize) bulur. Geçmişi
+// iki aydan kısa kullanıcılarda her şey yeni olacağından atlanır.
+func newMerchants(txs []ports.Transaction, cur time.Time) []ports.Insight {
+	type agg struct {
+		name     string
+		currency string
+		total    float64
+		count    int
+	}
+	seen := map[string]bool{}
+	fresh := map[string]*agg{}
+	var keys []string
+	var history bool
+	for _, t := range txs {
+		if t.Type != "expense" {
+			continue
+		}
+		k := monthsAgo(cur, t.OccurredAt)
+		if k >= 2 {
+			history = true
+		}
+		if t.Note == nil {
+			continue
+		}
+		key := subKey(*t.Note)
+		if key == "" {
+			continue
+		}
+		key += "|" + strings.ToUpper(t.Currency)
+		if k > 0 {
+			seen[key] = true
+			continue
+		}
+		a, ok := fresh[key]
+		if !ok {
+			a = &agg{name: strings.TrimSpace(*t.Note), currency: strings.ToUpper(t.Currency)}
+			fresh[key] = a
+			keys = append(keys, key)
+		}
+		a.total += t.Amount
+		a.count++
+	}
+	if !history {
+		return nil
+	}
+	var out []ports.Insight
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		a := fresh[key]
+		out = append(out, ports.Insight{
+			Kind: ports.InsightNewMerchant, Severity: ports.SeverityInfo,
+			Fingerprint: fmt.Sprintf("%s:%s:%s", ports.InsightNewMerchant, key, cur.Format("2006-01")),
+			Title:       "New merchant: " + a.name,
+			Period:      cur,
+			Currency:    a.currency,
+			Data:        map[string]float64{"total": round2(a.total), "count": float64(a.count)},
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Data["total"] > out[j].Data["total"] })
+	if len(out) > newMerchantMax {
+		out = out[:newMerchantMax]
+	}
+	return out
+}
+
+// categoryChanges son tamamlanmış ayı (k=1) baseK ay önceki ayla karşılaştırır; önemlilik
+// eşiği aynı para birimindeki aylık toplama göre ölçülür.
+func categoryChanges(kind string, baseK int, cats []catCur, byCat map[catCur]map[int]float64, monthTotal map[string]map[int]float64, cur time.Time) []ports.Insight {
+	period := cur.AddDate(0, -1, 0)
+	var out []ports.Insight
+	for _, key := range cats {
+		total := monthTotal[key.cur][1]
+		last, base := byCat[key][1], byCat[key][baseK]
+		if total <= 0 || last <= 0 || base <= 0 {
+			continue
+		}
+		diff := last - base
+		pct := diff / base
+		if math.Abs(pct) < changeMinPct || math.Abs(diff) < changeMinShare*total {
+			continue
+		}
+		sev := ports.SeverityInfo
+		if pct >= changeWarnPct {
+			sev = ports.SeverityWarning
+		}
+		title := "Category spending up vs. last month"
+		if kind == ports.InsightCategoryYoY {
+			title = "Category spending up vs. same month last year"
+		}
+		if pct < 0 {
+			title = strings.Replace(title, " up ", " down ", 1)
+		}
+		id := key.cat
+		out = append(out, ports.Insight{
+			Kind: kind, Severity: sev,
+			Fingerprint: fmt.Sprintf("%s:%s:%s", kind, key, period.Format("2006-01")),
+			Title:       title,
+			Period:      period, CategoryID: &id, Currency: key.cur,
+			Data: map[string]float64{
+				"current": round2(last), "previous": round2(base),
+				"change": round2(diff), "changePct": round2(pct * 100),
+			},
+		})
+	}
+	return out
+}
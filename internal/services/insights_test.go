@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func spend(cat int64, note string, amount float64, at time.Time) ports.Transaction {
+	return ports.Transaction{Type: "expense", CategoryID: cat, Note: &note, Amount: amount, Currency: "TRY", OccurredAt: at}
+}
+
+func insightSample() []ports.Transaction {
+	var txs []ports.Transaction
+	groceries := map[time.Month]float64{4: 1000, 5: 1100, 6: 900, 7: 1000, 8: 1050, 9: 950}
+	for m := time.April; m <= time.September; m++ {
+		for w := 0; w < 4; w++ {
+			txs = append(txs, spend(1, "Market", groceries[m]/4, day(2026, m, 3+7*w)))
+		}
+	}
+	txs = append(txs,
+		spend(2, "Akaryakıt", 300, day(2025, 9, 10)),
+		spend(2, "Akaryakıt", 300, day(2026, 8, 10)),
+		spend(2, "Akaryakıt", 600, day(2026, 9, 10)),
+		spend(1, "Market", 2000, day(2026, 10, 4)),
+		spend(3, "Yeni Kafe", 80, day(2026, 10, 12)),
+	)
+	txs[len(txs)-2].ID = 77
+	return txs
+}
+
+func TestComputeInsights(t *testing.T) {
+	got := map[string][]ports.Insight{}
+	for _, in := range ComputeInsights(insightSample(), day(2026, 10, 19)) {
+		got[in.Kind] = append(got[in.Kind], in)
+	}
+
+	sp := got[ports.InsightCategorySpike]
+	if len(sp) != 1 || *sp[0].CategoryID != 1 || sp[0].Severity != ports.SeverityAlert || sp[0].Data["mean"] != 1000 {
+		t.Fatalf("bad spike: %+v", sp)
+	}
+	lg := got[ports.InsightLargeTx]
+	if len(lg) != 1 || *lg[0].TransactionID != 77 || lg[0].Data["amount"] != 2000 {
+		t.Fatalf("bad large tx: %+v", lg)
+	}
+	nm := got[ports.InsightNewMerchant]
+	if len(nm) != 1 || nm[0].Title != "New merchant: Yeni Kafe" || nm[0].Severity != ports.SeverityInfo {
+		t.Fatalf("bad new merchant: %+v", nm)
+	}
+	mom := got[ports.InsightCategoryMoM]
+	if len(mom) != 1 || *mom[0].CategoryID != 2 || mom[0].Data["changePct"] != 100 || mom[0].Severity != ports.SeverityWarning {
+		t.Fatalf("bad mom: %+v", mom)
+	}
+	if !mom[0].Period.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("mom must describe last complete month: %s", mom[0].Period)
+	}
+	yoy := got[ports.InsightCategoryYoY]
+	if len(yoy) != 1 || *yoy[0].CategoryID != 2 || yoy[0].Data["previous"] != 300 {
+		t.Fatalf("bad yoy: %+v", yoy)
+	}
+}
+
+func TestComputeInsights_QuietWithoutHistory(t *testing.T) {
+	txs := []ports.Transaction{
+		spend(1, "Market", 500, day(2026, 10, 2)),
+		spend(1, "Kafe", 9000, day(2026, 10, 5)),
+	}
+	if got := ComputeInsights(txs, day(2026, 10, 19)); len(got) != 0 {
+		t.Fatalf("new user must get no insights: %+v", got)
+	}
+}
+
+func TestComputeInsights_CurrenciesNotMixed(t *testing.T) {
+	usd := spend(2, "Akaryakıt", 300, day(2026, 8, 20))
+	usd.Currency = "USD"
+	txs := append(insightSample(), usd)
+
+	var mom []ports.Insight
+	for _, in := range ComputeInsights(txs, day(2026, 10, 19)) {
+		if in.Kind == ports.InsightCategoryMoM {
+			mom = append(mom, in)
+		}
+	}
+	// USD gideri TRY ağustos toplamına eklenseydi değişim sıfırlanırdı.
+	if len(mom) != 1 || mom[0].Currency != "TRY" || mom[0].Data["previous"] != 300 {
+		t.Fatalf("bad mom: %+v", mom)
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const (
+	PlanActive = "active"
+	PlanClosed = "closed"
+
+	maxInstallments = 36
+)
+
+type InstallmentService struct {
+	Repo    ports.InstallmentRepo
+	Wallets ports.WalletRepo
+	Audit   *AuditService
+
+	Now func() time.Time
+}
+
+func (s *InstallmentService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// SplitInstallments tutarı kuruş hassasiyetinde n parçaya böler; yuvarlama
+// farkı remainder'a göre ilk ya da son taksite eklenir.
+func SplitInstallments(total float64, n int, remainder string) []float64 {
+	if n < 1 {
+		return nil
+	}
+	cents := int64(math.Round(total * 100))
+	base := cents / int64(n)
+	rest := cents - base*int64(n)
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = float64(base) / 100
+	}
+	idx := 0
+	if remainder == "last" {
+		idx = n - 1
+	}
+	out[idx] = float64(base+rest) / 100
+	return out
+}
+
+// installmentDate: ilk taksitten k ay sonrası, ay kısaysa son güne çekilir.
+func installmentDate(first time.Time, k int) time.Time {
+	y, m := addMonths(first.Year(), first.Month(), k)
+	return time.Date(y, m, clampDay(y, m, first.Day()),
+		first.Hour(), first.Minute(), first.Second(), 0, time.UTC)
+}
+
+func (s *InstallmentService) Create(uid int64, p *ports.InstallmentPlan) error {
+	if p.Count < 2 || p.Count > maxInstallments {
+		return errs.ValidationFailed("bad_installment_count")
+	}
+	if p.TotalAmount <= 0 || math.Round(p.TotalAmount*100) < float64(p.Count) {
+		return errs.ValidationFailed("bad_amount")
+	}
+	if p.Remainder == "" {
+		p.Remainder = "first"
+	}
+	if p.Remainder != "first" && p.Remainder != "last" {
+		return errs.ValidationFailed("bad_remainder")
+	}
+	w, err := s.Wallets.Get(uid, p.WalletID)
+	if err != nil {
+		return err
+	}
+	if w.Kind != ports.WalletCreditCard {
+		return errs.ValidationFailed("not_a_credit_card")
+	}
+	if p.Currency == "" {
+		p.Currency = w.Currency
+	}
+	p.FirstAt = p.FirstAt.UTC()
+	p.Status = PlanActive
+
+	items := s.schedule(p, p.FirstAt, 0, 1, SplitInstallments(p.TotalAmount, p.Count, p.Remainder))
+	if err := s.Repo.CreatePlan(uid, p, items); err != nil {
+		return err
+	}
+	p.Items = items
+	s.fill(p, items)
+	if s.Audit != nil {
+		s.Audit.Log(uid, "installment.create", "installment_plan", &p.ID, map[string]any{
+			"amount": p.TotalAmount, "currency": p.Currency, "count": p.Count,
+		})
+	}
+	return nil
+}
+
+func (s *InstallmentService) List(uid int64, status string) ([]ports.InstallmentPlan, error) {
+	plans, err := s.Repo.ListPlans(uid, status)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plans {
+		items, err := s.Repo.Items(uid, plans[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		s.fill(&plans[i], items)
+	}
+	return plans, nil
+}
+
+func (s *InstallmentService) Get(uid, id int64) (*ports.InstallmentPlan, error) {
+	p, err := s.Repo.GetPlan(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.Repo.Items(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Items = items
+	s.fill(p, items)
+	return p, nil
+}
+
+// CloseEarly kalan taksitleri bugüne tarihli tek bir işlemde toplar (erken kapama).
+func (s *InstallmentService) CloseEarly(uid, id int64) (*ports.InstallmentPlan, error) {
+	p, future, err := s.activePlan(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	var add []ports.Transaction
+	if len(future) > 0 {
+		var sum float64
+		for _, t := range future {
+			sum += t.Amount
+		}
+		no := p.Count - len(future) + 1
+		add = s.schedule(p, now, 0, no, []float64{round2(sum)})
+	}
+	p.Status = PlanClosed
+	p.ClosedAt = &now
+	if err := s.Repo.Replace(uid, p, txIDs(future), add); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "installment.close", "installment_plan", &p.ID, map[string]any{"remaining": len(future)})
+	}
+	return s.Get(uid, id)
+}
+
+// Restructure kalan tutarı bir sonraki taksit tarihinden başlayarak count yeni
+// taksite yeniden böler; ödenmiş taksitler olduğu gibi kalır.
+func (s *InstallmentService) Restructure(uid, id int64, count int) (*ports.InstallmentPlan, error) {
+	if count < 1 || count > maxInstallments {
+		return nil, errs.ValidationFailed("bad_installment_count")
+	}
+	p, future, err := s.activePlan(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(future) == 0 {
+		return nil, errs.ValidationFailed("nothing_to_restructure")
+	}
+	var sum float64
+	for _, t := range future {
+		sum += t.Amount
+	}
+	paid := p.Count - len(future)
+	// Tarihler planın ilk taksit gününe göre hesaplanır; future[0] kısa ayda kırpılmış olabilir (31 -> 28).
+	add := s.schedule(p, p.FirstAt, paid, paid+1, SplitInstallments(round2(sum), count, p.Remainder))
+	p.Count = paid + count
+	if err := s.Repo.Replace(uid, p, txIDs(future), add); err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "installment.restructure", "installment_plan", &p.ID, map[string]any{
+			"remaining": round2(sum), "count": count,
+		})
+	}
+	return s.Get(uid, id)
+}
+
+func (s *InstallmentService) activePlan(uid, id int64) (*ports.InstallmentPlan, []ports.Transaction, error) {
+	p, err := s.Repo.GetPlan(uid, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Status != PlanActive {
+		return nil, nil, errs.ValidationFailed("plan_closed")
+	}
+	items, err := s.Repo.Items(uid, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := s.now()
+	var future []ports.Transaction
+	for _, t := range items {
+		if t.OccurredAt.After(now) {
+			future = append(future, t)
+		}
+	}
+	return p, future, nil
+}
+
+// schedule amounts için first'ten k ay sonrasından itibaren aylık taksit işlemleri üretir; no ilk taksit numarasıdır.
+func (s *InstallmentService) schedule(p *ports.InstallmentPlan, first time.Time, k, no int, amounts []float64) []ports.Transaction {
+	now := s.now()
+	out := make([]ports.Transaction, len(amounts))
+	for i, a := range amounts {
+		n := no + i
+		at := installmentDate(first, k+i)
+		out[i] = ports.Transaction{
+			WalletID: p.WalletID, CategoryID: p.CategoryID, Type: "expense",
+			Amount: a, Currency: p.Currency, Note: p.Note,
+			OccurredAt: at, InstallmentNo: &n, Status: statusFor(at, now),
+		}
+	}
+	return out
+}
+
+func (s *InstallmentService) fill(p *ports.InstallmentPlan, items []ports.Transaction) {
+	now := s.now()
+	p.PaidCount, p.RemainingCount, p.RemainingAmount = 0, 0, 0
+	for _, t := range items {
+		if t.OccurredAt.After(now) {
+			p.RemainingCount++
+			p.RemainingAmount += t.Amount
+		} else {
+			p.PaidCount++
+		}
+	}
+	p.RemainingAmount = round2(p.RemainingAmount)
+}
+
+func txIDs(items []ports.Transaction) []int64 {
+	out := make([]int64, len(items))
+	for i, t := range items {
+		out[i] = t.ID
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeInstRepo struct {
+	plan    ports.InstallmentPlan
+	items   []ports.Transaction
+	removed []int64
+	nextID  int64
+}
+
+func (r *fakeInstRepo) ListPlans(int64, string) ([]ports.InstallmentPlan, error) {
+	return []ports.InstallmentPlan{r.plan}, nil
+}
+func (r *fakeInstRepo) GetPlan(int64, int64) (*ports.InstallmentPlan, error) {
+	p := r.plan
+	return &p, nil
+}
+func (r *fakeInstRepo) Items(int64, int64) ([]ports.Transaction, error) {
+	var out []ports.Transaction
+	for _, t := range r.items {
+		if t.DeletedAt == nil {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+func (r *fakeInstRepo) CreatePlan(_ int64, p *ports.InstallmentPlan, items []ports.Transaction) error {
+	p.ID = 1
+	r.plan = *p
+	r.add(items)
+	return nil
+}
+func (r *fakeInstRepo) Replace(_ int64, p *ports.InstallmentPlan, removeIDs []int64, add []ports.Transaction) error {
+	now := time.Now()
+	for _, id := range removeIDs {
+		for i := range r.items {
+			if r.items[i].ID == id {
+				r.items[i].DeletedAt = &now
+			}
+		}
+	}
+	r.removed = append(r.removed, removeIDs...)
+	r.plan = *p
+	r.add(add)
+	return nil
+}
+func (r *fakeInstRepo) add(items []ports.Transaction) {
+	for i := range items {
+		r.nextID++
+		items[i].ID = r.nextID
+		r.items = append(r.items, items[i])
+	}
+}
+
+func TestSplitInstallments_Remainder(t *testing.T) {
+	first := SplitInstallments(100, 3, "first")
+	if first[0] != 33.34 || first[1] != 33.33 || first[2] != 33.33 {
+		t.Fatalf("first remainder wrong: %v", first)
+	}
+	last := SplitInstallments(100, 3, "last")
+	if last[0] != 33.33 || last[2] != 33.34 {
+		t.Fatalf("last remainder wrong: %v", last)
+	}
+}
+
+func newInstSvc(now time.Time) (*InstallmentService, *fakeInstRepo) {
+	wr := &fakeWalletRepo{rows: []ports.Wallet{
+		{ID: 1, Kind: ports.WalletCreditCard, Currency: "TRY"},
+		{ID: 2, Kind: ports.WalletCash, Currency: "TRY"},
+	}}
+	ir := &fakeInstRepo{}
+	return &InstallmentService{Repo: ir, Wallets: wr, Now: func() time.Time { return now }}, ir
+}
+
+func TestInstallments_Create_Schedule(t *testing.T) {
+	s, ir := newInstSvc(time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC))
+
+	if err := s.Create(1, &ports.InstallmentPlan{WalletID: 2, CategoryID: 1, TotalAmount: 100, Count: 3}); err == nil {
+		t.Fatalf("expected not_a_credit_card")
+	}
+
+	p := ports.InstallmentPlan{WalletID: 1, CategoryID: 1, TotalAmount: 1000, Count: 3,
+		FirstAt: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)}
+	if err := s.Create(1, &p); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(ir.items) != 3 {
+		t.Fatalf("want 3 items, got %d", len(ir.items))
+	}
+	if d := ir.items[1].OccurredAt; d.Month() != time.February || d.Day() != 28 {
+		t.Fatalf("second installment should clamp to Feb 28: %v", d)
+	}
+	if p.PaidCount != 1 || p.RemainingCount != 2 || p.RemainingAmount != 666.66 {
+		t.Fatalf("remaining wrong: %+v", p)
+	}
+	if ir.items[0].Status != ports.TxPending || ir.items[1].Status != ports.TxScheduled || ir.items[2].Status != ports.TxScheduled {
+		t.Fatalf("future installments must be scheduled: %s %s %s", ir.items[0].Status, ir.items[1].Status, ir.items[2].Status)
+	}
+}
+
+func TestInstallments_RestructureKeepsFirstDay(t *testing.T) {
+	s, _ := newInstSvc(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC))
+	p := ports.InstallmentPlan{WalletID: 1, CategoryID: 1, TotalAmount: 900, Count: 3,
+		FirstAt: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)}
+	if err := s.Create(1, &p); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	got, err := s.Restructure(1, p.ID, 3)
+	if err != nil {
+		t.Fatalf("restructure err: %v", err)
+	}
+	want := []int{31, 28, 31, 30}
+	if len(got.Items) != len(want) {
+		t.Fatalf("want %d items, got %d", len(want), len(got.Items))
+	}
+	for i, it := range got.Items {
+		if it.OccurredAt.Day() != want[i] {
+			t.Fatalf("item %d drifted: %v", i, it.OccurredAt)
+		}
+	}
+}
+
+func TestInstallments_RestructureAndClose(t *testing.T) {
+	s, ir := newInstSvc(time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC))
+	p := ports.InstallmentPlan{WalletID: 1, CategoryID: 1, TotalAmount: 1200, Count: 4,
+		FirstAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}
+	if err := s.Create(1, &p); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	got, err := s.Restructure(1, p.ID, 6)
+	if err != nil {
+		t.Fatalf("restructure err: %v", err)
+	}
+	if got.Count != 7 || got.RemainingCount != 6 || got.RemainingAmount != 900 {
+		t.Fatalf("restructure wrong: %+v", got)
+	}
+	if len(ir.removed) != 3 {
+		t.Fatalf("future installments not replaced: %v", ir.removed)
+	}
+
+	closed, err := s.CloseEarly(1, p.ID)
+	if err != nil {
+		t.Fatalf("close err: %v", err)
+	}
+	if closed.Status != PlanClosed || closed.RemainingCount != 0 || len(closed.Items) != 2 {
+		t.Fatalf("close wrong: %+v", closed)
+	}
+	if closed.Items[1].Amount != 900 {
+		t.Fatalf("payoff amount wrong: %v", closed.Items[1].Amount)
+	}
+	if _, err := s.Restructure(1, p.ID, 2); err == nil {
+		t.Fatalf("closed plan must not be restructured")
+	}
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// PriceFetcher varlık fiyat sağlayıcısı; RatesFetcher ile aynı biçimde takılıp çıkarılabilir.
+type PriceFetcher interface {
+	Quote(symbol string) (price float64, currency string, date time.Time, err error)
+}
+
+var assetKinds = map[string]bool{"stock": true, "fund": true, "gold": true, "crypto": true, "other": true}
+
+const qtyEps = 1e-9
+
+type InvestmentService struct {
+	Repo   ports.InvestmentRepo
+	Quotes PriceFetcher
+	// Rates değerlemeyi istenen para birimine çevirir (RatesService).
+	Rates  RatesFetcher
+	Access WalletAccess
+	Audit  *AuditService
+
+	Now func() time.Time
+}
+
+type PortfolioQuery struct {
+	Currency string
+	Method   string
+	WalletID int64
+	From     time.Time
+	To       time.Time
+}
+
+// CostResult: Quantity eldeki miktar, Cost kalan maliyet, Realized gerçekleşen kâr/zarar
+// (hepsi varlığın para biriminde).
+type CostResult struct {
+	Quantity float64
+	Cost     float64
+	Realized float64
+}
+
+func (s *InvestmentService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// CostBasis tek bir varlığın zaman sıralı lotlarından maliyet ve gerçekleşen kârı hesaplar.
+// Alış komisyonu maliyete eklenir, satış komisyonu gelirden düşülür.
+func CostBasis(lots []ports.Lot, method string) (CostResult, error) {
+	type open struct{ qty, unit float64 }
+	var (
+		queue []open
+		res   CostResult
+	)
+	for _, l := range lots {
+		switch l.Side {
+		case ports.LotBuy:
+			cost := l.Quantity*l.Price + l.Fee
+			res.Quantity += l.Quantity
+			res.Cost += cost
+			queue = append(queue, open{l.Quantity, cost / l.Quantity})
+		case ports.LotSell:
+			if l.Quantity > res.Quantity+qtyEps {
+				return res, errs.ValidationFailed("insufficient_quantity")
+			}
+			proceeds := l.Quantity*l.Price - l.Fee
+			var used float64
+			if method == ports.CostAverage {
+				used = res.Cost / res.Quantity * l.Quantity
+			} else {
+				left := l.Quantity
+				for left > qtyEps && len(queue) > 0 {
+					take := math.Min(left, queue[0].qty)
+					used += take * queue[0].unit
+					queue[0].qty -= take
+					left -= take
+					if queue[0].qty <= qtyEps {
+						queue = queue[1:]
+					}
+				}
+			}
+			res.Realized += proceeds - used
+			res.Cost -= used
+			res.Quantity -= l.Quantity
+			if res.Quantity <= qtyEps {
+				res.Quantity, res.Cost, queue = 0, 0, nil
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *InvestmentService) ListAssets(uid int64) ([]ports.Asset, error) {
+	return s.Repo.ListAssets(uid)
+}
+
+func (s *InvestmentService) CreateAsset(uid int64, a *ports.Asset) error {
+	a.Symbol = strings.ToUpper(strings.TrimSpace(a.Symbol))
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Symbol == "" || a.Name == "" {
+		return errs.ValidationFailed("symbol_and_name_required")
+	}
+	if !assetKinds[a.Kind] {
+		return errs.ValidationFailed("bad_asset_kind")
+	}
+	if a.Unit == "" {
+		a.Unit = "unit"
+		if a.Kind == "gold" {
+			a.Unit = "gram"
+		}
+	}
+	if err := s.Repo.CreateAsset(uid, a); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "asset.create", "asset", &a.ID, map[string]any{"symbol": a.Symbol})
+	}
+	return nil
+}
+
+func (s *InvestmentService) ListLots(uid, walletID int64) ([]ports.Lot, error) {
+	return s.Repo.ListLots(uid, walletID)
+}
+
+func (s *InvestmentService) AddLot(uid int64, l *ports.Lot) error {
+	if l.Side != ports.LotBuy && l.Side != ports.LotSell {
+		return errs.ValidationFailed("bad_side")
+	}
+	if l.Quantity <= 0 || l.Price < 0 || l.Fee < 0 {
+		return errs.ValidationFailed("bad_amount")
+	}
+	if _, err := s.Repo.GetAsset(uid, l.AssetID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ValidationFailed("asset_not_found")
+		}
+		return err
+	}
+	if s.Access != nil {
+		if err := s.Access.CanWrite(uid, l.WalletID); err != nil {
+			return err
+		}
+	}
+	if l.TradedAt.IsZero() {
+		l.TradedAt = s.now()
+	}
+	l.TradedAt = l.TradedAt.UTC()
+	if l.Side == ports.LotSell {
+		// Satış, cüzdandaki miktarı hiçbir anda eksiye düşürmemeli (geçmiş tarihli satışlar dahil).
+		lots, err := s.Repo.ListLots(uid, l.WalletID)
+		if err != nil {
+			return err
+		}
+		asset := append(lotsOf(lots, l.AssetID), *l)
+		sort.SliceStable(asset, func(i, j int) bool { return asset[i].TradedAt.Before(asset[j].TradedAt) })
+		if _, err := CostBasis(asset, ports.CostFIFO); err != nil {
+			return err
+		}
+	}
+	if err := s.Repo.AddLot(uid, l); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "lot.create", "asset", &l.AssetID, map[string]any{
+			"side": l.Side, "quantity": l.Quantity, "price": l.Price,
+		})
+	}
+	return nil
+}
+
+func (s *InvestmentService) SetPrice(uid, assetID int64, date time.Time, price float64) error {
+	if price <= 0 {
+		return errs.ValidationFailed("bad_price")
+	}
+	if _, err := s.Repo.GetAsset(uid, assetID); err != nil {
+		return err
+	}
+	return s.Repo.UpsertPrice(&ports.AssetPrice{AssetID: assetID, Date: date.UTC(), Price: price, Source: ports.PriceManual})
+}
+
+func (s *InvestmentService) Prices(uid, assetID int64, from, to time.Time) ([]ports.AssetPrice, error) {
+	return s.Repo.Prices(uid, assetID, from, to)
+}
+
+// RefreshPrices sağlayıcıdan her sembol için bir kez fiyat çeker; kote para birimi
+// varlığınkiyle uyuşmayan kayıtlar atlanır. Güncellenen kayıt sayısını döner.
+func (s *InvestmentService) RefreshPrices() (int, error) {
+	if s.Quotes == nil {
+		return 0, nil
+	}
+	assets, err := s.Repo.AssetsForRefresh()
+	if err != nil {
+		return 0, err
+	}
+	type quote struct {
+		price float64
+		ccy   string
+		date  time.Time
+		err   error
+	}
+	quotes := map[string]quote{}
+	var (
+		n       int
+		lastErr error
+	)
+	for _, a := range assets {
+		q, ok := quotes[a.Symbol]
+		if !ok {
+			q.price, q.ccy, q.date, q.err = s.Quotes.Quote(a.Symbol)
+			quotes[a.Symbol] = q
+		}
+		if q.err != nil {
+			lastErr = q.err
+			continue
+		}
+		if !strings.EqualFold(q.ccy, a.Currency) || q.price <= 0 {
+			continue
+		}
+		if q.date.IsZero() {
+			q.date = s.now()
+		}
+		if err := s.Repo.UpsertPrice(&ports.AssetPrice{AssetID: a.ID, Date: q.date, Price: q.price, Source: ports.PriceProvider}); err != nil {
+			lastErr = err
+			continue
+		}
+		n++
+	}
+	return n, lastErr
+}
+
+// Portfolio pozisyonları q.To itibarıyla değerler. Kur çevrimi güncel kurla yapılır;
+// dönem başı değeri de aynı kurla hesaplanır, böylece getiri kur etkisinden arınır.
+func (s *InvestmentService) Portfolio(uid int64, q PortfolioQuery) (*ports.Portfolio, error) {
+	if q.Method == "" {
+		q.Method = ports.CostFIFO
+	}
+	if q.Method != ports.CostFIFO && q.Method != ports.CostAverage {
+		return nil, errs.ValidationFailed("bad_cost_method")
+	}
+	if q.To.IsZero() {
+		q.To = s.now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.AddDate(0, 0, -30)
+	}
+	if !q.From.Before(q.To) {
+		return nil, errs.ValidationFailed("bad_range")
+	}
+	q.Currency = strings.ToUpper(q.Currency)
+	if q.Currency == "" {
+		q.Currency = "TRY"
+	}
+
+	assets, err := s.Repo.ListAssets(uid)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := s.Repo.ListLots(uid, q.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	conv, err := s.converter(q.Currency, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ports.Portfolio{Currency: q.Currency, Method: q.Method, Positions: []ports.Position{}}
+	out.Period.From, out.Period.To = q.From, q.To
+	for _, a := range assets {
+		all := lotsOf(lots, a.ID)
+		if len(all) == 0 {
+			continue
+		}
+		end, err := CostBasis(lotsUntil(all, q.To), q.Method)
+		if err != nil {
+			return nil, err
+		}
+		start, err := CostBasis(lotsUntil(all, q.From), q.Method)
+		if err != nil {
+			return nil, err
+		}
+		rate := conv[a.Currency]
+
+		p := ports.Position{
+			AssetID: a.ID, Symbol: a.Symbol, Name: a.Name, Kind: a.Kind, Unit: a.Unit, Currency: a.Currency,
+			Quantity:  end.Quantity,
+			CostBasis: round2(end.Cost * rate),
+			Realized:  round2(end.Realized * rate),
+		}
+		if end.Quantity > 0 {
+			p.AvgCost = end.Cost / end.Quantity
+		}
+		endPx, err := s.priceAt(a.ID, q.To)
+		if err != nil {
+			return nil, err
+		}
+		p.MarketValue = p.CostBasis
+		if endPx != nil {
+			p.Price, p.PriceAt = &endPx.Price, &endPx.Date
+			p.MarketValue = round2(end.Quantity * endPx.Price * rate)
+		}
+		p.Unrealized = round2(p.MarketValue - p.CostBasis)
+
+		startValue := start.Cost * rate
+		if start.Quantity > 0 {
+			if px, err := s.priceAt(a.ID, q.From); err != nil {
+				return nil, err
+			} else if px != nil {
+				startValue = start.Quantity * px.Price * rate
+			}
+		}
+		for _, l := range all {
+			if l.TradedAt.After(q.From) && !l.TradedAt.After(q.To) {
+				if l.Side == ports.LotBuy {
+					out.Period.NetFlows += (l.Quantity*l.Price + l.Fee) * rate
+				} else {
+					out.Period.NetFlows -= (l.Quantity*l.Price - l.Fee) * rate
+				}
+			}
+		}
+		out.Period.StartValue += startValue
+
+		if p.Quantity == 0 && p.Realized == 0 {
+			continue
+		}
+		out.Positions = append(out.Positions, p)
+		out.TotalValue += p.MarketValue
+		out.TotalCost += p.CostBasis
+		out.Unrealized += p.Unrealized
+		out.Realized += p.Realized
+	}
+
+	for i := range out.Positions {
+		if out.TotalValue > 0 {
+			out.Positions[i].Weight = math.Round(out.Positions[i].MarketValue/out.TotalValue*10000) / 10000
+		}
+	}
+	out.TotalValue, out.TotalCost = round2(out.TotalValue), round2(out.TotalCost)
+	out.Unrealized, out.Realized = round2(out.Unrealized), round2(out.Realized)
+
+	per := &out.Period
+	per.StartValue, per.NetFlows, per.EndValue = round2(per.StartValue), round2(per.NetFlows), out.TotalValue
+	per.Gain = round2(per.EndValue - per.StartValue - per.NetFlows)
+	if base := per.StartValue + math.Max(per.NetFlows, 0); base > 0 {
+		per.Return = math.Round(per.Gain/base*10000) / 10000
+	}
+	return out, nil
+}
+
+// converter varlık para birimlerinden hedefe çarpanları döner (hedef bazlı kurlar: 1 hedef = r birim).
+func (s *InvestmentService) converter(target string, assets []ports.Asset) (map[string]float64, error) {
+	out := map[string]float64{target: 1}
+	var need bool
+	for _, a := range assets {
+		if _, ok := out[a.Currency]; !ok {
+			need = true
+		}
+	}
+	if !need {
+		return out, nil
+	}
+	if s.Rates == nil {
+		return nil, errs.ValidationFailed("rates_unavailable")
+	}
+	_, _, rates, err := s.Rates.Latest(target)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assets {
+		if _, ok := out[a.Currency]; ok {
+			continue
+		}
+		r := rates[a.Currency]
+		if r <= 0 {
+			return nil, errs.ValidationFailed("rate_unavailable_" + strings.ToLower(a.Currency))
+		}
+		out[a.Currency] = 1 / r
+	}
+	return out, nil
+}
+
+func (s *InvestmentService) priceAt(assetID int64, at time.Time) (*ports.AssetPrice, error) {
+	p, err := s.Repo.PriceAt(assetID, at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+func lotsOf(lots []ports.Lot, assetID int64) []ports.Lot {
+	var out []ports.Lot
+	for _, l := range lots {
+		if l.AssetID == assetID {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func lotsUntil(lots []ports.Lot, at time.Time) []ports.Lot {
+	var out []ports.Lot
+	for _, l := range lots {
+		if !l.TradedAt.After(at) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
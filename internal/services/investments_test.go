@@ -0,0 +1,124 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeInvestRepo struct {
+	assets []ports.Asset
+	lots   []ports.Lot
+	prices []ports.AssetPrice
+}
+
+func (f *fakeInvestRepo) ListAssets(userID int64) ([]ports.Asset, error) { return f.assets, nil }
+func (f *fakeInvestRepo) GetAsset(userID, id int64) (*ports.Asset, error) {
+	for i := range f.assets {
+		if f.assets[i].ID == id {
+			return &f.assets[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+func (f *fakeInvestRepo) CreateAsset(userID int64, a *ports.Asset) error {
+	a.ID = int64(len(f.assets) + 1)
+	f.assets = append(f.assets, *a)
+	return nil
+}
+func (f *fakeInvestRepo) AssetsForRefresh() ([]ports.Asset, error) { return f.assets, nil }
+func (f *fakeInvestRepo) ListLots(userID, walletID int64) ([]ports.Lot, error) {
+	return f.lots, nil
+}
+func (f *fakeInvestRepo) AddLot(userID int64, l *ports.Lot) error {
+	l.ID = int64(len(f.lots) + 1)
+	f.lots = append(f.lots, *l)
+	return nil
+}
+func (f *fakeInvestRepo) UpsertPrice(p *ports.AssetPrice) error {
+	f.prices = append(f.prices, *p)
+	return nil
+}
+func (f *fakeInvestRepo) Prices(userID, assetID int64, from, to time.Time) ([]ports.AssetPrice, error) {
+	return f.prices, nil
+}
+func (f *fakeInvestRepo) PriceAt(assetID int64, at time.Time) (*ports.AssetPrice, error) {
+	var best *ports.AssetPrice
+	for i, p := range f.prices {
+		if p.AssetID == assetID && !p.Date.After(at) && (best == nil || p.Date.After(best.Date)) {
+			best = &f.prices[i]
+		}
+	}
+	if best == nil {
+		return nil, sql.ErrNoRows
+	}
+	return best, nil
+}
+
+func sampleLots() []ports.Lot {
+	return []ports.Lot{
+		{AssetID: 1, Side: ports.LotBuy, Quantity: 10, Price: 100, TradedAt: day(2026, 1, 1)},
+		{AssetID: 1, Side: ports.LotBuy, Quantity: 10, Price: 200, TradedAt: day(2026, 2, 1)},
+		{AssetID: 1, Side: ports.LotSell, Quantity: 10, Price: 300, TradedAt: day(2026, 3, 1)},
+	}
+}
+
+func TestCostBasis_FIFOvsAverage(t *testing.T) {
+	fifo, err := CostBasis(sampleLots(), ports.CostFIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fifo.Quantity != 10 || fifo.Cost != 2000 || fifo.Realized != 2000 {
+		t.Fatalf("fifo: %+v", fifo)
+	}
+	avg, err := CostBasis(sampleLots(), ports.CostAverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avg.Quantity != 10 || avg.Cost != 1500 || avg.Realized != 1500 {
+		t.Fatalf("avg: %+v", avg)
+	}
+}
+
+func TestAddLot_SellMoreThanHeld(t *testing.T) {
+	repo := &fakeInvestRepo{assets: []ports.Asset{{ID: 1, Currency: "USD"}}, lots: sampleLots()}
+	s := &InvestmentService{Repo: repo}
+	l := ports.Lot{AssetID: 1, Side: ports.LotSell, Quantity: 11, Price: 300, TradedAt: day(2026, 4, 1)}
+	if err := s.AddLot(7, &l); !failedWith(err, "insufficient_quantity") {
+		t.Fatalf("want insufficient_quantity, got %v", err)
+	}
+	// Geçmiş tarihli satış: o anda elde yalnızca 10 adet var.
+	l = ports.Lot{AssetID: 1, Side: ports.LotSell, Quantity: 15, Price: 150, TradedAt: day(2026, 1, 15)}
+	if err := s.AddLot(7, &l); !failedWith(err, "insufficient_quantity") {
+		t.Fatalf("want insufficient_quantity for backdated sell, got %v", err)
+	}
+}
+
+func TestPortfolio_ValuesInTargetCurrency(t *testing.T) {
+	repo := &fakeInvestRepo{
+		assets: []ports.Asset{{ID: 1, Symbol: "AAPL", Kind: "stock", Currency: "USD"}},
+		lots:   sampleLots(),
+		prices: []ports.AssetPrice{{AssetID: 1, Date: day(2026, 3, 15), Price: 250}},
+	}
+	s := &InvestmentService{
+		Repo:  repo,
+		Rates: &fakeFetcher{base: "TRY", r: map[string]float64{"USD": 0.025}},
+		Now:   func() time.Time { return day(2026, 4, 1) },
+	}
+	p, err := s.Portfolio(7, PortfolioQuery{Currency: "try"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Currency != "TRY" || len(p.Positions) != 1 {
+		t.Fatalf("bad portfolio: %+v", p)
+	}
+	pos := p.Positions[0]
+	if pos.MarketValue != 100000 || pos.CostBasis != 80000 || pos.Unrealized != 20000 || pos.Realized != 80000 {
+		t.Fatalf("bad position: %+v", pos)
+	}
+	if pos.Weight != 1 || p.TotalValue != 100000 {
+		t.Fatalf("bad totals: %+v", p)
+	}
+}
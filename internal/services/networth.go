@@ -0,0 +1,336 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// maxBackfillDays tek seferde yeniden hesaplanabilecek en uzun geçmiş (~5 yıl).
+const maxBackfillDays = 5 * 366
+
+// StaleMarker geçmiş tarihli işlem değişikliklerini net değer job'una bildirir.
+type StaleMarker interface {
+	MarkStale(uid int64, since time.Time) error
+}
+
+// NetWorthService cüzdan bakiyelerinin günlük anlık görüntülerini kullanıcının ana para
+// birimine çevirerek saklar. Her çekilen kur fx_history'ye yazılır; geçmiş günler o günün
+// (yoksa önceki son günün) kuruyla, hiç kayıt yoksa güncel kurla çevrilir.
+type NetWorthService struct {
+	Repo  ports.NetWorthRepo
+	Rates RatesFetcher
+	Audit *AuditService
+
+	Now func() time.Time
+}
+
+func (s *NetWorthService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func dayOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MarkStale bugünden önceki bir günü etkileyen değişikliği işaretler; bugünün görüntüsü
+// zaten her çalıştırmada yenilenir.
+func (s *NetWorthService) MarkStale(uid int64, since time.Time) error {
+	d := dayOf(since)
+	if !d.Before(dayOf(s.now())) {
+		return nil
+	}
+	return s.Repo.MarkStale(uid, d)
+}
+
+func (s *NetWorthService) SetBaseCurrency(uid int64, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return errs.ValidationFailed("bad_currency")
+	}
+	if err := s.Repo.SetBaseCurrency(uid, currency); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "networth.currency", "user", &uid, map[string]any{"currency": currency})
+	}
+	// Eski görüntüler önceki para biriminde; hepsini yeniden hesapla.
+	first, err := s.Repo.FirstSnapshotDate(uid)
+	if err != nil || first == nil {
+		return err
+	}
+	_, err = s.Backfill(uid, *first)
+	return err
+}
+
+// Backfill from gününden bugüne kadar tüm günlerin görüntülerini yeniden üretir ve
+// yazılan gün sayısını döner.
+func (s *NetWorthService) Backfill(uid int64, from time.Time) (int, error) {
+	from, today := dayOf(from), dayOf(s.now())
+	if from.After(today) {
+		return 0, errs.ValidationFailed("bad_range")
+	}
+	if today.Sub(from) > maxBackfillDays*24*time.Hour {
+		return 0, errs.ValidationFailed("backfill_range_too_long")
+	}
+	base, err := s.Repo.BaseCurrency(uid)
+	if err != nil {
+		return 0, err
+	}
+	opening, err := s.Repo.BalancesBefore(uid, from)
+	if err != nil {
+		return 0, err
+	}
+	end := today.AddDate(0, 0, 1)
+	deltas, err := s.Repo.DailyDeltas(uid, from, end)
+	if err != nil {
+		return 0, err
+	}
+	conv, err := s.converter(base, opening)
+	if err != nil {
+		return 0, err
+	}
+	var quotes []string
+	for c := range conv {
+		if c != base {
+			quotes = append(quotes, c)
+		}
+	}
+	sort.Strings(quotes)
+	hist, err := s.Repo.RatesBetween(base, quotes, from, today)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := SnapshotDays(opening, deltas, from, today)
+	ApplyRates(rows, conv, hist)
+	for i := range rows {
+		rows[i].UserID = uid
+		rows[i].BaseCurrency = base
+	}
+	if err := s.Repo.ReplaceSnapshots(uid, from, today, rows); err != nil {
+		return 0, err
+	}
+	return int(today.Sub(from).Hours()/24) + 1, nil
+}
+
+// SnapshotDays açılış bakiyelerine günlük hareketleri ekleyerek [from, to] aralığındaki
+// her gün için cüzdan başına gün sonu bakiyesi üretir.
+func SnapshotDays(opening []ports.WalletBalance, deltas []ports.WalletDelta, from, to time.Time) []ports.NetWorthSnapshot {
+	bal := make(map[int64]float64, len(opening))
+	for _, w := range opening {
+		bal[w.WalletID] = w.Balance
+	}
+	byDay := map[time.Time][]ports.WalletDelta{}
+	for _, d := range deltas {
+		k := dayOf(d.Day)
+		byDay[k] = append(byDay[k], d)
+	}
+
+	var out []ports.NetWorthSnapshot
+	for d := dayOf(from); !d.After(to); d = d.AddDate(0, 0, 1) {
+		for _, x := range byDay[d] {
+			bal[x.WalletID] += x.Amount
+		}
+		for _, w := range opening {
+			share := w.Share
+			if share <= 0 {
+				share = 1
+			}
+			out = append(out, ports.NetWorthSnapshot{
+				Date: d, WalletID: w.WalletID, Currency: w.Currency, Balance: round2(bal[w.WalletID]),
+				Liability: w.Kind == ports.WalletCreditCard || w.Kind == ports.WalletLoan, Share: share,
+			})
+		}
+	}
+	return out
+}
+
+// ApplyRates tarih sırasındaki görüntülerin Value alanını kullanıcının payı üzerinden
+// hesaplar: o güne kadarki son tarihsel kur, yoksa latest çarpanı kullanılır.
+func ApplyRates(rows []ports.NetWorthSnapshot, latest map[string]float64, hist []ports.FXRate) {
+	eff := map[string]float64{}
+	i := 0
+	for k := range rows {
+		d := dayOf(rows[k].Date)
+		for ; i < len(hist) && !dayOf(hist[i].Date).After(d); i++ {
+			if hist[i].Rate > 0 {
+				eff[hist[i].Quote] = 1 / hist[i].Rate
+			}
+		}
+		m, ok := eff[rows[k].Currency]
+		if !ok {
+			m = latest[rows[k].Currency]
+		}
+		share := rows[k].Share
+		if share <= 0 {
+			share = 1
+		}
+		rows[k].Value = round2(rows[k].Balance * share * m)
+	}
+}
+
+// RunDaily tüm kullanıcılar için bugünün görüntüsünü alır; geçmişi bayatlamış
+// kullanıcılarda işaretli günden itibaren yeniden hesaplar. İşlenen kullanıcı sayısını döner.
+func (s *NetWorthService) RunDaily() (int, error) {
+	ids, err := s.Repo.UserIDs()
+	if err != nil {
+		return 0, err
+	}
+	stale, err := s.Repo.Stale()
+	if err != nil {
+		return 0, err
+	}
+	today := dayOf(s.now())
+	var (
+		n       int
+		lastErr error
+	)
+	for _, uid := range ids {
+		from := today
+		since, isStale := stale[uid]
+		if isStale && since.Before(today) {
+			from = since
+			if today.Sub(from) > maxBackfillDays*24*time.Hour {
+				from = today.AddDate(0, 0, -maxBackfillDays)
+			}
+		}
+		if _, err := s.Backfill(uid, from); err != nil {
+			lastErr = err
+			continue
+		}
+		if isStale {
+			if err := s.Repo.ClearStale(uid, since); err != nil {
+				lastErr = err
+			}
+		}
+		n++
+	}
+	return n, lastErr
+}
+
+// Series [from, to] aralığını interval'e göre gruplar; her nokta dönemin son görüntüsüdür.
+func (s *NetWorthService) Series(uid int64, from, to time.Time, interval string) (*ports.NetWorthSeries, error) {
+	if interval == "" {
+		interval = ports.IntervalMonth
+	}
+	if interval != ports.IntervalDay && interval != ports.IntervalWeek && interval != ports.IntervalMonth {
+		return nil, errs.ValidationFailed("bad_interval")
+	}
+	if to.IsZero() {
+		to = s.now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+	from, to = dayOf(from), dayOf(to)
+	if from.After(to) {
+		return nil, errs.ValidationFailed("bad_range")
+	}
+	base, err := s.Repo.BaseCurrency(uid)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.Snapshots(uid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.NetWorthSeries{Currency: base, Interval: interval, Points: NetWorthPoints(rows, interval)}, nil
+}
+
+// NetWorthPoints görüntüleri günlük toplamlara, ardından dönem sonu noktalarına indirger.
+// Kredi kartı ve kredi cüzdanları borç, diğerleri varlık sayılır; eksi bakiyeli bir
+// vadesiz hesap varlığı, fazla ödenmiş bir kart borcu azaltır.
+func NetWorthPoints(rows []ports.NetWorthSnapshot, interval string) []ports.NetWorthPoint {
+	daily := map[time.Time]*ports.NetWorthPoint{}
+	for _, r := range rows {
+		d := dayOf(r.Date)
+		p := daily[d]
+		if p == nil {
+			p = &ports.NetWorthPoint{Date: d}
+			daily[d] = p
+		}
+		if r.Liability {
+			p.Liabilities -= r.Value
+		} else {
+			p.Assets += r.Value
+		}
+	}
+	days := make([]time.Time, 0, len(daily))
+	for d := range daily {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	out := []ports.NetWorthPoint{}
+	var lastKey time.Time
+	for _, d := range days {
+		p := *daily[d]
+		p.Assets, p.Liabilities = round2(p.Assets), round2(p.Liabilities)
+		p.NetWorth = round2(p.Assets - p.Liabilities)
+		k := bucketOf(d, interval)
+		if len(out) > 0 && k.Equal(lastKey) {
+			out[len(out)-1] = p
+			continue
+		}
+		out = append(out, p)
+		lastKey = k
+	}
+	return out
+}
+
+func bucketOf(d time.Time, interval string) time.Time {
+	switch interval {
+	case ports.IntervalWeek:
+		// Pazartesi başlangıçlı hafta.
+		return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	case ports.IntervalMonth:
+		return time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return d
+}
+
+// converter cüzdan para birimlerinden ana para birimine çarpanları döner.
+func (s *NetWorthService) converter(base string, wallets []ports.WalletBalance) (map[string]float64, error) {
+	out := map[string]float64{base: 1}
+	var need bool
+	for _, w := range wallets {
+		if _, ok := out[w.Currency]; !ok {
+			need = true
+		}
+	}
+	if !need {
+		return out, nil
+	}
+	if s.Rates == nil {
+		return nil, errs.ValidationFailed("rates_unavailable")
+	}
+	_, date, rates, err := s.Rates.Latest(base)
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		date = s.now()
+	}
+	if err := s.Repo.SaveRates(base, dayOf(date), rates); err != nil {
+		return nil, err
+	}
+	for _, w := range wallets {
+		if _, ok := out[w.Currency]; ok {
+			continue
+		}
+		r := rates[w.Currency]
+		if r <= 0 {
+			return nil, errs.ValidationFailed("rate_unavailable_" + strings.ToLower(w.Currency))
+		}
+		out[w.Currency] = 1 / r
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type staleRec struct{ since []time.Time }
+
+func (s *staleRec) MarkStale(uid int64, since time.Time) error {
+	s.since = append(s.since, since)
+	return nil
+}
+
+func TestSnapshotDays_AppliesDailyDeltas(t *testing.T) {
+	from := dayOf(day(2026, 3, 1))
+	opening := []ports.WalletBalance{{WalletID: 1, Currency: "TRY", Balance: 100}, {WalletID: 2, Currency: "TRY", Balance: -50}}
+	deltas := []ports.WalletDelta{
+		{WalletID: 1, Day: from.AddDate(0, 0, 1), Amount: 25},
+		{WalletID: 2, Day: from.AddDate(0, 0, 2), Amount: -10},
+	}
+	rows := SnapshotDays(opening, deltas, from, from.AddDate(0, 0, 2))
+	if len(rows) != 6 {
+		t.Fatalf("want 6 rows, got %d", len(rows))
+	}
+	last := rows[4:]
+	if last[0].Balance != 125 || last[1].Balance != -60 {
+		t.Fatalf("bad closing balances: %+v", last)
+	}
+	if rows[0].Balance != 100 || rows[1].Balance != -50 {
+		t.Fatalf("bad opening day: %+v", rows[:2])
+	}
+}
+
+func TestNetWorthPoints_MonthTakesLastDay(t *testing.T) {
+	rows := []ports.NetWorthSnapshot{
+		{Date: dayOf(day(2026, 1, 10)), WalletID: 1, Value: 1000},
+		{Date: dayOf(day(2026, 1, 31)), WalletID: 1, Value: 1200},
+		{Date: dayOf(day(2026, 1, 31)), WalletID: 2, Value: -300, Liability: true},
+		{Date: dayOf(day(2026, 2, 5)), WalletID: 1, Value: 900},
+	}
+	pts := NetWorthPoints(rows, ports.IntervalMonth)
+	if len(pts) != 2 {
+		t.Fatalf("want 2 points, got %+v", pts)
+	}
+	if pts[0].Assets != 1200 || pts[0].Liabilities != 300 || pts[0].NetWorth != 900 {
+		t.Fatalf("bad january point: %+v", pts[0])
+	}
+	if !pts[1].Date.Equal(dayOf(day(2026, 2, 5))) || pts[1].NetWorth != 900 {
+		t.Fatalf("bad february point: %+v", pts[1])
+	}
+	if n := len(NetWorthPoints(rows, ports.IntervalDay)); n != 3 {
+		t.Fatalf("want 3 daily points, got %d", n)
+	}
+}
+
+func TestNetWorth_LiabilityByKindAndShare(t *testing.T) {
+	from := dayOf(day(2026, 3, 1))
+	opening := []ports.WalletBalance{
+		{WalletID: 1, Currency: "TRY", Kind: ports.WalletBank, Balance: -20},
+		{WalletID: 2, Currency: "TRY", Kind: ports.WalletCreditCard, Balance: -100},
+		{WalletID: 3, Currency: "TRY", Kind: ports.WalletCash, Share: 0.5, Balance: 400},
+	}
+	rows := SnapshotDays(opening, nil, from, from)
+	ApplyRates(rows, map[string]float64{"TRY": 1}, nil)
+	pts := NetWorthPoints(rows, ports.IntervalDay)
+	// Eksi bakiyeli hesap varlığı azaltır; hane cüzdanının yarısı sayılır.
+	if pts[0].Assets != 180 || pts[0].Liabilities != 100 || pts[0].NetWorth != 80 {
+		t.Fatalf("bad point: %+v", pts[0])
+	}
+}
+
+func TestApplyRates_UsesHistoricalRate(t *testing.T) {
+	from := dayOf(day(2026, 3, 1))
+	opening := []ports.WalletBalance{{WalletID: 1, Currency: "USD", Kind: ports.WalletCash, Balance: 10}}
+	rows := SnapshotDays(opening, nil, from.AddDate(0, 0, -1), from.AddDate(0, 0, 1))
+	hist := []ports.FXRate{
+		{Date: from, Quote: "USD", Rate: 0.05},
+		{Date: from.AddDate(0, 0, 1), Quote: "USD", Rate: 0.025},
+	}
+	ApplyRates(rows, map[string]float64{"TRY": 1, "USD": 30}, hist)
+	// Tarihsel kur yoksa güncel kur, sonra o günün kuru kullanılır.
+	if rows[0].Value != 300 || rows[1].Value != 200 || rows[2].Value != 400 {
+		t.Fatalf("bad values: %v %v %v", rows[0].Value, rows[1].Value, rows[2].Value)
+	}
+}
+
+func TestTx_Update_MarksEarlierDateStale(t *testing.T) {
+	txr := &fakeTxRepo{rows: []ports.Transaction{{ID: 5, OccurredAt: day(2026, 1, 3)}}}
+	rec := &staleRec{}
+	svc := &TxService{Repo: txr, NetWorth: rec}
+
+	if err := svc.Update(1, &ports.Transaction{ID: 5, OccurredAt: day(2026, 2, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.since) != 1 || !rec.since[0].Equal(day(2026, 1, 3)) {
+		t.Fatalf("want old date marked, got %v", rec.since)
+	}
+}
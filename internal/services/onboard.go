@@ -1,24 +1,132 @@
 package services
 
-import "github.com/Veysel440/finance-master-api/internal/ports"
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// DefaultLocale eşleşen şablon yoksa kullanılır.
+const DefaultLocale = "tr"
+
+//go:embed onboarding/*.json
+var onboardFS embed.FS
+
+var onboardTemplates = mustLoadTemplates()
+
+func mustLoadTemplates() map[string]ports.OnboardTemplate {
+	files, err := onboardFS.ReadDir("onboarding")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]ports.OnboardTemplate{}
+	for _, f := range files {
+		b, err := onboardFS.ReadFile(path.Join("onboarding", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var t ports.OnboardTemplate
+		if err := json.Unmarshal(b, &t); err != nil {
+			panic(fmt.Sprintf("onboarding/%s: %v", f.Name(), err))
+		}
+		out[t.Locale] = t
+	}
+	return out
+}
+
+// Locales desteklenen şablon dilleri.
+func Locales() []string {
+	out := make([]string, 0, len(onboardTemplates))
+	for l := range onboardTemplates {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func HasLocale(l string) bool {
+	_, ok := onboardTemplates[strings.ToLower(l)]
+	return ok
+}
+
+// PickLocale açık seçimi, yoksa Accept-Language'deki en yüksek q değerli desteklenen dili seçer.
+// "de-AT" gibi bölge etiketleri birincil dile indirgenir.
+func PickLocale(explicit, acceptLanguage string) string {
+	if HasLocale(explicit) {
+		return strings.ToLower(explicit)
+	}
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if i := strings.IndexAny(tag, "-_"); i > 0 {
+			tag = tag[:i]
+		}
+		q := 1.0
+		for _, p := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if HasLocale(tag) && q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	if best == "" {
+		return DefaultLocale
+	}
+	return best
+}
 
 type OnboardService struct {
-	Wallet ports.WalletRepo
-	Cat    ports.CategoryRepo
+	Repo  ports.OnboardRepo
+	Audit *AuditService
 }
 
-func (o *OnboardService) Seed(userID int64) error {
-	if o == nil {
-		return nil
+// Seed kayıt sırasında şablonu uygular; dil ve ana para birimi de şablondan atanır.
+func (o *OnboardService) Seed(userID int64, locale string) (*ports.SeedResult, error) {
+	if o == nil || o.Repo == nil {
+		return nil, nil
 	}
-	if o.Wallet != nil {
-		_ = o.Wallet.Create(userID, &ports.Wallet{Name: "Ana Cüzdan", Currency: "TRY"})
+	return o.apply(userID, PickLocale(locale, ""), true)
+}
+
+// Reseed silinmiş/eksik varsayılanları geri ekler; dil verilmezse kullanıcının kayıtlı dili kullanılır.
+func (o *OnboardService) Reseed(userID int64, locale string) (*ports.SeedResult, error) {
+	if locale != "" && !HasLocale(locale) {
+		return nil, errs.ValidationFailed("unsupported_locale")
+	}
+	if locale == "" {
+		l, err := o.Repo.Locale(userID)
+		if err != nil {
+			return nil, err
+		}
+		locale = l
+	}
+	return o.apply(userID, PickLocale(locale, ""), false)
+}
+
+func (o *OnboardService) apply(userID int64, locale string, initial bool) (*ports.SeedResult, error) {
+	res, err := o.Repo.Apply(userID, onboardTemplates[locale], initial)
+	if err != nil {
+		if o.Audit != nil {
+			o.Audit.Log(userID, "onboard.failed", "user", &userID, map[string]any{"locale": locale, "error": err.Error()})
+		}
+		return nil, err
 	}
-	if o.Cat != nil {
-		_ = o.Cat.Create(userID, &ports.Category{Name: "Maaş", Type: "income"})
-		_ = o.Cat.Create(userID, &ports.Category{Name: "Serbest", Type: "income"})
-		_ = o.Cat.Create(userID, &ports.Category{Name: "Yemek", Type: "expense"})
-		_ = o.Cat.Create(userID, &ports.Category{Name: "Market", Type: "expense"})
+	if o.Audit != nil {
+		o.Audit.Log(userID, "onboard.seed", "user", &userID, map[string]any{
+			"locale": locale, "initial": initial, "wallets": res.Wallets, "categories": res.Categories,
+		})
 	}
-	return nil
+	return &res, nil
 }
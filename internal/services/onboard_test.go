@@ -1,32 +1,96 @@
 package services
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/Veysel440/finance-master-api/internal/ports"
 )
 
-type fakeWalRepo struct{ created int }
+type fakeOnboardRepo struct {
+	locale  string
+	tpl     ports.OnboardTemplate
+	initial bool
+	err     error
+}
 
-func (f *fakeWalRepo) List(int64) ([]ports.Wallet, error) { return nil, nil }
-func (f *fakeWalRepo) Create(int64, *ports.Wallet) error  { f.created++; return nil }
-func (f *fakeWalRepo) Update(int64, *ports.Wallet) error  { return nil }
-func (f *fakeWalRepo) Delete(int64, int64) error          { return nil }
+func (f *fakeOnboardRepo) Locale(int64) (string, error) { return f.locale, nil }
+func (f *fakeOnboardRepo) Apply(_ int64, tpl ports.OnboardTemplate, initial bool) (ports.SeedResult, error) {
+	f.tpl, f.initial = tpl, initial
+	if f.err != nil {
+		return ports.SeedResult{}, f.err
+	}
+	return ports.SeedResult{Locale: tpl.Locale, Wallets: len(tpl.Wallets), Categories: len(tpl.Categories)}, nil
+}
 
-type fakeCatRepo struct{ created int }
+func TestOnboard_Seed(t *testing.T) {
+	repo := &fakeOnboardRepo{}
+	o := &OnboardService{Repo: repo}
+	res, err := o.Seed(1, "en")
+	if err != nil || res.Locale != "en" || !repo.initial || repo.tpl.Currency != "USD" {
+		t.Fatalf("bad seed: %v %+v %+v", err, res, repo.tpl)
+	}
 
-func (f *fakeCatRepo) List(int64, string) ([]ports.Category, error) { return nil, nil }
-func (f *fakeCatRepo) Create(int64, *ports.Category) error          { f.created++; return nil }
-func (f *fakeCatRepo) Update(int64, *ports.Category) error          { return nil }
-func (f *fakeCatRepo) Delete(int64, int64) error                    { return nil }
+	repo.err = errors.New("deadlock")
+	if _, err := o.Seed(1, "tr"); err == nil {
+		t.Fatalf("seeding failures must be reported")
+	}
+}
 
-func TestOnboard_Seed(t *testing.T) {
-	wr, cr := &fakeWalRepo{}, &fakeCatRepo{}
-	o := &OnboardService{Wallet: wr, Cat: cr}
-	if err := o.Seed(1); err != nil {
-		t.Fatalf("seed err: %v", err)
+func TestOnboard_Reseed(t *testing.T) {
+	repo := &fakeOnboardRepo{locale: "de"}
+	o := &OnboardService{Repo: repo}
+	if _, err := o.Reseed(1, ""); err != nil || repo.tpl.Locale != "de" || repo.initial {
+		t.Fatalf("reseed must use stored locale without touching profile: %v %+v", err, repo)
 	}
-	if wr.created < 1 || cr.created < 4 {
-		t.Fatalf("defaults not created")
+	if _, err := o.Reseed(1, "xx"); !failedWith(err, "unsupported_locale") {
+		t.Fatalf("want unsupported_locale, got %v", err)
+	}
+}
+
+func TestPickLocale(t *testing.T) {
+	cases := []struct{ explicit, header, want string }{
+		{"de", "en-US,en;q=0.9", "de"},
+		{"", "fr-FR,de-AT;q=0.8,en;q=0.5", "de"},
+		{"", "en-GB", "en"},
+		{"", "ja,fr;q=0.5", DefaultLocale},
+		{"xx", "", DefaultLocale},
+	}
+	for _, c := range cases {
+		if got := PickLocale(c.explicit, c.header); got != c.want {
+			t.Errorf("PickLocale(%q, %q) = %q, want %q", c.explicit, c.header, got, c.want)
+		}
+	}
+}
+
+func TestOnboardTemplates_Valid(t *testing.T) {
+	for _, l := range []string{"tr", "en", "de"} {
+		tpl, ok := onboardTemplates[l]
+		if !ok {
+			t.Fatalf("missing template %s", l)
+		}
+		if len(tpl.Currency) != 3 || len(tpl.Wallets) == 0 {
+			t.Fatalf("%s: bad currency or wallets: %+v", l, tpl)
+		}
+		seen := map[string]bool{}
+		var walk func(typ string, cs []ports.TemplateCategory)
+		walk = func(typ string, cs []ports.TemplateCategory) {
+			for _, c := range cs {
+				ct := typ
+				if ct == "" {
+					ct = c.Type
+				}
+				if ct != "income" && ct != "expense" {
+					t.Fatalf("%s: %q has no type", l, c.Name)
+				}
+				// categories (user_id, type, name) tekildir.
+				if seen[ct+"|"+c.Name] {
+					t.Fatalf("%s: duplicate category %q", l, c.Name)
+				}
+				seen[ct+"|"+c.Name] = true
+				walk(ct, c.Children)
+			}
+		}
+		walk("", tpl.Categories)
 	}
 }
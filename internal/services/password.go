@@ -0,0 +1,163 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+	"github.com/Veysel440/finance-master-api/internal/validation"
+)
+
+// ForgotPassword hesap varsa tek kullanımlık sıfırlama bağlantısı postalar. Hesabın varlığını
+// sızdırmamak için bilinmeyen adres ve hız sınırı aşımı da sessizce başarılı sayılır.
+func (s *AuthService) ForgotPassword(email string) error {
+	s.defaults()
+	if s.Resets == nil || s.Mailer == nil {
+		return nil
+	}
+	u, err := s.Repo.FindUserByEmail(strings.TrimSpace(email))
+	if err != nil || u == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	n, err := s.Resets.RecentResets(u.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if n >= s.ResetPerHour {
+		if s.Audit != nil {
+			s.Audit.Log(u.ID, "auth.password_forgot_throttled", "user", &u.ID, nil)
+		}
+		return nil
+	}
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	if err := s.Resets.CreateReset(u.ID, security.SHA256Hex(token), now, now.Add(s.ResetTTL)); err != nil {
+		return err
+	}
+	if err := s.Mailer.Send(resetMail(u.Email, s.ResetURL, token, s.ResetTTL)); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(u.ID, "auth.password_forgot", "user", &u.ID, nil)
+	}
+	return nil
+}
+
+// ResetPassword belirteci tüketip yeni parolayı yazar ve tüm oturumları kapatır.
+func (s *AuthService) ResetPassword(token, newPass string) error {
+	if s.Resets == nil {
+		return errs.Forbidden
+	}
+	hash := security.SHA256Hex(strings.TrimSpace(token))
+	now := time.Now().UTC()
+	_, email, err := s.Resets.PeekReset(hash, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.ValidationFailed("invalid_reset_token")
+	}
+	if err != nil {
+		return err
+	}
+	if err := validation.ValidatePassword(newPass, email); err != nil {
+		return errs.ValidationFailed("weak_password")
+	}
+	passHash, err := security.ArgonHash(newPass)
+	if err != nil {
+		return err
+	}
+	uid, err := s.Resets.CompleteReset(hash, passHash, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.ValidationFailed("invalid_reset_token")
+	}
+	if err != nil {
+		return err
+	}
+	if s.Sess != nil {
+		if err := s.Sess.RevokeAll(uid); err != nil {
+			return err
+		}
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.password_reset", "user", &uid, nil)
+	}
+	return nil
+}
+
+// tokenLink belirteci istemci bağlantısına "token" parametresi olarak ekler; base boşsa
+// belirtecin kendisi döner.
+func tokenLink(base, token string) string {
+	if base == "" {
+		return token
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(token)
+}
+
+// ChangePassword mevcut parola (ve etkinse TOTP) ile yeniden doğrulayıp parolayı değiştirir.
+// Yanlış mevcut parola girişteki gibi kilitleme sayacına yazılır. refresh çağıranın geçerli
+// refresh belirtecidir; yalnız o oturum açık kalır, diğerleri kapatılır.
+func (s *AuthService) ChangePassword(uid int64, current, newPass, code, refresh, ip string) error {
+	s.defaults()
+	u, err := s.Repo.FindUserByID(uid)
+	if err != nil {
+		return err
+	}
+	if until := s.getLock(uid); until != nil && time.Now().Before(*until) {
+		return errs.AccountLocked
+	}
+	if ok, _ := security.ArgonCheck(current, u.PassHash); !ok {
+		if locked, _ := s.bumpFail(uid, u.Email, ip); locked {
+			return errs.AccountLocked
+		}
+		return errs.InvalidCredentials
+	}
+	if _, err := verifySecondFactor(s.Repo, uid, code, time.Now()); err != nil {
+		return err
+	}
+	if err := validation.ValidatePassword(newPass, u.Email); err != nil {
+		return errs.ValidationFailed("weak_password")
+	}
+	if same, _ := security.ArgonCheck(newPass, u.PassHash); same {
+		return errs.ValidationFailed("same_password")
+	}
+	passHash, err := security.ArgonHash(newPass)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.SetPassword(uid, passHash); err != nil {
+		return err
+	}
+	s.resetFail(u.Email, ip)
+	if s.Sess != nil {
+		if err := s.Sess.RevokeOthers(uid, security.SHA256Hex(strings.TrimSpace(refresh))); err != nil {
+			return err
+		}
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.password_change", "user", &uid, map[string]any{"ip": ip})
+	}
+	return nil
+}
+
+func resetMail(to, base, token string, ttl time.Duration) ports.Mail {
+	link := tokenLink(base, token)
+	return ports.Mail{
+		To:      to,
+		Subject: "Finance Master password reset",
+		Text: fmt.Sprintf("A password reset was requested for your Finance Master account.\n\n"+
+			"Use the link below within %d minutes to choose a new password:\n\n%s\n\n"+
+			"If you did not request this, you can ignore this email; your password stays unchanged.\n",
+			int(ttl.Minutes()), link),
+	}
+}
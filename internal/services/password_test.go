@@ -0,0 +1,214 @@
+package services
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+	"github.com/pquerna/otp/totp"
+)
+
+type fakeReset struct {
+	uid     int64
+	created time.Time
+	expires time.Time
+	used    bool
+}
+
+type fakeResetRepo struct {
+	auth   *fakeAuthRepo
+	tokens map[string]*fakeReset
+}
+
+func (f *fakeResetRepo) CreateReset(uid int64, hash string, created, expires time.Time) error {
+	f.tokens[hash] = &fakeReset{uid: uid, created: created, expires: expires}
+	return nil
+}
+func (f *fakeResetRepo) RecentResets(uid int64, since time.Time) (int, error) {
+	n := 0
+	for _, r := range f.tokens {
+		if r.uid == uid && !r.created.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+func (f *fakeResetRepo) PeekReset(hash string, now time.Time) (int64, string, error) {
+	r, ok := f.tokens[hash]
+	if !ok || r.used || !now.Before(r.expires) {
+		return 0, "", sql.ErrNoRows
+	}
+	return r.uid, f.auth.users[r.uid].Email, nil
+}
+func (f *fakeResetRepo) CompleteReset(hash, passHash string, now time.Time) (int64, error) {
+	uid, _, err := f.PeekReset(hash, now)
+	if err != nil {
+		return 0, err
+	}
+	u := f.auth.users[uid]
+	u.PassHash = passHash
+	f.auth.byEmail[u.Email] = u
+	for _, r := range f.tokens {
+		if r.uid == uid {
+			r.used = true
+		}
+	}
+	return uid, nil
+}
+
+type memMailer struct{ sent []ports.Mail }
+
+func (m *memMailer) Send(mail ports.Mail) error { m.sent = append(m.sent, mail); return nil }
+
+type fakeSessionRepo struct {
+	revoked []int64
+	kept    []string
+}
+
+func (f *fakeSessionRepo) StoreRefreshMeta(int64, string, string, string, time.Time, time.Time) error {
+	return nil
+}
+func (f *fakeSessionRepo) ValidateRefresh(int64, string, string, string, time.Time, bool, bool) (bool, error) {
+	return false, nil
+}
+func (f *fakeSessionRepo) RotateRefreshMeta(int64, string, string, string, string, time.Time, time.Time) error {
+	return nil
+}
+func (f *fakeSessionRepo) ListSessions(int64, int, int) ([]ports.Session, int, error) {
+	return nil, 0, nil
+}
+func (f *fakeSessionRepo) RevokeSession(int64, int64) error { return nil }
+func (f *fakeSessionRepo) RevokeAll(uid int64) error {
+	f.revoked = append(f.revoked, uid)
+	return nil
+}
+func (f *fakeSessionRepo) RevokeOthers(uid int64, keepHash string) error {
+	f.revoked = append(f.revoked, uid)
+	f.kept = append(f.kept, keepHash)
+	return nil
+}
+
+var reResetToken = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+func TestPassword_ForgotAndReset(t *testing.T) {
+	s, repo := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	mails, sess := &memMailer{}, &fakeSessionRepo{}
+	s.Resets = &fakeResetRepo{auth: repo, tokens: map[string]*fakeReset{}}
+	s.Mailer, s.Sess, s.ResetURL = mails, sess, "https://app.example/reset"
+
+	if err := s.ForgotPassword("nobody@e.com"); err != nil || len(mails.sent) != 0 {
+		t.Fatalf("unknown email must be silent: %v %d", err, len(mails.sent))
+	}
+	if err := s.ForgotPassword("v@e.com"); err != nil || len(mails.sent) != 1 {
+		t.Fatalf("forgot: %v %d", err, len(mails.sent))
+	}
+	m := reResetToken.FindStringSubmatch(mails.sent[0].Text)
+	if m == nil || mails.sent[0].To != "v@e.com" {
+		t.Fatalf("mail without reset link: %+v", mails.sent[0])
+	}
+	token := m[1]
+
+	if err := s.ResetPassword(token, "short"); !failedWith(err, "weak_password") {
+		t.Fatalf("weak: %v", err)
+	}
+	if err := s.ResetPassword(token, "N3w-Passw0rd!"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := security.ArgonCheck("N3w-Passw0rd!", repo.users[uid].PassHash); !ok {
+		t.Fatalf("password not changed")
+	}
+	if len(sess.revoked) != 1 || sess.revoked[0] != uid {
+		t.Fatalf("sessions not revoked: %v", sess.revoked)
+	}
+	if err := s.ResetPassword(token, "An0ther-Pass!"); !failedWith(err, "invalid_reset_token") {
+		t.Fatalf("token reused: %v", err)
+	}
+}
+
+func TestPassword_ForgotRateLimitedPerAccount(t *testing.T) {
+	s, repo := newSvc()
+	mustRegister(t, s, "v@e.com", "A1complex!")
+	mails := &memMailer{}
+	s.Resets = &fakeResetRepo{auth: repo, tokens: map[string]*fakeReset{}}
+	s.Mailer = mails
+
+	for i := 0; i < 5; i++ {
+		if err := s.ForgotPassword("v@e.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(mails.sent) != 3 {
+		t.Fatalf("want 3 mails within the hour, got %d", len(mails.sent))
+	}
+}
+
+func TestPassword_ExpiredToken(t *testing.T) {
+	s, repo := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	resets := &fakeResetRepo{auth: repo, tokens: map[string]*fakeReset{}}
+	s.Resets = resets
+	past := time.Now().Add(-2 * time.Hour)
+	_ = resets.CreateReset(uid, security.SHA256Hex("old-token-value-123"), past, past.Add(30*time.Minute))
+
+	if err := s.ResetPassword("old-token-value-123", "N3w-Passw0rd!"); !failedWith(err, "invalid_reset_token") {
+		t.Fatalf("expired: %v", err)
+	}
+}
+
+func TestPassword_Change(t *testing.T) {
+	s, repo := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	sess := &fakeSessionRepo{}
+	s.Sess = sess
+	_, refresh, _, err := s.Login("v@e.com", "A1complex!", "d1", "phone", "", "ua", "ip", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ChangePassword(uid, "wrong-pass", "N3w-Passw0rd!", "", refresh, "ip"); err != errs.InvalidCredentials {
+		t.Fatalf("wrong current: %v", err)
+	}
+	if err := s.ChangePassword(uid, "A1complex!", "A1complex!", "", refresh, "ip"); !failedWith(err, "same_password") {
+		t.Fatalf("same: %v", err)
+	}
+	if err := s.ChangePassword(uid, "A1complex!", "short", "", refresh, "ip"); !failedWith(err, "weak_password") {
+		t.Fatalf("weak: %v", err)
+	}
+
+	now := time.Now()
+	repo.totp[uid] = &ports.TotpSecret{UserID: uid, Secret: "JBSWY3DPEHPK3PXP", ConfirmedAt: &now}
+	if err := s.ChangePassword(uid, "A1complex!", "N3w-Passw0rd!", "", refresh, "ip"); err != errs.TOTPRequired {
+		t.Fatalf("totp required: %v", err)
+	}
+	code, _ := totp.GenerateCode("JBSWY3DPEHPK3PXP", time.Now())
+	if err := s.ChangePassword(uid, "A1complex!", "N3w-Passw0rd!", code, refresh, "ip"); err != nil {
+		t.Fatalf("change: %v", err)
+	}
+	if ok, _ := security.ArgonCheck("N3w-Passw0rd!", repo.users[uid].PassHash); !ok {
+		t.Fatalf("password not changed")
+	}
+	if len(sess.revoked) != 1 || sess.revoked[0] != uid || sess.kept[0] != security.SHA256Hex(refresh) {
+		t.Fatalf("only the caller's session should survive: %v %v", sess.revoked, sess.kept)
+	}
+}
+
+func TestPassword_ChangeWrongCurrentLocks(t *testing.T) {
+	s, _ := newSvc()
+	s.MaxLoginFailures = 2
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+
+	if err := s.ChangePassword(uid, "wrong-pass", "N3w-Passw0rd!", "", "", "ip"); err != errs.InvalidCredentials {
+		t.Fatalf("first: %v", err)
+	}
+	if err := s.ChangePassword(uid, "wrong-pass", "N3w-Passw0rd!", "", "", "ip"); err != errs.AccountLocked {
+		t.Fatalf("second: %v", err)
+	}
+	if err := s.ChangePassword(uid, "A1complex!", "N3w-Passw0rd!", "", "", "ip"); err != errs.AccountLocked {
+		t.Fatalf("locked: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+// ReconcileService cüzdanı banka ekstresiyle karşılaştırır: ekstre tarihine kadarki cleared
+// işlemlerin bakiyesi ekstre kapanış bakiyesine eşitse set kilitlenebilir.
+type ReconcileService struct {
+	Repo    ports.ReconcileRepo
+	Wallets ports.WalletRepo
+	Access  WalletAccess
+	Audit   *AuditService
+}
+
+func (s *ReconcileService) canWrite(uid, walletID int64) error {
+	if s.Access != nil {
+		return s.Access.CanWrite(uid, walletID)
+	}
+	_, err := s.Wallets.Get(uid, walletID)
+	return err
+}
+
+func (s *ReconcileService) Start(uid int64, rc *ports.Reconciliation) error {
+	if rc.StatementDate.IsZero() {
+		return errs.ValidationFailed("statement_date_required")
+	}
+	rc.StatementDate = dayOf(rc.StatementDate)
+	if err := s.canWrite(uid, rc.WalletID); err != nil {
+		return err
+	}
+	if err := s.Repo.Create(uid, rc); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "reconciliation.start", "wallet", &rc.WalletID, map[string]any{
+			"reconciliation": rc.ID, "endingBalance": rc.EndingBalance,
+		})
+	}
+	return s.fill(rc)
+}
+
+func (s *ReconcileService) List(uid, walletID int64) ([]ports.Reconciliation, error) {
+	return s.Repo.List(uid, walletID)
+}
+
+// Get açık oturumlarda farkı güncel işlemlerle yeniden hesaplar.
+func (s *ReconcileService) Get(uid, id int64) (*ports.Reconciliation, error) {
+	rc, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	if rc.Status != ports.ReconLocked {
+		if err := s.fill(rc); err != nil {
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+// Lock fark sıfırsa ekstre tarihine kadarki cleared işlemleri reconciled yapar.
+func (s *ReconcileService) Lock(uid, id int64) (int64, error) {
+	rc, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return 0, err
+	}
+	if rc.Status == ports.ReconLocked {
+		return 0, errs.Conflict
+	}
+	if err := s.canWrite(uid, rc.WalletID); err != nil {
+		return 0, err
+	}
+	if err := s.fill(rc); err != nil {
+		return 0, err
+	}
+	if *rc.Difference != 0 {
+		return 0, errs.ValidationFailed("reconciliation_difference")
+	}
+	n, err := s.Repo.Lock(rc, rc.StatementDate.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "reconciliation.lock", "wallet", &rc.WalletID, map[string]any{
+			"reconciliation": rc.ID, "count": n,
+		})
+	}
+	return n, nil
+}
+
+// Unlock kilitli seti çözer; işlemler cleared'a döner ve yeniden düzenlenebilir.
+func (s *ReconcileService) Unlock(uid, id int64) (int64, error) {
+	rc, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return 0, err
+	}
+	if rc.Status != ports.ReconLocked {
+		return 0, errs.Conflict
+	}
+	if err := s.canWrite(uid, rc.WalletID); err != nil {
+		return 0, err
+	}
+	n, err := s.Repo.Unlock(rc)
+	if err != nil {
+		return 0, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "reconciliation.unlock", "wallet", &rc.WalletID, map[string]any{
+			"reconciliation": rc.ID, "count": n,
+		})
+	}
+	return n, nil
+}
+
+func (s *ReconcileService) fill(rc *ports.Reconciliation) error {
+	t, err := s.Repo.Cleared(rc.WalletID, rc.StatementDate.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	cleared := round2(t.Balance)
+	diff := round2(rc.EndingBalance - cleared)
+	rc.ClearedBalance, rc.Difference = &cleared, &diff
+	rc.ClearedCount, rc.PendingCount = t.ClearedCount, t.PendingCount
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeReconRepo struct {
+	rec    ports.Reconciliation
+	totals ports.ClearedTotals
+	before time.Time
+	locked bool
+}
+
+func (f *fakeReconRepo) Create(userID int64, r *ports.Reconciliation) error {
+	r.ID, r.Status = 1, ports.ReconOpen
+	f.rec = *r
+	return nil
+}
+func (f *fakeReconRepo) Get(userID, id int64) (*ports.Reconciliation, error) {
+	r := f.rec
+	return &r, nil
+}
+func (f *fakeReconRepo) List(userID, walletID int64) ([]ports.Reconciliation, error) {
+	return []ports.Reconciliation{f.rec}, nil
+}
+func (f *fakeReconRepo) Cleared(walletID int64, before time.Time) (ports.ClearedTotals, error) {
+	f.before = before
+	return f.totals, nil
+}
+func (f *fakeReconRepo) Lock(r *ports.Reconciliation, before time.Time) (int64, error) {
+	f.locked = true
+	f.rec.Status = ports.ReconLocked
+	return int64(f.totals.ClearedCount), nil
+}
+func (f *fakeReconRepo) Unlock(r *ports.Reconciliation) (int64, error) {
+	f.rec.Status = ports.ReconUnlocked
+	return 0, nil
+}
+
+func TestReconcile_DifferenceBlocksLock(t *testing.T) {
+	repo := &fakeReconRepo{totals: ports.ClearedTotals{Balance: 950, ClearedCount: 4, PendingCount: 1}}
+	s := &ReconcileService{Repo: repo, Wallets: &fakeWalletRepo{rows: []ports.Wallet{{ID: 3}}}}
+
+	rc := ports.Reconciliation{WalletID: 3, StatementDate: day(2026, 5, 31), EndingBalance: 1000}
+	if err := s.Start(1, &rc); err != nil {
+		t.Fatal(err)
+	}
+	if *rc.Difference != 50 || rc.PendingCount != 1 {
+		t.Fatalf("bad session: %+v", rc)
+	}
+	// Ekstre günü dahil: sınır ertesi günün başı.
+	if !repo.before.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("bad cutoff: %v", repo.before)
+	}
+	if _, err := s.Lock(1, rc.ID); !failedWith(err, "reconciliation_difference") {
+		t.Fatalf("want reconciliation_difference, got %v", err)
+	}
+
+	repo.totals.Balance = 1000
+	n, err := s.Lock(1, rc.ID)
+	if err != nil || n != 4 || !repo.locked {
+		t.Fatalf("lock: n=%d err=%v", n, err)
+	}
+}
+
+func TestTx_ReconciledRefusesEdits(t *testing.T) {
+	txr := &fakeTxRepo{rows: []ports.Transaction{{ID: 8, Status: ports.TxReconciled}}}
+	svc := &TxService{Repo: txr}
+
+	if err := svc.Update(1, &ports.Transaction{ID: 8, Amount: 5}); err == nil || txr.updated != nil {
+		t.Fatalf("update of reconciled tx must fail, got %v", err)
+	}
+	if err := svc.Delete(1, 8); err == nil || txr.deleted != 0 {
+		t.Fatalf("delete of reconciled tx must fail, got %v", err)
+	}
+	if err := svc.UpsertBatch(1, []ports.Transaction{{ID: 8}}); err == nil || txr.batch != 0 {
+		t.Fatalf("batch touching reconciled tx must fail, got %v", err)
+	}
+	if err := svc.Create(1, &ports.Transaction{Status: ports.TxReconciled}); !failedWith(err, "bad_status") {
+		t.Fatalf("want bad_status, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type ReportService struct {
+	Repo ports.ReportRepo
+
+	Now func() time.Time
+}
+
+func (s *ReportService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// TaxYear takvim yılı (UTC) için gelir, indirilebilir gider ve KDV dökümünü döner.
+func (s *ReportService) TaxYear(uid int64, year int) (*ports.TaxReport, error) {
+	if year < 2000 || year > s.now().Year()+1 {
+		return nil, errs.ValidationFailed("bad_year")
+	}
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	rows, err := s.Repo.TaxRows(uid, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.TaxReport{
+		Year: year, From: from, To: to, GeneratedAt: s.now(),
+		Ledgers: BuildTaxLedgers(rows),
+	}, nil
+}
+
+// BuildTaxLedgers satırları para birimine göre ayırır ve oran/ay kırılımlarını toplar.
+// Vergisiz işlemler gelir/gider toplamlarına girer, oran kırılımına girmez.
+func BuildTaxLedgers(rows []ports.TaxRow) []ports.TaxLedger {
+	type acc struct {
+		l     ports.TaxLedger
+		rates map[float64]*ports.TaxRateLine
+	}
+	ledgers := map[string]*acc{}
+	var curs []string
+	for _, r := range rows {
+		a, ok := ledgers[r.Currency]
+		if !ok {
+			a = &acc{l: ports.TaxLedger{Currency: r.Currency}, rates: map[float64]*ports.TaxRateLine{}}
+			a.l.ByMonth = make([]ports.TaxMonthLine, 12)
+			for i := range a.l.ByMonth {
+				a.l.ByMonth[i].Month = i + 1
+			}
+			ledgers[r.Currency] = a
+			curs = append(curs, r.Currency)
+		}
+		if r.Month < 1 || r.Month > 12 {
+			continue
+		}
+		m := &a.l.ByMonth[r.Month-1]
+		var rl *ports.TaxRateLine
+		if r.Rate != nil {
+			if rl = a.rates[*r.Rate]; rl == nil {
+				rl = &ports.TaxRateLine{Rate: *r.Rate}
+				a.rates[*r.Rate] = rl
+			}
+		}
+		switch r.Type {
+		case "income":
+			a.l.Income += r.Total
+			a.l.VATCollected += r.Tax
+			m.Income += r.Total
+			m.VATCollected += r.Tax
+			if rl != nil {
+				rl.Income += r.Total
+				rl.VATCollected += r.Tax
+			}
+		case "expense":
+			a.l.VATPaid += r.Tax
+			m.VATPaid += r.Tax
+			if r.Deductible {
+				a.l.DeductibleExpenses += r.Total
+				a.l.VATDeductible += r.Tax
+				m.DeductibleExpenses += r.Total
+				m.VATDeductible += r.Tax
+			} else {
+				a.l.NonDeductibleExpenses += r.Total
+			}
+			if rl != nil {
+				rl.Expenses += r.Total
+				rl.VATPaid += r.Tax
+				if r.Deductible {
+					rl.VATDeductible += r.Tax
+				}
+			}
+		}
+	}
+	sort.Strings(curs)
+
+	out := make([]ports.TaxLedger, 0, len(curs))
+	for _, c := range curs {
+		a := ledgers[c]
+		l := a.l
+		l.Income, l.DeductibleExpenses = round2(l.Income), round2(l.DeductibleExpenses)
+		l.NonDeductibleExpenses = round2(l.NonDeductibleExpenses)
+		l.VATCollected, l.VATPaid, l.VATDeductible = round2(l.VATCollected), round2(l.VATPaid), round2(l.VATDeductible)
+		l.NetVAT = round2(l.VATCollected - l.VATDeductible)
+		for i := range l.ByMonth {
+			m := &l.ByMonth[i]
+			m.Income, m.DeductibleExpenses = round2(m.Income), round2(m.DeductibleExpenses)
+			m.VATCollected, m.VATPaid, m.VATDeductible = round2(m.VATCollected), round2(m.VATPaid), round2(m.VATDeductible)
+		}
+		l.ByRate = make([]ports.TaxRateLine, 0, len(a.rates))
+		for _, rl := range a.rates {
+			l.ByRate = append(l.ByRate, ports.TaxRateLine{
+				Rate: rl.Rate, Income: round2(rl.Income), Expenses: round2(rl.Expenses),
+				VATCollected: round2(rl.VATCollected), VATPaid: round2(rl.VATPaid), VATDeductible: round2(rl.VATDeductible),
+			})
+		}
+		sort.Slice(l.ByRate, func(i, j int) bool { return l.ByRate[i].Rate < l.ByRate[j].Rate })
+		out = append(out, l)
+	}
+	return out
+}
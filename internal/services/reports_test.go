@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func TestBuildTaxLedgers(t *testing.T) {
+	r20, r10 := 20.0, 10.0
+	rows := []ports.TaxRow{
+		{Month: 1, Currency: "TRY", Type: "income", Rate: &r20, Total: 1200, Tax: 200},
+		{Month: 1, Currency: "TRY", Type: "expense", Rate: &r20, Deductible: true, Total: 600, Tax: 100},
+		{Month: 2, Currency: "TRY", Type: "expense", Rate: &r10, Total: 110, Tax: 10},
+		{Month: 2, Currency: "TRY", Type: "expense", Total: 50},
+		{Month: 3, Currency: "EUR", Type: "income", Total: 300},
+	}
+	ls := BuildTaxLedgers(rows)
+	if len(ls) != 2 || ls[0].Currency != "EUR" || ls[1].Currency != "TRY" {
+		t.Fatalf("ledgers must be split per currency: %+v", ls)
+	}
+	try := ls[1]
+	if try.Income != 1200 || try.DeductibleExpenses != 600 || try.NonDeductibleExpenses != 160 {
+		t.Fatalf("bad totals: %+v", try)
+	}
+	if try.VATCollected != 200 || try.VATPaid != 110 || try.VATDeductible != 100 || try.NetVAT != 100 {
+		t.Fatalf("bad vat: %+v", try)
+	}
+	if len(try.ByRate) != 2 || try.ByRate[0].Rate != 10 || try.ByRate[1].VATCollected != 200 {
+		t.Fatalf("bad rate breakdown: %+v", try.ByRate)
+	}
+	if len(try.ByMonth) != 12 || try.ByMonth[0].VATDeductible != 100 || try.ByMonth[1].VATPaid != 10 {
+		t.Fatalf("bad month breakdown: %+v", try.ByMonth[:2])
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const (
+	StatementOpen          = "open"
+	StatementPaid          = "paid"
+	StatementPartiallyPaid = "partially_paid"
+	StatementUnpaid        = "unpaid"
+	StatementOverdue       = "overdue"
+)
+
+// CardStatement bir kredi kartı hesap dönemi. Bakiye alanları borç yönündedir
+// (pozitif = karta borç). Open dönemde StatementBalance o ana kadarki borçtur.
+type CardStatement struct {
+	PeriodStart      time.Time `json:"periodStart"`
+	PeriodEnd        time.Time `json:"periodEnd"`
+	DueDate          time.Time `json:"dueDate"`
+	OpeningBalance   float64   `json:"openingBalance"`
+	Purchases        float64   `json:"purchases"`
+	Credits          float64   `json:"credits"`
+	StatementBalance float64   `json:"statementBalance"`
+	MinimumPayment   float64   `json:"minimumPayment"`
+	Paid             float64   `json:"paid"`
+	Remaining        float64   `json:"remaining"`
+	Status           string    `json:"status"`
+}
+
+type StatementService struct {
+	Wallets ports.WalletRepo
+	Tx      ports.TxRepo
+
+	// MinPaymentRate asgari ödeme oranı (varsayılan %20).
+	MinPaymentRate float64
+	// MinPaymentRateHigh, limiti HighLimit'i aşan kartlar için oran (varsayılan %40).
+	MinPaymentRateHigh float64
+	HighLimit          float64
+
+	Now func() time.Time
+}
+
+func (s *StatementService) defaults() {
+	if s.MinPaymentRate == 0 {
+		s.MinPaymentRate = 0.20
+	}
+	if s.MinPaymentRateHigh == 0 {
+		s.MinPaymentRateHigh = 0.40
+	}
+	if s.HighLimit == 0 {
+		s.HighLimit = 50000
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+}
+
+// List son count kapanmış dönemi ve açık dönemi (en sonda) döner.
+func (s *StatementService) List(uid, walletID int64, count int) ([]CardStatement, error) {
+	s.defaults()
+	if count < 1 || count > 24 {
+		count = 6
+	}
+	w, err := s.Wallets.Get(uid, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Kind != ports.WalletCreditCard {
+		return nil, errs.ValidationFailed("not_a_credit_card")
+	}
+	if w.StatementDay == nil || w.DueDay == nil {
+		return nil, errs.ValidationFailed("statement_cycle_not_set")
+	}
+
+	now := s.Now().UTC()
+	cyc := cardCycle{closeDay: *w.StatementDay, dueDay: *w.DueDay}
+
+	// Açık dönemin kapanış ayı: bu ayın kesimi geçmediyse bu ay, geçtiyse gelecek ay.
+	openY, openM := now.Year(), now.Month()
+	if !now.Before(cyc.periodEnd(openY, openM)) {
+		openY, openM = addMonths(openY, openM, 1)
+	}
+	openEnd := cyc.periodEnd(openY, openM)
+
+	firstY, firstM := addMonths(openY, openM, -count-1)
+	from := cyc.periodEnd(firstY, firstM)
+	// Erişim yukarıdaki Wallets.Get ile doğrulandı; hane kartında tüm üyelerin harcamaları sayılır.
+	txs, err := s.Tx.ListWalletRange(walletID, from, openEnd)
+	if err != nil {
+		return nil, err
+	}
+	// İlk dönemden önceki hareketler tek bir devreden bakiye olarak gelir (borç yönünde).
+	carry, err := s.Tx.WalletBalanceBefore(walletID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]CardStatement, 0, count+1)
+	for i := count; i >= 0; i-- {
+		y, m := addMonths(openY, openM, -i)
+		py, pm := addMonths(y, m, -1)
+		st := CardStatement{
+			PeriodStart:    cyc.periodEnd(py, pm),
+			PeriodEnd:      cyc.closeOn(y, m),
+			DueDate:        cyc.dueOn(y, m),
+			OpeningBalance: -carry,
+		}
+		end := cyc.periodEnd(y, m)
+		dueEnd := st.DueDate.AddDate(0, 0, 1)
+		var payments float64
+		for _, t := range txs {
+			switch {
+			case t.OccurredAt.Before(st.PeriodStart):
+				st.OpeningBalance += debtDelta(t)
+			case t.OccurredAt.Before(end):
+				if t.Type == "expense" {
+					st.Purchases += t.Amount
+				} else {
+					st.Credits += t.Amount
+				}
+			case t.OccurredAt.Before(dueEnd) && t.Type == "income":
+				payments += t.Amount
+			}
+		}
+		st.StatementBalance = round2(math.Max(0, st.OpeningBalance+st.Purchases-st.Credits))
+		st.OpeningBalance = round2(st.OpeningBalance)
+		st.Purchases, st.Credits = round2(st.Purchases), round2(st.Credits)
+		st.MinimumPayment = s.minPayment(w, st.StatementBalance)
+
+		if i == 0 {
+			st.Status = StatementOpen
+			st.Remaining = st.StatementBalance
+		} else {
+			st.Paid = round2(math.Min(st.StatementBalance, payments))
+			st.Remaining = round2(st.StatementBalance - st.Paid)
+			st.Status = statementStatus(st, now)
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+func (s *StatementService) minPayment(w *ports.Wallet, bal float64) float64 {
+	rate := s.MinPaymentRate
+	if w.CreditLimit != nil && *w.CreditLimit > s.HighLimit {
+		rate = s.MinPaymentRateHigh
+	}
+	return round2(bal * rate)
+}
+
+func statementStatus(st CardStatement, now time.Time) string {
+	switch {
+	case st.Remaining <= 0:
+		return StatementPaid
+	case now.After(st.DueDate.AddDate(0, 0, 1)) && st.Paid < st.MinimumPayment:
+		return StatementOverdue
+	case st.Paid > 0:
+		return StatementPartiallyPaid
+	default:
+		return StatementUnpaid
+	}
+}
+
+func debtDelta(t ports.Transaction) float64 {
+	if t.Type == "expense" {
+		return t.Amount
+	}
+	return -t.Amount
+}
+
+type cardCycle struct{ closeDay, dueDay int }
+
+// closeOn: verilen ayın kesim günü (ay kısaysa son gün).
+func (c cardCycle) closeOn(y int, m time.Month) time.Time {
+	return time.Date(y, m, clampDay(y, m, c.closeDay), 0, 0, 0, 0, time.UTC)
+}
+
+// periodEnd: kesim gününün bittiği an (dönem sonu, hariç).
+func (c cardCycle) periodEnd(y int, m time.Month) time.Time {
+	return c.closeOn(y, m).AddDate(0, 0, 1)
+}
+
+// dueOn: son ödeme günü kesimden sonraysa aynı ay, değilse bir sonraki ay.
+func (c cardCycle) dueOn(y int, m time.Month) time.Time {
+	if c.dueDay <= c.closeDay {
+		y, m = addMonths(y, m, 1)
+	}
+	return time.Date(y, m, clampDay(y, m, c.dueDay), 0, 0, 0, 0, time.UTC)
+}
+
+func clampDay(y int, m time.Month, d int) int {
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if d > last {
+		return last
+	}
+	return d
+}
+
+func addMonths(y int, m time.Month, n int) (int, time.Month) {
+	t := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).AddDate(0, n, 0)
+	return t.Year(), t.Month()
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
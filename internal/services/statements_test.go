@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func day(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) }
+
+func TestStatements_Cycles(t *testing.T) {
+	closeDay, dueDay, limit := 15, 25, 20000.0
+	wr := &fakeWalletRepo{rows: []ports.Wallet{{
+		ID: 7, Name: "Kart", Kind: ports.WalletCreditCard,
+		CreditLimit: &limit, StatementDay: &closeDay, DueDay: &dueDay,
+	}}}
+	txr := &fakeTxRepo{rows: []ports.Transaction{
+		{WalletID: 7, Type: "expense", Amount: 1000, OccurredAt: day(2026, 1, 5)},
+		{WalletID: 7, Type: "income", Amount: 1000, OccurredAt: day(2026, 1, 20)},
+		{WalletID: 7, Type: "expense", Amount: 500, OccurredAt: day(2026, 2, 1)},
+		{WalletID: 7, Type: "income", Amount: 100, OccurredAt: day(2026, 2, 20)},
+		{WalletID: 7, Type: "expense", Amount: 200, OccurredAt: day(2026, 3, 1)},
+		{WalletID: 9, Type: "expense", Amount: 999, OccurredAt: day(2026, 3, 1)},
+	}}
+	s := &StatementService{Wallets: wr, Tx: txr, Now: func() time.Time { return day(2026, 3, 10) }}
+
+	rows, err := s.List(1, 7, 2)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("want 3 periods, got %d", len(rows))
+	}
+	jan, feb, open := rows[0], rows[1], rows[2]
+
+	if !jan.PeriodEnd.Equal(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)) ||
+		!jan.DueDate.Equal(time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("jan dates wrong: %+v", jan)
+	}
+	if jan.StatementBalance != 1000 || jan.Status != StatementPaid {
+		t.Fatalf("jan wrong: %+v", jan)
+	}
+	if feb.StatementBalance != 500 || feb.MinimumPayment != 100 || feb.Paid != 100 ||
+		feb.Remaining != 400 || feb.Status != StatementPartiallyPaid {
+		t.Fatalf("feb wrong: %+v", feb)
+	}
+	if open.Status != StatementOpen || open.StatementBalance != 600 {
+		t.Fatalf("open wrong: %+v", open)
+	}
+}
+
+func TestStatements_RequiresCardCycle(t *testing.T) {
+	wr := &fakeWalletRepo{rows: []ports.Wallet{{ID: 1, Kind: ports.WalletCash}}}
+	s := &StatementService{Wallets: wr, Tx: &fakeTxRepo{}}
+	if _, err := s.List(1, 1, 3); err == nil {
+		t.Fatalf("expected not_a_credit_card")
+	}
+}
+
+func TestCardCycle_ClampsShortMonths(t *testing.T) {
+	c := cardCycle{closeDay: 31, dueDay: 10}
+	if got := c.closeOn(2026, time.February); got.Day() != 28 {
+		t.Fatalf("want 28, got %d", got.Day())
+	}
+	if got := c.dueOn(2026, time.January); got.Month() != time.February || got.Day() != 10 {
+		t.Fatalf("due should roll to next month: %v", got)
+	}
+}
+
+func TestStatements_CarriesBalanceBeforeWindow(t *testing.T) {
+	closeDay, dueDay := 15, 25
+	wr := &fakeWalletRepo{rows: []ports.Wallet{{
+		ID: 7, Kind: ports.WalletCreditCard, StatementDay: &closeDay, DueDay: &dueDay,
+	}}}
+	txr := &fakeTxRepo{rows: []ports.Transaction{
+		{WalletID: 7, Type: "expense", Amount: 300, OccurredAt: day(2025, 11, 20)},
+		{WalletID: 7, Type: "expense", Amount: 100, OccurredAt: day(2026, 2, 1)},
+		{WalletID: 7, Type: "expense", Amount: 999, OccurredAt: day(2026, 3, 12), Status: ports.TxScheduled},
+	}}
+	s := &StatementService{Wallets: wr, Tx: txr, Now: func() time.Time { return day(2026, 3, 10) }}
+
+	rows, err := s.List(1, 7, 1)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if rows[0].OpeningBalance != 300 || rows[0].StatementBalance != 400 {
+		t.Fatalf("carry wrong: %+v", rows[0])
+	}
+	if rows[1].StatementBalance != 400 {
+		t.Fatalf("scheduled charge counted in open period: %+v", rows[1])
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const (
+	// subLookback tespit penceresi: yıllık ödemelerin en az iki tekrarını görecek kadar geniş.
+	subLookback   = 25 * 30 * 24 * time.Hour
+	subMinCharges = 3
+	// subAmountTol tutarların medyandan en fazla bu oranda sapması "benzer tutar" sayılır.
+	subAmountTol = 0.25
+	// subPriceEps bu orandan küçük değişiklikler fiyat değişikliği sayılmaz (kur/yuvarlama).
+	subPriceEps = 0.01
+)
+
+type cadence struct {
+	name    string
+	days    float64
+	tol     float64
+	perYear float64
+	next    func(time.Time) time.Time
+}
+
+var cadences = []cadence{
+	{ports.CadenceWeekly, 7, 2, 52, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }},
+	{ports.CadenceBiweekly, 14, 3, 26, func(t time.Time) time.Time { return t.AddDate(0, 0, 14) }},
+	{ports.CadenceMonthly, 30.4, 4, 12, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{ports.CadenceQuarterly, 91, 10, 4, func(t time.Time) time.Time { return t.AddDate(0, 3, 0) }},
+	{ports.CadenceYearly, 365, 15, 1, func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+type SubscriptionService struct {
+	Repo ports.SubscriptionRepo
+
+	Now func() time.Time
+}
+
+func (s *SubscriptionService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// List kayıtlı tespitleri döner; durum (active/lapsed) okuma anına göre güncellenir.
+func (s *SubscriptionService) List(uid int64) ([]ports.Subscription, error) {
+	rows, err := s.Repo.List(uid)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for i := range rows {
+		rows[i].Status = subStatus(rows[i], now)
+	}
+	return rows, nil
+}
+
+// Scan yalnızca son taramadan sonra işlemi değişen kullanıcıları yeniden tarar;
+// taranan kullanıcı sayısını döner.
+func (s *SubscriptionService) Scan() (int, error) {
+	ids, err := s.Repo.ChangedUsers()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		n       int
+		lastErr error
+	)
+	for _, uid := range ids {
+		if err := s.ScanUser(uid); err != nil {
+			lastErr = err
+			continue
+		}
+		n++
+	}
+	return n, lastErr
+}
+
+func (s *SubscriptionService) ScanUser(uid int64) error {
+	now := s.now()
+	txs, err := s.Repo.Expenses(uid, now.Add(-subLookback))
+	if err != nil {
+		return err
+	}
+	return s.Repo.Replace(uid, DetectSubscriptions(txs, now), now)
+}
+
+// DetectSubscriptions aynı (normalize) not ve para birimiyle, benzer tutarda ve düzenli
+// aralıklarla tekrar eden giderleri bulur. txs occurred_at artan sırada olmalıdır.
+func DetectSubscriptions(txs []ports.Transaction, now time.Time) []ports.Subscription {
+	groups := map[string][]ports.Transaction{}
+	var keys []string
+	for _, t := range txs {
+		if t.Type != "expense" || t.Note == nil {
+			continue
+		}
+		k := subKey(*t.Note)
+		if k == "" {
+			continue
+		}
+		k += "|" + strings.ToUpper(t.Currency)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+	sort.Strings(keys)
+
+	out := []ports.Subscription{}
+	for _, k := range keys {
+		if sub, ok := detectOne(k, groups[k], now); ok {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+func detectOne(key string, g []ports.Transaction, now time.Time) (ports.Subscription, bool) {
+	if len(g) < subMinCharges {
+		return ports.Subscription{}, false
+	}
+	amounts := make([]float64, len(g))
+	for i, t := range g {
+		amounts[i] = t.Amount
+	}
+	med := median(amounts)
+	for _, a := range amounts {
+		if med <= 0 || math.Abs(a-med)/med > subAmountTol {
+			return ports.Subscription{}, false
+		}
+	}
+
+	gaps := make([]float64, len(g)-1)
+	for i := 1; i < len(g); i++ {
+		gaps[i-1] = g[i].OccurredAt.Sub(g[i-1].OccurredAt).Hours() / 24
+	}
+	gap := median(gaps)
+	var c *cadence
+	for i := range cadences {
+		if math.Abs(gap-cadences[i].days) <= cadences[i].tol {
+			c = &cadences[i]
+			break
+		}
+	}
+	if c == nil {
+		return ports.Subscription{}, false
+	}
+	// Aralıkların çoğu (en az %75) kadansa uymalı; tek bir kaçırılmış/erken ödeme tolere edilir.
+	var fit int
+	for _, d := range gaps {
+		if math.Abs(d-c.days) <= c.tol {
+			fit++
+		}
+	}
+	if float64(fit) < 0.75*float64(len(gaps)) {
+		return ports.Subscription{}, false
+	}
+
+	last := g[len(g)-1]
+	var sum float64
+	for _, a := range amounts {
+		sum += a
+	}
+	avg := round2(sum / float64(len(amounts)))
+	sub := ports.Subscription{
+		Key: key, Name: strings.TrimSpace(*last.Note), Currency: strings.ToUpper(last.Currency), Cadence: c.name,
+		Charges: len(g), AvgAmount: avg, LastAmount: last.Amount, LastCharge: last.OccurredAt,
+		NextExpected: dayOf(c.next(last.OccurredAt)),
+		// Yıllık maliyet güncel fiyatla: fiyat değiştiyse ileriye dönük tutar son ödemedir.
+		Annualized: round2(last.Amount * c.perYear),
+	}
+	wid, cid := last.WalletID, last.CategoryID
+	sub.WalletID, sub.CategoryID = &wid, &cid
+
+	prev := g[len(g)-2]
+	if prev.Amount > 0 && math.Abs(last.Amount-prev.Amount)/prev.Amount > subPriceEps {
+		pa, at := prev.Amount, last.OccurredAt
+		sub.PrevAmount, sub.ChangedAt = &pa, &at
+	}
+	sub.Status = subStatus(sub, now)
+	return sub, true
+}
+
+// subStatus beklenen tarihten kadans toleransı kadar sonra hâlâ ödeme yoksa lapsed.
+func subStatus(s ports.Subscription, now time.Time) string {
+	grace := 3 * 24 * time.Hour
+	for _, c := range cadences {
+		if c.name == s.Cadence {
+			grace = time.Duration(c.tol*24) * time.Hour
+		}
+	}
+	if now.After(s.NextExpected.Add(grace)) {
+		return ports.SubLapsed
+	}
+	return ports.SubActive
+}
+
+// subKey notu gruplamak için normalize eder: küçük harf, rakam/noktalama atılır
+// ("Netflix 03/2026 #1234" → "netflix").
+func subKey(note string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(note) {
+		switch {
+		case unicode.IsLetter(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	if rs := []rune(b.String()); len(rs) > 100 {
+		return string(rs[:100])
+	}
+	return b.String()
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	m := len(s) / 2
+	if len(s)%2 == 1 {
+		return s[m]
+	}
+	return (s[m-1] + s[m]) / 2
+}
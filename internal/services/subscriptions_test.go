@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+func charge(note string, amount float64, at time.Time) ports.Transaction {
+	return ports.Transaction{Type: "expense", Note: &note, Amount: amount, Currency: "TRY", OccurredAt: at, WalletID: 2, CategoryID: 9}
+}
+
+func TestDetectSubscriptions_MonthlyWithPriceChange(t *testing.T) {
+	txs := []ports.Transaction{
+		charge("NETFLIX 01/2026", 149.99, day(2026, 1, 5)),
+		charge("Market alışverişi", 820, day(2026, 1, 9)),
+		charge("Netflix 02/2026", 149.99, day(2026, 2, 5)),
+		charge("Market alışverişi", 95, day(2026, 2, 20)),
+		charge("netflix #3391", 149.99, day(2026, 3, 6)),
+		charge("Market alışverişi", 430, day(2026, 3, 28)),
+		charge("Netflix 04/2026", 179.99, day(2026, 4, 5)),
+	}
+	subs := DetectSubscriptions(txs, day(2026, 4, 20))
+	if len(subs) != 1 {
+		t.Fatalf("want only netflix, got %+v", subs)
+	}
+	s := subs[0]
+	if s.Key != "netflix|TRY" || s.Cadence != ports.CadenceMonthly || s.Charges != 4 || s.Status != ports.SubActive {
+		t.Fatalf("bad detection: %+v", s)
+	}
+	if !s.NextExpected.Equal(time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC)) || s.Annualized != 2159.88 {
+		t.Fatalf("bad projection: next=%s annual=%v", s.NextExpected, s.Annualized)
+	}
+	if s.PrevAmount == nil || *s.PrevAmount != 149.99 {
+		t.Fatalf("price change not flagged: %+v", s)
+	}
+}
+
+func TestDetectSubscriptions_IrregularAndLapsed(t *testing.T) {
+	irregular := []ports.Transaction{
+		charge("Taksi", 100, day(2026, 1, 1)),
+		charge("Taksi", 100, day(2026, 1, 3)),
+		charge("Taksi", 100, day(2026, 2, 20)),
+	}
+	if subs := DetectSubscriptions(irregular, day(2026, 3, 1)); len(subs) != 0 {
+		t.Fatalf("irregular charges must not be a subscription: %+v", subs)
+	}
+
+	weekly := []ports.Transaction{
+		charge("Gym", 50, day(2026, 1, 1)),
+		charge("Gym", 50, day(2026, 1, 8)),
+		charge("Gym", 50, day(2026, 1, 15)),
+	}
+	subs := DetectSubscriptions(weekly, day(2026, 2, 15))
+	if len(subs) != 1 || subs[0].Cadence != ports.CadenceWeekly || subs[0].Status != ports.SubLapsed {
+		t.Fatalf("want lapsed weekly, got %+v", subs)
+	}
+}
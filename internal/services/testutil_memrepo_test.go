@@ -40,6 +40,33 @@ func (m *memAuthRepo) FindUserByEmail(email string) (*ports.User, error) {
 	return nil, ErrNotFound
 }
 
+func (m *memAuthRepo) FindUserByID(id int64) (*ports.User, error) {
+	for _, u := range m.users {
+		if u.ID == id {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+func (m *memAuthRepo) SetPassword(userID int64, passHash string) error {
+	for _, u := range m.users {
+		if u.ID == userID {
+			u.PassHash = passHash
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *memAuthRepo) UseTotpStep(int64, int64) (bool, error)                 { return true, nil }
+func (m *memAuthRepo) ReplaceRecoveryCodes(int64, []string) error             { return nil }
+func (m *memAuthRepo) UseRecoveryCode(int64, string, time.Time) (bool, error) { return false, nil }
+func (m *memAuthRepo) DeleteTotp(userID int64) error {
+	delete(m.totp, userID)
+	return nil
+}
+
 var ErrNotFound = errString("not_found")
 
 type errString string
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/security"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpPeriod = 30
+	// recoveryCodeCount onayda ve yenilemede üretilen tek kullanımlık kod sayısı.
+	recoveryCodeCount = 10
+)
+
+var totpOpts = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// totpStep kodu ±1 adım kaymayla doğrular ve eşleşen zaman adımını döner.
+func totpStep(secret, code string, now time.Time) (int64, bool) {
+	for _, off := range []int64{0, -1, 1} {
+		t := now.Add(time.Duration(off*totpPeriod) * time.Second)
+		want, err := totp.GenerateCodeCustom(secret, t, totpOpts)
+		if err == nil && subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+func isTotpCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// verifySecondFactor TOTP etkinse kodu ister. Altı haneli kod zaman adımıyla, diğerleri tek
+// kullanımlık kurtarma kodu olarak denenir; recovery kurtarma kodu harcandıysa true olur.
+func verifySecondFactor(repo ports.AuthRepo, uid int64, code string, now time.Time) (recovery bool, err error) {
+	ts, err := repo.GetTotp(uid)
+	if err != nil {
+		return false, err
+	}
+	if ts == nil || ts.ConfirmedAt == nil {
+		return false, nil
+	}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, errs.TOTPRequired
+	}
+	if isTotpCode(code) {
+		step, ok := totpStep(ts.Secret, code, now)
+		if !ok {
+			return false, errs.TOTPInvalid
+		}
+		// Aynı ya da daha eski adım reddedilir: pencere içinde aynı kod ikinci kez geçmez.
+		fresh, err := repo.UseTotpStep(uid, step)
+		if err != nil {
+			return false, err
+		}
+		if !fresh {
+			return false, errs.TOTPInvalid
+		}
+		return false, nil
+	}
+	ok, err := repo.UseRecoveryCode(uid, hashRecoveryCode(code), now)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, errs.TOTPInvalid
+	}
+	return true, nil
+}
+
+// newRecoveryCodes "xxxx-xxxx-xxxx-xxxx" biçiminde 80 bit rastgele kodlar ve özetlerini üretir.
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return security.SHA256Hex(code)
+}
+
+func (s *AuthService) replaceRecoveryCodes(uid int64) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ReplaceRecoveryCodes(uid, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// reauthTotp parola ve geçerli ikinci faktörle yeniden doğrulama; TOTP kapalıysa hata döner.
+func (s *AuthService) reauthTotp(uid int64, password, code string) error {
+	u, err := s.Repo.FindUserByID(uid)
+	if err != nil {
+		return err
+	}
+	if ok, _ := security.ArgonCheck(password, u.PassHash); !ok {
+		return errs.InvalidCredentials
+	}
+	if ts, _ := s.Repo.GetTotp(uid); ts == nil || ts.ConfirmedAt == nil {
+		return errs.ValidationFailed("totp_not_enabled")
+	}
+	recovery, err := verifySecondFactor(s.Repo, uid, code, time.Now())
+	if err != nil {
+		return err
+	}
+	if recovery && s.Audit != nil {
+		s.Audit.Log(uid, "auth.totp.recovery_used", "user", &uid, nil)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes eski kodları geçersiz kılıp yenilerini bir kez gösterilmek üzere döner.
+func (s *AuthService) RegenerateRecoveryCodes(uid int64, password, code string) ([]string, error) {
+	if err := s.reauthTotp(uid, password, code); err != nil {
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(uid)
+	if err != nil {
+		return nil, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.totp.recovery_regenerate", "user", &uid, nil)
+	}
+	return codes, nil
+}
+
+// DisableTotp parola ve geçerli kod (ya da kurtarma kodu) ile iki adımlı doğrulamayı kapatır.
+func (s *AuthService) DisableTotp(uid int64, password, code string) error {
+	if err := s.reauthTotp(uid, password, code); err != nil {
+		return err
+	}
+	if err := s.Repo.DeleteTotp(uid); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "auth.totp.disable", "user", &uid, nil)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/pquerna/otp/totp"
+)
+
+func enableTotp(t *testing.T, s *AuthService, uid int64) (string, []string) {
+	t.Helper()
+	secret, _, err := s.TotpSetup(uid, "v@e.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Onay kodu adımı tüketir; sonraki denemeler bir önceki adımın kodunu kullanamaz.
+	code, _ := totp.GenerateCode(secret, time.Now().Add(-30*time.Second))
+	codes, err := s.TotpConfirm(uid, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret, codes
+}
+
+func TestTotp_RecoveryCodesAtLogin(t *testing.T) {
+	s, repo := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	_, codes := enableTotp(t, s, uid)
+	if len(codes) != 10 || len(repo.recovery[uid]) != 10 {
+		t.Fatalf("want 10 recovery codes, got %d/%d", len(codes), len(repo.recovery[uid]))
+	}
+	for h := range repo.recovery[uid] {
+		if h == codes[0] {
+			t.Fatalf("recovery codes must be stored hashed")
+		}
+	}
+
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", codes[0], "ua", "ip", ""); err != nil {
+		t.Fatalf("login with recovery code: %v", err)
+	}
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", codes[0], "ua", "ip2", ""); err == nil {
+		t.Fatalf("recovery code reused")
+	}
+
+	fresh, err := s.RegenerateRecoveryCodes(uid, "A1complex!", codes[1])
+	if err != nil || len(fresh) != 10 {
+		t.Fatalf("regenerate: %v", err)
+	}
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", codes[2], "ua", "ip3", ""); err == nil {
+		t.Fatalf("old codes must be invalid after regeneration")
+	}
+}
+
+func TestTotp_ReplayRejected(t *testing.T) {
+	s, _ := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	secret, _ := enableTotp(t, s, uid)
+	code, _ := totp.GenerateCode(secret, time.Now())
+
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", code, "ua", "ip", ""); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", code, "ua", "ip2", ""); err == nil {
+		t.Fatalf("same code accepted twice")
+	}
+}
+
+func TestTotp_DisableRequiresPasswordAndCode(t *testing.T) {
+	s, repo := newSvc()
+	uid := mustRegister(t, s, "v@e.com", "A1complex!")
+	_, codes := enableTotp(t, s, uid)
+
+	if _, _, err := s.TotpSetup(uid, "v@e.com"); err != errs.Conflict {
+		t.Fatalf("setup must not replace an active secret: %v", err)
+	}
+	if err := s.DisableTotp(uid, "wrong-pass", codes[0]); err != errs.InvalidCredentials {
+		t.Fatalf("password: %v", err)
+	}
+	if err := s.DisableTotp(uid, "A1complex!", "000000"); err != errs.TOTPInvalid {
+		t.Fatalf("code: %v", err)
+	}
+	if err := s.DisableTotp(uid, "A1complex!", codes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if repo.totp[uid] != nil || len(repo.recovery[uid]) != 0 {
+		t.Fatalf("totp not removed")
+	}
+	if _, _, _, err := s.Login("v@e.com", "A1complex!", "", "", "", "ua", "ip", ""); err != nil {
+		t.Fatalf("login without totp after disable: %v", err)
+	}
+	if err := s.DisableTotp(uid, "A1complex!", codes[1]); !failedWith(err, "totp_not_enabled") {
+		t.Fatalf("disable twice: %v", err)
+	}
+}
@@ -1,18 +1,183 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/Veysel440/finance-master-api/internal/errs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
+	"github.com/Veysel440/finance-master-api/internal/txquery"
 )
 
+// WalletAccess işlem yazılmadan önce kullanıcının cüzdana yazma yetkisini doğrular
+// (kişisel cüzdan sahibi ya da hane cüzdanında editor/owner).
+type WalletAccess interface {
+	CanWrite(uid, walletID int64) error
+}
+
 type TxService struct {
-	Repo  ports.TxRepo
-	Audit *AuditService
-	Idem  ports.IdempotencyRepo
+	Repo   ports.TxRepo
+	Cats   ports.CategoryRepo
+	Audit  *AuditService
+	Idem   ports.IdempotencyRepo
+	Access WalletAccess
+	// NetWorth geçmiş tarihli değişikliklerde net değer görüntülerini bayat işaretler.
+	NetWorth StaleMarker
+
+	Now func() time.Time
+}
+
+const (
+	// autoPostBatch otomatik kayıtta tek seferde işlenen planlanmış işlem sayısı.
+	autoPostBatch   = 500
+	maxUpcomingDays = 365
+)
+
+func (s *TxService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// statusFor durum verilmemiş yeni işlemin durumu: ileri tarihli işlem planlanmış, diğerleri pending.
+// Taksit, borç ödemesi ve grup defteri satırları da bu kuralla yazılır.
+func statusFor(at, now time.Time) string {
+	if at.After(now) {
+		return ports.TxScheduled
+	}
+	return ports.TxPending
+}
+
+// initialStatus yeni işlemin durumunu belirler; planlanmış işlem geçmiş tarihli olamaz.
+func (s *TxService) initialStatus(t *ports.Transaction) error {
+	switch {
+	case t.Status == "":
+		t.Status = statusFor(t.OccurredAt, s.now())
+	case t.Status == ports.TxScheduled && !t.OccurredAt.After(s.now()):
+		return errs.ValidationFailed("scheduled_not_in_future")
+	}
+	return nil
+}
+
+// keptStatus durum gönderilmeden güncellenen kaydın durumunu belirler: ileri tarihe alınan
+// kayıt planlanmış olur, geçmişe alınan planlanmış kayıt pending'e döner, diğerleri korunur.
+func keptStatus(old *ports.Transaction, at, now time.Time) string {
+	if at.After(now) {
+		return ports.TxScheduled
+	}
+	if old.Status == ports.TxScheduled {
+		return ports.TxPending
+	}
+	return old.Status
+}
+
+func (s *TxService) markStale(uid int64, dates ...time.Time) {
+	if s.NetWorth == nil || len(dates) == 0 {
+		return
+	}
+	since := dates[0]
+	for _, d := range dates[1:] {
+		if d.Before(since) {
+			since = d
+		}
+	}
+	_ = s.NetWorth.MarkStale(uid, since)
+}
+
+// current güncelleme/silme öncesi kaydı döner; reconciled işlemler kilitlidir.
+// Kayıt yoksa (nil, nil): repo davranışı korunur.
+func (s *TxService) current(uid, id int64) (*ports.Transaction, error) {
+	old, err := s.Repo.GetOne(uid, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && old.Status == ports.TxReconciled {
+		return nil, errs.TxLocked
+	}
+	return old, nil
+}
+
+// checkStatus istemcinin yazabileceği durumları doğrular; reconciled yalnızca kilitlemeyle atanır.
+func checkStatus(status string) error {
+	switch status {
+	case "", ports.TxScheduled, ports.TxPending, ports.TxCleared:
+		return nil
+	}
+	return errs.ValidationFailed("bad_status")
+}
+
+// mergeTax gönderilmeyen vergi alanlarını kayıttan alır. Oran korunup vergi tutarı
+// gönderilmemişse checkTax yeni tutar üzerinden yeniden hesaplar.
+func mergeTax(t, old *ports.Transaction) {
+	if old == nil {
+		return
+	}
+	if t.TaxRate == nil && t.TaxAmount == nil {
+		t.TaxRate = old.TaxRate
+	}
+	if t.Deductible == nil {
+		t.Deductible = old.Deductible
+	}
+}
+
+// checkTax KDV alanlarını doğrular. Tutar KDV dahildir: vergi = tutar*oran/(100+oran).
+// Oran verilip vergi verilmezse hesaplanır; bir kuruşluk yuvarlama farkı kabul edilir.
+func checkTax(t *ports.Transaction) error {
+	if t.Deductible != nil && *t.Deductible && t.Type != "expense" {
+		return errs.ValidationFailed("deductible_expense_only")
+	}
+	if t.TaxRate == nil {
+		if t.TaxAmount != nil {
+			return errs.ValidationFailed("tax_rate_required")
+		}
+		return nil
+	}
+	rate := *t.TaxRate
+	if rate < 0 || rate > 100 {
+		return errs.ValidationFailed("bad_tax_rate")
+	}
+	want := round2(t.Amount * rate / (100 + rate))
+	if t.TaxAmount == nil {
+		t.TaxAmount = &want
+		return nil
+	}
+	if *t.TaxAmount < 0 || math.Round(math.Abs(*t.TaxAmount-want)*100) > 1 {
+		return errs.ValidationFailed("tax_amount_mismatch")
+	}
+	return nil
+}
+
+func (s *TxService) canWrite(uid int64, walletIDs ...int64) error {
+	if s.Access == nil {
+		return nil
+	}
+	seen := map[int64]bool{}
+	for _, id := range walletIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if err := s.Access.CanWrite(uid, id); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *TxService) CreateIdem(uid int64, key string, t *ports.Transaction) error {
+	if err := checkStatus(t.Status); err != nil {
+		return err
+	}
+	if err := checkTax(t); err != nil {
+		return err
+	}
 	if s.Idem != nil && key != "" {
 		if rid, ok, err := s.Idem.Get(uid, key, "transaction"); err == nil && ok {
 			exist, err := s.Repo.GetOne(uid, rid)
@@ -22,12 +187,19 @@ func (s *TxService) CreateIdem(uid int64, key string, t *ports.Transaction) erro
 			}
 		}
 	}
+	if err := s.canWrite(uid, t.WalletID); err != nil {
+		return err
+	}
+	if err := s.initialStatus(t); err != nil {
+		return err
+	}
 	if err := s.Repo.Create(uid, t); err != nil {
 		return err
 	}
 	if s.Idem != nil && key != "" {
 		_ = s.Idem.Save(uid, key, "transaction", t.ID)
 	}
+	s.markStale(uid, t.OccurredAt)
 	if s.Audit != nil {
 		s.Audit.Log(uid, "tx.create", "transaction", &t.ID, map[string]any{"amount": t.Amount, "currency": t.Currency, "type": t.Type})
 	}
@@ -35,25 +207,83 @@ func (s *TxService) CreateIdem(uid int64, key string, t *ports.Transaction) erro
 }
 
 func (s *TxService) Create(uid int64, t *ports.Transaction) error {
+	if err := checkStatus(t.Status); err != nil {
+		return err
+	}
+	if err := checkTax(t); err != nil {
+		return err
+	}
+	if err := s.canWrite(uid, t.WalletID); err != nil {
+		return err
+	}
+	if err := s.initialStatus(t); err != nil {
+		return err
+	}
 	if err := s.Repo.Create(uid, t); err != nil {
 		return err
 	}
+	s.markStale(uid, t.OccurredAt)
 	s.alog(uid, "tx.create", t)
 	return nil
 }
 
 func (s *TxService) Update(uid int64, t *ports.Transaction) error {
+	if err := checkStatus(t.Status); err != nil {
+		return err
+	}
+	old, err := s.current(uid, t.ID)
+	if err != nil {
+		return err
+	}
+	mergeTax(t, old)
+	if err := checkTax(t); err != nil {
+		return err
+	}
+	// Kayıt başka cüzdana taşınıyorsa hem eski hem yeni cüzdana yazma yetkisi gerekir.
+	wallets := []int64{t.WalletID}
+	if old != nil {
+		wallets = append(wallets, old.WalletID)
+	}
+	if err := s.canWrite(uid, wallets...); err != nil {
+		return err
+	}
+	dates := []time.Time{t.OccurredAt}
+	if t.Status == ports.TxScheduled && !t.OccurredAt.After(s.now()) {
+		return errs.ValidationFailed("scheduled_not_in_future")
+	}
+	if old != nil {
+		dates = append(dates, old.OccurredAt)
+		if t.Status == "" {
+			t.Status = keptStatus(old, t.OccurredAt, s.now())
+		}
+	}
+	if t.Status == "" {
+		t.Status = ports.TxPending
+	}
 	if err := s.Repo.Update(uid, t); err != nil {
 		return err
 	}
+	s.markStale(uid, dates...)
 	s.alog(uid, "tx.update", t)
 	return nil
 }
 
 func (s *TxService) Delete(uid, id int64) error {
+	old, err := s.current(uid, id)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		if err := s.canWrite(uid, old.WalletID); err != nil {
+			return err
+		}
+	}
 	if err := s.Repo.SoftDelete(uid, id); err != nil {
 		return err
 	}
+	if old != nil {
+		s.markStale(uid, old.OccurredAt)
+	}
 	if s.Audit != nil {
 		s.Audit.Log(uid, "tx.delete", "transaction", &id, nil)
 	}
@@ -61,21 +291,112 @@ func (s *TxService) Delete(uid, id int64) error {
 }
 
 func (s *TxService) UpsertBatch(uid int64, items []ports.Transaction) error {
+	ids := make([]int64, 0, len(items))
+	dates := make([]time.Time, 0, len(items))
+	for i := range items {
+		if err := checkStatus(items[i].Status); err != nil {
+			return err
+		}
+		ids = append(ids, items[i].WalletID)
+		dates = append(dates, items[i].OccurredAt)
+		if items[i].ID > 0 {
+			// Mevcut kaydın cüzdanı da yazılabilir olmalı; reconciled kayıt kilitlidir.
+			old, err := s.current(uid, items[i].ID)
+			if err != nil {
+				return err
+			}
+			if old != nil {
+				ids = append(ids, old.WalletID)
+				dates = append(dates, old.OccurredAt)
+				if items[i].Status == "" && items[i].DeletedAt == nil {
+					items[i].Status = keptStatus(old, items[i].OccurredAt, s.now())
+				} else if items[i].Status == ports.TxScheduled && !items[i].OccurredAt.After(s.now()) {
+					return errs.ValidationFailed("scheduled_not_in_future")
+				}
+			}
+			mergeTax(&items[i], old)
+		}
+		if err := checkTax(&items[i]); err != nil {
+			return err
+		}
+		if items[i].ID == 0 && items[i].DeletedAt == nil {
+			if err := s.initialStatus(&items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.canWrite(uid, ids...); err != nil {
+		return err
+	}
 	if err := s.Repo.UpsertBatch(uid, items); err != nil {
 		return err
 	}
+	s.markStale(uid, dates...)
 	if s.Audit != nil {
 		s.Audit.Log(uid, "tx.upsert_batch", "transaction", nil, map[string]any{"count": len(items)})
 	}
 	return nil
 }
 
+// SetStatus işlemleri pending/cleared olarak işaretler; reconciled olanlara dokunmaz.
+func (s *TxService) SetStatus(uid int64, ids []int64, status string) (int64, error) {
+	if status != ports.TxPending && status != ports.TxCleared {
+		return 0, errs.ValidationFailed("bad_status")
+	}
+	if len(ids) == 0 {
+		return 0, errs.ValidationFailed("ids_required")
+	}
+	n, err := s.Repo.SetStatus(uid, ids, status)
+	if err != nil {
+		return 0, err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "tx.status", "transaction", nil, map[string]any{"status": status, "count": n})
+	}
+	return n, nil
+}
+
 func (s *TxService) List(uid int64, page, size int, q string) ([]ports.Transaction, int, error) {
+	if err := checkQuery(q); err != nil {
+		return nil, 0, err
+	}
 	return s.Repo.List(uid, page, size, q)
 }
 
-func (s *TxService) Summary(uid int64, from, to time.Time) ([]ports.TxSummary, error) {
-	return s.Repo.Summary(uid, from, to)
+// checkQuery arama sorgusunu repo'ya gitmeden doğrular; sözdizimi hatası konumuyla döner.
+func checkQuery(q string) error {
+	s := strings.TrimSpace(q)
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "ft:") {
+		return nil
+	}
+	if _, _, err := txquery.Build(s); err != nil {
+		return errs.ValidationFailed("q: " + err.Error())
+	}
+	return nil
+}
+
+func (s *TxService) Summary(uid int64, from, to time.Time, includeScheduled bool) ([]ports.TxSummary, error) {
+	return s.Repo.Summary(uid, from, to, includeScheduled)
+}
+
+// SummaryByCategory kategori toplamlarını döner; rollup açıksa ebeveynler
+// alt kategorilerinin toplamını da içerir.
+func (s *TxService) SummaryByCategory(uid int64, from, to time.Time, rollup, includeScheduled bool) ([]ports.CategoryTotal, error) {
+	rows, err := s.Repo.SummaryByCategory(uid, from, to, includeScheduled)
+	if err != nil {
+		return nil, err
+	}
+	if !rollup || s.Cats == nil {
+		for i := range rows {
+			rows[i].Own = rows[i].Total
+		}
+		return rows, nil
+	}
+	cats, err := s.Cats.List(uid, "")
+	if err != nil {
+		return nil, err
+	}
+	return RollupCategoryTotals(cats, rows), nil
 }
 
 func (s *TxService) GetOne(uid, id int64) (*ports.Transaction, error) {
@@ -83,12 +404,62 @@ func (s *TxService) GetOne(uid, id int64) (*ports.Transaction, error) {
 }
 
 func (s *TxService) ListRange(uid int64, from, to time.Time, q string, page, size int) ([]ports.Transaction, int, error) {
+	if err := checkQuery(q); err != nil {
+		return nil, 0, err
+	}
 	return s.Repo.ListRange(uid, from, to, q, page, size)
 }
 func (s *TxService) Since(uid int64, since time.Time) ([]ports.Transaction, error) {
 	return s.Repo.GetSince(uid, since)
 }
 
+// Upcoming önümüzdeki days gün içindeki planlanmış işlemleri döner.
+func (s *TxService) Upcoming(uid int64, days int) ([]ports.Transaction, error) {
+	if days < 1 || days > maxUpcomingDays {
+		return nil, errs.ValidationFailed("bad_days")
+	}
+	now := s.now()
+	return s.Repo.Upcoming(uid, now, now.AddDate(0, 0, days))
+}
+
+// PostDue tarihi gelen planlanmış işlemleri pending'e çeker ve etkilenen kullanıcıların
+// net değer görüntülerini bayat işaretler; kaydedilen işlem sayısını döner.
+func (s *TxService) PostDue() (int, error) {
+	var total int
+	for {
+		due, err := s.Repo.DueScheduled(s.now(), autoPostBatch)
+		if err != nil {
+			return total, err
+		}
+		if len(due) == 0 {
+			return total, nil
+		}
+		ids := make([]int64, len(due))
+		dates := map[int64][]time.Time{}
+		for i, t := range due {
+			ids[i] = t.ID
+			dates[t.UserID] = append(dates[t.UserID], t.OccurredAt)
+		}
+		n, err := s.Repo.PostScheduled(ids)
+		if err != nil {
+			return total, err
+		}
+		total += int(n)
+		if n == 0 {
+			return total, nil
+		}
+		for uid, ds := range dates {
+			s.markStale(uid, ds...)
+			if s.Audit != nil {
+				s.Audit.Log(uid, "tx.autopost", "transaction", nil, map[string]any{"count": len(ds)})
+			}
+		}
+		if len(due) < autoPostBatch {
+			return total, nil
+		}
+	}
+}
+
 func (s *TxService) alog(uid int64, act string, t *ports.Transaction) {
 	if s.Audit == nil || t == nil {
 		return
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Veysel440/finance-master-api/internal/errs"
 	"github.com/Veysel440/finance-master-api/internal/ports"
 )
 
@@ -12,6 +13,11 @@ type fakeTxRepo struct {
 	updated *ports.Transaction
 	deleted int64
 	batch   int
+
+	catTotals []ports.CategoryTotal
+	rows      []ports.Transaction
+	status    string
+	filter    ports.TxFilter
 }
 
 func (r *fakeTxRepo) Create(uid int64, t *ports.Transaction) error { r.created = t; return nil }
@@ -24,10 +30,74 @@ func (r *fakeTxRepo) UpsertBatch(uid int64, items []ports.Transaction) error {
 func (r *fakeTxRepo) List(int64, int, int, string) ([]ports.Transaction, int, error) {
 	return nil, 0, nil
 }
-func (r *fakeTxRepo) Summary(int64, time.Time, time.Time) ([]ports.TxSummary, error) { return nil, nil }
-func (r *fakeTxRepo) GetOne(int64, int64) (*ports.Transaction, error)                { return nil, nil }
+func (r *fakeTxRepo) Summary(int64, time.Time, time.Time, bool) ([]ports.TxSummary, error) {
+	return nil, nil
+}
+func (r *fakeTxRepo) GetOne(_ int64, id int64) (*ports.Transaction, error) {
+	for i := range r.rows {
+		if r.rows[i].ID == id {
+			return &r.rows[i], nil
+		}
+	}
+	return nil, nil
+}
+func (r *fakeTxRepo) SummaryByCategory(int64, time.Time, time.Time, bool) ([]ports.CategoryTotal, error) {
+	return r.catTotals, nil
+}
 func (r *fakeTxRepo) ListRange(int64, time.Time, time.Time, string, int, int) ([]ports.Transaction, int, error) {
-	return nil, 0, nil
+	return r.rows, len(r.rows), nil
+}
+func (r *fakeTxRepo) SetStatus(_ int64, ids []int64, status string) (int64, error) {
+	r.status = status
+	return int64(len(ids)), nil
+}
+func (r *fakeTxRepo) ListWalletRange(walletID int64, from, to time.Time) ([]ports.Transaction, error) {
+	var out []ports.Transaction
+	for _, t := range r.rows {
+		if t.WalletID == walletID && t.Status != ports.TxScheduled && !t.OccurredAt.Before(from) && t.OccurredAt.Before(to) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+func (r *fakeTxRepo) WalletBalanceBefore(walletID int64, before time.Time) (float64, error) {
+	var bal float64
+	for _, t := range r.rows {
+		if t.WalletID == walletID && t.Status != ports.TxScheduled && t.OccurredAt.Before(before) {
+			bal -= debtDelta(t)
+		}
+	}
+	return bal, nil
+}
+func (r *fakeTxRepo) ListFilter(_ int64, f ports.TxFilter, _, _ int) ([]ports.Transaction, int, error) {
+	r.filter = f
+	return r.rows, len(r.rows), nil
+}
+func (r *fakeTxRepo) SummaryByCategoryFilter(_ int64, f ports.TxFilter) ([]ports.CategoryTotal, error) {
+	r.filter = f
+	return r.catTotals, nil
+}
+func (r *fakeTxRepo) Upcoming(int64, time.Time, time.Time) ([]ports.Transaction, error) {
+	return r.rows, nil
+}
+func (r *fakeTxRepo) DueScheduled(now time.Time, limit int) ([]ports.Transaction, error) {
+	var out []ports.Transaction
+	for _, t := range r.rows {
+		if t.Status == ports.TxScheduled && !t.OccurredAt.After(now) && len(out) < limit {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+func (r *fakeTxRepo) PostScheduled(ids []int64) (int64, error) {
+	var n int64
+	for _, id := range ids {
+		if t, _ := r.GetOne(0, id); t != nil && t.Status == ports.TxScheduled {
+			t.Status = ports.TxPending
+			n++
+		}
+	}
+	return n, nil
 }
 func (r *fakeTxRepo) GetSince(int64, time.Time) ([]ports.Transaction, error) {
 	return []ports.Transaction{}, nil
@@ -81,3 +151,124 @@ func TestTx_UpsertBatch_Count(t *testing.T) {
 		t.Fatalf("batch size mismatch")
 	}
 }
+
+// walletACL yalnızca listedeki cüzdanlara yazma izni verir.
+type walletACL map[int64]bool
+
+func (a walletACL) CanWrite(_, walletID int64) error {
+	if !a[walletID] {
+		return errs.Forbidden
+	}
+	return nil
+}
+
+func TestTx_WriteAccessChecksExistingWallet(t *testing.T) {
+	at := time.Now().Add(-time.Hour)
+	txr := &fakeTxRepo{rows: []ports.Transaction{{ID: 5, WalletID: 1, OccurredAt: at}}}
+	// Cüzdan 1 ortak cüzdan; kullanıcı artık yalnızca görüntüleyici.
+	svc := &TxService{Repo: txr, Access: walletACL{2: true}}
+
+	if err := svc.Delete(9, 5); err != errs.Forbidden || txr.deleted != 0 {
+		t.Fatalf("delete in read-only wallet: %v", err)
+	}
+	moved := &ports.Transaction{ID: 5, WalletID: 2, Type: "expense", Amount: 10, OccurredAt: at}
+	if err := svc.Update(9, moved); err != errs.Forbidden || txr.updated != nil {
+		t.Fatalf("move out of read-only wallet: %v", err)
+	}
+	if err := svc.UpsertBatch(9, []ports.Transaction{*moved}); err != errs.Forbidden || txr.batch != 0 {
+		t.Fatalf("batch move out of read-only wallet: %v", err)
+	}
+	if err := svc.UpsertBatch(9, []ports.Transaction{{WalletID: 2, Type: "expense", Amount: 1, OccurredAt: at}}); err != nil {
+		t.Fatalf("new row in writable wallet: %v", err)
+	}
+}
+
+func TestTx_List_RejectsBadQuery(t *testing.T) {
+	svc := &TxService{Repo: &fakeTxRepo{}}
+	if _, _, err := svc.List(1, 1, 20, `wallet:"Kart`); !failedWith(err, "q: pos 8: unterminated quote") {
+		t.Fatalf("want positioned syntax error, got %v", err)
+	}
+	if _, _, err := svc.List(1, 1, 20, `ft:"anything goes`); err != nil {
+		t.Fatalf("fulltext must bypass the parser: %v", err)
+	}
+}
+
+func TestTx_Create_ValidatesTax(t *testing.T) {
+	svc := &TxService{Repo: &fakeTxRepo{}}
+	rate, yes := 20.0, true
+
+	tx := &ports.Transaction{Type: "expense", Amount: 120, Currency: "TRY", TaxRate: &rate, Deductible: &yes}
+	if err := svc.Create(1, tx); err != nil || tx.TaxAmount == nil || *tx.TaxAmount != 20 {
+		t.Fatalf("tax must be derived from gross amount: %v %+v", err, tx.TaxAmount)
+	}
+	wrong := 24.0
+	tx = &ports.Transaction{Type: "expense", Amount: 120, Currency: "TRY", TaxRate: &rate, TaxAmount: &wrong}
+	if err := svc.Create(1, tx); !failedWith(err, "tax_amount_mismatch") {
+		t.Fatalf("want mismatch, got %v", err)
+	}
+	tx = &ports.Transaction{Type: "expense", Amount: 120, Currency: "TRY", TaxAmount: &wrong}
+	if err := svc.Create(1, tx); !failedWith(err, "tax_rate_required") {
+		t.Fatalf("want rate required, got %v", err)
+	}
+	tx = &ports.Transaction{Type: "income", Amount: 120, Currency: "TRY", Deductible: &yes}
+	if err := svc.Create(1, tx); !failedWith(err, "deductible_expense_only") {
+		t.Fatalf("want deductible error, got %v", err)
+	}
+}
+
+func TestTx_Update_KeepsAbsentTaxFields(t *testing.T) {
+	rate, tax, yes := 20.0, 20.0, true
+	txr := &fakeTxRepo{rows: []ports.Transaction{{
+		ID: 5, Type: "expense", Amount: 120, Currency: "TRY", OccurredAt: day(2026, 1, 3),
+		TaxRate: &rate, TaxAmount: &tax, Deductible: &yes,
+	}}}
+	svc := &TxService{Repo: txr}
+
+	// İstemci vergi alanlarını göndermeden tutarı değiştirir.
+	if err := svc.Update(1, &ports.Transaction{ID: 5, Type: "expense", Amount: 240, Currency: "TRY", OccurredAt: day(2026, 1, 3)}); err != nil {
+		t.Fatal(err)
+	}
+	u := txr.updated
+	if u.TaxRate == nil || *u.TaxRate != 20 || u.TaxAmount == nil || *u.TaxAmount != 40 {
+		t.Fatalf("tax must be kept and recomputed: %+v %+v", u.TaxRate, u.TaxAmount)
+	}
+	if u.Deductible == nil || !*u.Deductible {
+		t.Fatalf("deductible must be kept: %+v", u.Deductible)
+	}
+}
+
+func TestTx_ScheduledLifecycle(t *testing.T) {
+	now := day(2026, 10, 19)
+	txr := &fakeTxRepo{rows: []ports.Transaction{
+		{ID: 1, UserID: 7, Status: ports.TxScheduled, OccurredAt: day(2026, 10, 18)},
+		{ID: 2, UserID: 7, Status: ports.TxScheduled, OccurredAt: day(2026, 10, 25)},
+		{ID: 3, UserID: 7, Status: ports.TxPending, OccurredAt: day(2026, 10, 1)},
+	}}
+	svc := &TxService{Repo: txr, Now: func() time.Time { return now }}
+
+	future := &ports.Transaction{Type: "expense", Amount: 10, Currency: "TRY", OccurredAt: day(2026, 11, 1)}
+	if err := svc.Create(7, future); err != nil || future.Status != ports.TxScheduled {
+		t.Fatalf("future-dated tx must default to scheduled: %v %q", err, future.Status)
+	}
+	past := &ports.Transaction{Type: "expense", Amount: 10, Currency: "TRY", OccurredAt: day(2026, 10, 1), Status: ports.TxScheduled}
+	if err := svc.Create(7, past); !failedWith(err, "scheduled_not_in_future") {
+		t.Fatalf("want scheduled_not_in_future, got %v", err)
+	}
+
+	n, err := svc.PostDue()
+	if err != nil || n != 1 {
+		t.Fatalf("want one posted, got %d %v", n, err)
+	}
+	if txr.rows[0].Status != ports.TxPending || txr.rows[1].Status != ports.TxScheduled {
+		t.Fatalf("only due items may be posted: %+v", txr.rows)
+	}
+	if _, err := svc.Upcoming(7, 0); !failedWith(err, "bad_days") {
+		t.Fatalf("want bad_days, got %v", err)
+	}
+
+	// Durum gönderilmeden ileri tarihe alınan kayıt planlanmış olur.
+	moved := &ports.Transaction{ID: 3, Type: "expense", Amount: 10, Currency: "TRY", OccurredAt: day(2026, 12, 1)}
+	if err := svc.Update(7, moved); err != nil || txr.updated.Status != ports.TxScheduled {
+		t.Fatalf("moved into the future must be scheduled: %v %q", err, moved.Status)
+	}
+}
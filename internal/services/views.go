@@ -0,0 +1,218 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/errs"
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+const maxViewIDs = 50
+
+var relRange = regexp.MustCompile(`^last-(\d{1,4})([dm])$`)
+
+// ResolveRange göreli tarih ifadesini now'a göre [from, to) aralığına çevirir.
+// Desteklenenler: today, this-week, this-month, last-month, this-quarter, last-quarter,
+// this-year, last-year, last-<N>d, last-<N>m (bugün dahil). custom için from/to gün olarak verilir.
+func ResolveRange(expr string, from, to *time.Time, now time.Time) (time.Time, time.Time, error) {
+	today := dayOf(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quarter := time.Date(today.Year(), time.Month((int(today.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	year := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+
+	switch expr {
+	case "today":
+		return today, tomorrow, nil
+	case "this-week":
+		start := bucketOf(today, ports.IntervalWeek)
+		return start, start.AddDate(0, 0, 7), nil
+	case "this-month":
+		return month, month.AddDate(0, 1, 0), nil
+	case "last-month":
+		return month.AddDate(0, -1, 0), month, nil
+	case "this-quarter":
+		return quarter, quarter.AddDate(0, 3, 0), nil
+	case "last-quarter":
+		return quarter.AddDate(0, -3, 0), quarter, nil
+	case "this-year":
+		return year, year.AddDate(1, 0, 0), nil
+	case "last-year":
+		return year.AddDate(-1, 0, 0), year, nil
+	case ports.RangeCustom:
+		if from == nil {
+			return time.Time{}, time.Time{}, errs.ValidationFailed("custom_range_requires_from")
+		}
+		end := tomorrow
+		if to != nil {
+			end = dayOf(*to).AddDate(0, 0, 1)
+		}
+		start := dayOf(*from)
+		if !start.Before(end) {
+			return time.Time{}, time.Time{}, errs.ValidationFailed("bad_range")
+		}
+		return start, end, nil
+	}
+	if m := relRange.FindStringSubmatch(expr); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 {
+			return time.Time{}, time.Time{}, errs.ValidationFailed("bad_range")
+		}
+		if m[2] == "d" {
+			return tomorrow.AddDate(0, 0, -n), tomorrow, nil
+		}
+		return tomorrow.AddDate(0, -n, 0), tomorrow, nil
+	}
+	return time.Time{}, time.Time{}, errs.ValidationFailed("bad_range")
+}
+
+type ViewService struct {
+	Repo  ports.ViewRepo
+	Tx    ports.TxRepo
+	Cats  ports.CategoryRepo
+	Audit *AuditService
+
+	Now func() time.Time
+}
+
+func (s *ViewService) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (s *ViewService) normalize(v *ports.SavedView) error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return errs.ValidationFailed("name_required")
+	}
+	v.Range = strings.ToLower(strings.TrimSpace(v.Range))
+	if v.Range == "" {
+		v.Range = "this-month"
+	}
+	if v.Range != ports.RangeCustom {
+		v.From, v.To = nil, nil
+	}
+	if _, _, err := ResolveRange(v.Range, v.From, v.To, s.now()); err != nil {
+		return err
+	}
+	if len(v.WalletIDs) > maxViewIDs || len(v.CategoryIDs) > maxViewIDs {
+		return errs.ValidationFailed("too_many_ids")
+	}
+	v.WalletIDs, v.CategoryIDs = uniqueIDs(v.WalletIDs), uniqueIDs(v.CategoryIDs)
+	v.Query = strings.TrimSpace(v.Query)
+	return checkQuery(v.Query)
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	out := []int64{}
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (s *ViewService) List(uid int64) ([]ports.SavedView, error) { return s.Repo.List(uid) }
+
+func (s *ViewService) Get(uid, id int64) (*ports.SavedView, error) { return s.Repo.Get(uid, id) }
+
+func (s *ViewService) Create(uid int64, v *ports.SavedView) error {
+	if err := s.normalize(v); err != nil {
+		return err
+	}
+	if err := s.Repo.Create(uid, v); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "view.create", "view", &v.ID, map[string]any{"name": v.Name})
+	}
+	return nil
+}
+
+func (s *ViewService) Update(uid int64, v *ports.SavedView) error {
+	if err := s.normalize(v); err != nil {
+		return err
+	}
+	if err := s.Repo.Update(uid, v); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "view.update", "view", &v.ID, map[string]any{"name": v.Name})
+	}
+	return nil
+}
+
+func (s *ViewService) Delete(uid, id int64) error {
+	if err := s.Repo.Delete(uid, id); err != nil {
+		return err
+	}
+	if s.Audit != nil {
+		s.Audit.Log(uid, "view.delete", "view", &id, nil)
+	}
+	return nil
+}
+
+// Filter görünümü bugünün tarihine göre çözülmüş bir TxFilter'a çevirir.
+func (s *ViewService) Filter(uid, id int64) (ports.TxFilter, error) {
+	v, err := s.Repo.Get(uid, id)
+	if err != nil {
+		return ports.TxFilter{}, err
+	}
+	from, to, err := ResolveRange(v.Range, v.From, v.To, s.now())
+	if err != nil {
+		return ports.TxFilter{}, err
+	}
+	return ports.TxFilter{From: from, To: to, WalletIDs: v.WalletIDs, CategoryIDs: v.CategoryIDs, Query: v.Query}, nil
+}
+
+func (s *ViewService) Transactions(uid, id int64, page, size int) ([]ports.Transaction, int, ports.TxFilter, error) {
+	f, err := s.Filter(uid, id)
+	if err != nil {
+		return nil, 0, f, err
+	}
+	rows, total, err := s.Tx.ListFilter(uid, f, page, size)
+	return rows, total, f, err
+}
+
+func (s *ViewService) Summary(uid, id int64) (*ports.ViewSummary, error) {
+	f, err := s.Filter(uid, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Tx.SummaryByCategoryFilter(uid, f)
+	if err != nil {
+		return nil, err
+	}
+	out := &ports.ViewSummary{From: f.From, To: f.To}
+	for _, r := range rows {
+		if r.Type == "income" {
+			out.Income += r.Total
+		} else {
+			out.Expense += r.Total
+		}
+	}
+	out.Income, out.Expense = round2(out.Income), round2(out.Expense)
+	out.Net = round2(out.Income - out.Expense)
+
+	if s.Cats == nil {
+		for i := range rows {
+			rows[i].Own = rows[i].Total
+		}
+		out.ByCategory = rows
+		return out, nil
+	}
+	cats, err := s.Cats.List(uid, "")
+	if err != nil {
+		return nil, err
+	}
+	out.ByCategory = RollupCategoryTotals(cats, rows)
+	return out, nil
+}
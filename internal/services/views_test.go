@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Veysel440/finance-master-api/internal/ports"
+)
+
+type fakeViewRepo struct{ v ports.SavedView }
+
+func (f *fakeViewRepo) List(int64) ([]ports.SavedView, error) { return []ports.SavedView{f.v}, nil }
+func (f *fakeViewRepo) Get(_, id int64) (*ports.SavedView, error) {
+	if id != f.v.ID {
+		return nil, ErrNotFound
+	}
+	v := f.v
+	return &v, nil
+}
+func (f *fakeViewRepo) Create(_ int64, v *ports.SavedView) error { v.ID = 1; f.v = *v; return nil }
+func (f *fakeViewRepo) Update(_ int64, v *ports.SavedView) error { f.v = *v; return nil }
+func (f *fakeViewRepo) Delete(int64, int64) error                { return nil }
+
+func TestResolveRange(t *testing.T) {
+	now := time.Date(2026, 5, 14, 15, 30, 0, 0, time.UTC)
+	d := func(m time.Month, day int) time.Time { return time.Date(2026, m, day, 0, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		expr     string
+		from, to time.Time
+	}{
+		{"this-month", d(5, 1), d(6, 1)},
+		{"last-month", d(4, 1), d(5, 1)},
+		{"this-quarter", d(4, 1), d(7, 1)},
+		{"last-quarter", d(1, 1), d(4, 1)},
+		{"this-week", d(5, 11), d(5, 18)},
+		{"last-90d", d(2, 14), d(5, 15)},
+		{"last-3m", d(2, 15), d(5, 15)},
+	}
+	for _, c := range cases {
+		from, to, err := ResolveRange(c.expr, nil, nil, now)
+		if err != nil || !from.Equal(c.from) || !to.Equal(c.to) {
+			t.Fatalf("%s: got [%s, %s) err=%v", c.expr, from, to, err)
+		}
+	}
+	if _, _, err := ResolveRange("next-week", nil, nil, now); !failedWith(err, "bad_range") {
+		t.Fatalf("want bad_range, got %v", err)
+	}
+	if _, _, err := ResolveRange(ports.RangeCustom, nil, nil, now); !failedWith(err, "custom_range_requires_from") {
+		t.Fatalf("custom without from: %v", err)
+	}
+}
+
+func TestViewSummary_ResolvesAtQueryTime(t *testing.T) {
+	txr := &fakeTxRepo{catTotals: []ports.CategoryTotal{
+		{CategoryID: 1, Type: "expense", Total: 120.5},
+		{CategoryID: 2, Type: "income", Total: 1000},
+	}}
+	now := time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)
+	s := &ViewService{Repo: &fakeViewRepo{}, Tx: txr, Now: func() time.Time { return now }}
+
+	v := ports.SavedView{Name: " Business ", Range: "this-month", WalletIDs: []int64{3, 3, 4}, Query: "category:Food"}
+	if err := s.Create(1, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "Business" || len(v.WalletIDs) != 2 {
+		t.Fatalf("view not normalized: %+v", v)
+	}
+
+	now = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	sum, err := s.Summary(1, v.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !txr.filter.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || txr.filter.Query != "category:Food" {
+		t.Fatalf("filter not resolved at query time: %+v", txr.filter)
+	}
+	if sum.Income != 1000 || sum.Expense != 120.5 || sum.Net != 879.5 {
+		t.Fatalf("bad summary: %+v", sum)
+	}
+
+	bad := ports.SavedView{Name: "x", Query: `note:"open`}
+	if err := s.Create(1, &bad); err == nil {
+		t.Fatal("invalid query must be rejected")
+	}
+}
@@ -0,0 +1,57 @@
+// Package txquery işlem araması için küçük sorgu dilini ayrıştırır ve
+// parametreli SQL koşuluna derler.
+//
+//	category:Food amount>100 wallet:"Kredi Kartı" before:2026-01-01 -note:refund "exact phrase"
+//
+// Terimler boşlukla ayrılır ve hepsi AND ile birleşir; başa konan '-' terimi olumsuzlar.
+// Alan adı olmayan kelimeler ve tırnaklı ifadeler notta aranır.
+package txquery
+
+import "fmt"
+
+const (
+	FieldText     = "" // alansız kelime/ifade: note içinde arama
+	FieldNote     = "note"
+	FieldCategory = "category"
+	FieldWallet   = "wallet"
+	FieldType     = "type"
+	FieldCurrency = "currency"
+	FieldStatus   = "status"
+	FieldAmount   = "amount"
+	FieldBefore   = "before"
+	FieldAfter    = "after"
+	FieldOn       = "on"
+)
+
+const (
+	OpEq  = "="
+	OpGt  = ">"
+	OpGte = ">="
+	OpLt  = "<"
+	OpLte = "<="
+)
+
+// Term tek bir arama koşulu. Pos, terimin sorgudaki 1 tabanlı karakter konumudur.
+type Term struct {
+	Neg   bool
+	Field string
+	Op    string
+	Value string
+	Pos   int
+}
+
+type Query struct {
+	Terms []Term
+}
+
+// SyntaxError konumlu ayrıştırma/derleme hatası.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("pos %d: %s", e.Pos, e.Msg) }
+
+func errAt(pos int, format string, args ...any) error {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
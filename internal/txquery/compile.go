@@ -0,0 +1,102 @@
+package txquery
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	txTypes    = map[string]bool{"income": true, "expense": true}
+	txStatuses = map[string]bool{"scheduled": true, "pending": true, "cleared": true, "reconciled": true}
+)
+
+// Compile terimleri transactions tablosu için AND'lenmiş bir WHERE parçasına çevirir.
+// Değerler her zaman parametre olarak geçer; boş sorguda cond "" döner.
+func Compile(q *Query) (cond string, args []any, err error) {
+	parts := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		c, a, err := compileTerm(t)
+		if err != nil {
+			return "", nil, err
+		}
+		if t.Neg {
+			c = "NOT (" + c + ")"
+		}
+		parts = append(parts, "("+c+")")
+		args = append(args, a...)
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+// Build Parse + Compile kısayolu.
+func Build(s string) (string, []any, error) {
+	q, err := Parse(s)
+	if err != nil {
+		return "", nil, err
+	}
+	return Compile(q)
+}
+
+func compileTerm(t Term) (string, []any, error) {
+	switch t.Field {
+	case FieldText, FieldNote:
+		return `COALESCE(note, '') LIKE ?`, []any{"%" + escapeLike(t.Value) + "%"}, nil
+	case FieldCategory:
+		// Üst kategori adıyla arama tüm alt seviyeleri de kapsar (roll-up ile tutarlı).
+		return `category_id IN (WITH RECURSIVE qsub(id) AS (
+				SELECT id FROM categories WHERE name = ?
+				UNION
+				SELECT qc.id FROM categories qc JOIN qsub ON qc.parent_id = qsub.id)
+			SELECT id FROM qsub)`, []any{t.Value}, nil
+	case FieldWallet:
+		return `EXISTS (SELECT 1 FROM wallets qw WHERE qw.id = transactions.wallet_id AND qw.name = ?)`, []any{t.Value}, nil
+	case FieldType:
+		v := strings.ToLower(t.Value)
+		if !txTypes[v] {
+			return "", nil, errAt(t.Pos, "type must be income or expense")
+		}
+		return `type = ?`, []any{v}, nil
+	case FieldStatus:
+		v := strings.ToLower(t.Value)
+		// posted: gerçekleşmiş, yani planlanmış olmayan her işlem.
+		if v == "posted" {
+			return `status <> ?`, []any{"scheduled"}, nil
+		}
+		if !txStatuses[v] {
+			return "", nil, errAt(t.Pos, "status must be scheduled, posted, pending, cleared or reconciled")
+		}
+		return `status = ?`, []any{v}, nil
+	case FieldCurrency:
+		v := strings.ToUpper(t.Value)
+		if len(v) != 3 {
+			return "", nil, errAt(t.Pos, "currency must be a 3-letter code")
+		}
+		return `currency = ?`, []any{v}, nil
+	case FieldAmount:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(t.Value, ",", "."), 64)
+		if err != nil || n < 0 {
+			return "", nil, errAt(t.Pos, "amount must be a non-negative number")
+		}
+		return `amount ` + t.Op + ` ?`, []any{n}, nil
+	case FieldBefore, FieldAfter, FieldOn:
+		d, err := time.Parse("2006-01-02", t.Value)
+		if err != nil {
+			return "", nil, errAt(t.Pos, "%s expects a date like 2026-01-31", t.Field)
+		}
+		next := d.AddDate(0, 0, 1)
+		switch t.Field {
+		case FieldBefore:
+			return `occurred_at < ?`, []any{d}, nil
+		case FieldAfter:
+			return `occurred_at >= ?`, []any{next}, nil
+		default:
+			return `occurred_at >= ? AND occurred_at < ?`, []any{d, next}, nil
+		}
+	}
+	return "", nil, errAt(t.Pos, "unknown field %q", t.Field)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,163 @@
+package txquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxLen   = 500
+	MaxTerms = 20
+)
+
+var fields = map[string]bool{
+	FieldNote: true, FieldCategory: true, FieldWallet: true, FieldType: true, FieldCurrency: true,
+	FieldStatus: true, FieldAmount: true, FieldBefore: true, FieldAfter: true, FieldOn: true,
+}
+
+type parser struct {
+	src []rune
+	i   int
+}
+
+// Parse sorguyu terimlere ayırır; hatalar *SyntaxError döner.
+func Parse(s string) (*Query, error) {
+	p := &parser{src: []rune(s)}
+	if len(p.src) > MaxLen {
+		return nil, errAt(MaxLen+1, "query longer than %d characters", MaxLen)
+	}
+	q := &Query{}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if len(q.Terms) == MaxTerms {
+			return nil, errAt(t.Pos, "too many terms (max %d)", MaxTerms)
+		}
+		q.Terms = append(q.Terms, t)
+	}
+}
+
+func (p *parser) eof() bool  { return p.i >= len(p.src) }
+func (p *parser) peek() rune { return p.src[p.i] }
+func (p *parser) pos() int   { return p.i + 1 }
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.i++
+	}
+}
+
+func (p *parser) term() (Term, error) {
+	t := Term{Pos: p.pos(), Op: OpEq}
+	if p.peek() == '-' {
+		t.Neg = true
+		p.i++
+		if p.eof() || unicode.IsSpace(p.peek()) {
+			return t, errAt(t.Pos, "'-' must be followed by a term")
+		}
+	}
+	if p.peek() == '"' {
+		v, err := p.quoted()
+		if err != nil {
+			return t, err
+		}
+		t.Value = v
+		return t, p.endOfTerm()
+	}
+
+	// Alan adı: harflerden oluşup ':' ya da karşılaştırma operatörüyle biten önek.
+	// Tanınmayan önekler ("Fatura: Ekim") düz metin olarak aranır.
+	start := p.i
+	for !p.eof() && unicode.IsLetter(p.peek()) {
+		p.i++
+	}
+	name := strings.ToLower(string(p.src[start:p.i]))
+	if fields[name] && !p.eof() && strings.ContainsRune(":<>=", p.peek()) {
+		t.Field = name
+		if p.peek() == ':' {
+			p.i++
+		}
+		t.Op = p.op()
+		if t.Op != OpEq && t.Field != FieldAmount {
+			return t, errAt(start+1, "operator %s is only supported for amount", t.Op)
+		}
+		if p.eof() || unicode.IsSpace(p.peek()) {
+			return t, errAt(p.pos(), "missing value for %s", name)
+		}
+		if p.peek() == '"' {
+			v, err := p.quoted()
+			if err != nil {
+				return t, err
+			}
+			t.Value = v
+			return t, p.endOfTerm()
+		}
+		t.Value = p.word()
+		return t, nil
+	}
+	p.i = start
+	t.Value = p.word()
+	return t, nil
+}
+
+func (p *parser) op() string {
+	for _, op := range []string{OpGte, OpLte, OpGt, OpLt, OpEq} {
+		if p.hasPrefix(op) {
+			p.i += len(op)
+			return op
+		}
+	}
+	return OpEq
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	rs := []rune(s)
+	if p.i+len(rs) > len(p.src) {
+		return false
+	}
+	return string(p.src[p.i:p.i+len(rs)]) == s
+}
+
+func (p *parser) word() string {
+	start := p.i
+	for !p.eof() && !unicode.IsSpace(p.peek()) {
+		p.i++
+	}
+	return string(p.src[start:p.i])
+}
+
+// quoted çift tırnaklı değeri okur; \" ve \\ kaçışlarını çözer.
+func (p *parser) quoted() (string, error) {
+	open := p.pos()
+	p.i++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.i++
+		switch {
+		case c == '\\' && !p.eof() && (p.peek() == '"' || p.peek() == '\\'):
+			b.WriteRune(p.peek())
+			p.i++
+		case c == '"':
+			if b.Len() == 0 {
+				return "", errAt(open, "empty quoted value")
+			}
+			return b.String(), nil
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return "", errAt(open, "unterminated quote")
+}
+
+func (p *parser) endOfTerm() error {
+	if !p.eof() && !unicode.IsSpace(p.peek()) {
+		return errAt(p.pos(), "expected space after quoted value")
+	}
+	return nil
+}
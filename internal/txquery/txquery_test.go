@@ -0,0 +1,93 @@
+package txquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_Terms(t *testing.T) {
+	q, err := Parse(`category:Food amount>100 wallet:"Kredi Kartı" before:2026-01-01 -note:refund "exact phrase"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Term{
+		{Field: FieldCategory, Op: OpEq, Value: "Food", Pos: 1},
+		{Field: FieldAmount, Op: OpGt, Value: "100", Pos: 15},
+		{Field: FieldWallet, Op: OpEq, Value: "Kredi Kartı", Pos: 26},
+		{Field: FieldBefore, Op: OpEq, Value: "2026-01-01", Pos: 47},
+		{Neg: true, Field: FieldNote, Op: OpEq, Value: "refund", Pos: 65},
+		{Field: FieldText, Op: OpEq, Value: "exact phrase", Pos: 78},
+	}
+	if len(q.Terms) != len(want) {
+		t.Fatalf("got %d terms: %+v", len(q.Terms), q.Terms)
+	}
+	for i := range want {
+		if q.Terms[i] != want[i] {
+			t.Fatalf("term %d: got %+v want %+v", i, q.Terms[i], want[i])
+		}
+	}
+}
+
+func TestParse_UnknownPrefixIsText(t *testing.T) {
+	q, err := Parse(`Fatura: Ekim`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Term{
+		{Field: FieldText, Op: OpEq, Value: "Fatura:", Pos: 1},
+		{Field: FieldText, Op: OpEq, Value: "Ekim", Pos: 9},
+	}
+	if len(q.Terms) != len(want) || q.Terms[0] != want[0] || q.Terms[1] != want[1] {
+		t.Fatalf("got %+v", q.Terms)
+	}
+}
+
+func TestCompile_Parameterized(t *testing.T) {
+	cond, args, err := Build(`amount>=50 -type:income 50%`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(amount >= ?) AND (NOT (type = ?)) AND (COALESCE(note, '') LIKE ?)`
+	if cond != want {
+		t.Fatalf("cond:\n got %s\nwant %s", cond, want)
+	}
+	if len(args) != 3 || args[0] != 50.0 || args[1] != "income" || args[2] != `%50\%%` {
+		t.Fatalf("args: %#v", args)
+	}
+
+	cond, args, err = Build(`status:scheduled -status:posted`)
+	if err != nil || cond != `(status = ?) AND (NOT (status <> ?))` || args[0] != "scheduled" || args[1] != "scheduled" {
+		t.Fatalf("status terms: %s %#v %v", cond, args, err)
+	}
+
+	_, args, err = Build(`after:2026-01-31`)
+	if err != nil || !args[0].(time.Time).Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("after must start the next day: %v %v", args, err)
+	}
+}
+
+func TestParse_ErrorsCarryPosition(t *testing.T) {
+	cases := []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{`note:"open`, 6, "unterminated quote"},
+		{`amount>abc`, 1, "amount must be"},
+		{`type>x`, 1, "only supported for amount"},
+		{`food before:`, 13, "missing value"},
+		{`a - b`, 3, "must be followed"},
+	}
+	for _, c := range cases {
+		_, _, err := Build(c.in)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Fatalf("%q: want SyntaxError, got %v", c.in, err)
+		}
+		if se.Pos != c.pos || !strings.Contains(se.Msg, c.msg) {
+			t.Fatalf("%q: got pos %d %q", c.in, se.Pos, se.Msg)
+		}
+	}
+}
@@ -26,6 +26,13 @@ func txType(fl validator.FieldLevel) bool {
 	s, _ := fl.Field().Interface().(string)
 	return s == "income" || s == "expense"
 }
+func walletKind(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "cash", "bank", "credit_card", "savings", "loan":
+		return true
+	}
+	return false
+}
 func isCurrency(fl validator.FieldLevel) bool {
 	s, _ := fl.Field().Interface().(string)
 	return reCurrency.MatchString(s)
@@ -39,6 +46,7 @@ func iso8601(fl validator.FieldLevel) bool {
 func init() {
 	_ = v.RegisterValidation("noctrl", noCtrl)
 	_ = v.RegisterValidation("txtype", txType)
+	_ = v.RegisterValidation("walletkind", walletKind)
 	_ = v.RegisterValidation("currency", isCurrency)
 	_ = v.RegisterValidation("iso8601", iso8601)
 }
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE categories
+    ADD COLUMN parent_id  BIGINT      NULL AFTER user_id,
+    ADD COLUMN sort_order INT         NOT NULL DEFAULT 0 AFTER type,
+    ADD COLUMN icon       VARCHAR(64) NULL AFTER sort_order,
+    ADD COLUMN color      CHAR(7)     NULL AFTER icon,
+    ADD INDEX idx_cat_parent (parent_id),
+    ADD CONSTRAINT fk_cat_parent FOREIGN KEY (parent_id) REFERENCES categories(id)
+        ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- +goose Down
+ALTER TABLE categories
+    DROP FOREIGN KEY fk_cat_parent,
+    DROP INDEX idx_cat_parent,
+    DROP COLUMN color,
+    DROP COLUMN icon,
+    DROP COLUMN sort_order,
+    DROP COLUMN parent_id;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE categories
+    ADD COLUMN archived_at    DATETIME NULL,
+    ADD COLUMN merged_into_id BIGINT   NULL,
+    ADD COLUMN updated_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    ADD INDEX idx_cat_user_updated (user_id, updated_at),
+    ADD CONSTRAINT fk_cat_merged_into FOREIGN KEY (merged_into_id) REFERENCES categories(id)
+        ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- +goose Down
+ALTER TABLE categories
+    DROP FOREIGN KEY fk_cat_merged_into,
+    DROP INDEX idx_cat_user_updated,
+    DROP COLUMN updated_at,
+    DROP COLUMN merged_into_id,
+    DROP COLUMN archived_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE wallets
+    ADD COLUMN kind         ENUM('cash','bank','credit_card','savings','loan') NOT NULL DEFAULT 'cash' AFTER currency,
+    ADD COLUMN credit_limit DECIMAL(14,2) NULL AFTER kind,
+    ADD COLUMN institution  VARCHAR(120)  NULL AFTER credit_limit,
+    ADD COLUMN account_mask VARCHAR(32)   NULL AFTER institution,
+    ADD COLUMN archived_at  DATETIME      NULL,
+    ADD COLUMN updated_at   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    ADD INDEX idx_wallet_user_kind (user_id, kind);
+
+-- +goose Down
+ALTER TABLE wallets
+    DROP INDEX idx_wallet_user_kind,
+    DROP COLUMN updated_at,
+    DROP COLUMN archived_at,
+    DROP COLUMN account_mask,
+    DROP COLUMN institution,
+    DROP COLUMN credit_limit,
+    DROP COLUMN kind;
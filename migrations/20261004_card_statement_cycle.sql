@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE wallets
+    ADD COLUMN statement_day TINYINT UNSIGNED NULL AFTER credit_limit,
+    ADD COLUMN due_day       TINYINT UNSIGNED NULL AFTER statement_day;
+
+-- +goose Down
+ALTER TABLE wallets
+    DROP COLUMN due_day,
+    DROP COLUMN statement_day;
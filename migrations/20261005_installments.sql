@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS installment_plans (
+                                                 id                BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                                 user_id           BIGINT        NOT NULL,
+                                                 wallet_id         BIGINT        NOT NULL,
+                                                 category_id       BIGINT        NOT NULL,
+                                                 total_amount      DECIMAL(14,2) NOT NULL,
+                                                 currency          CHAR(3)       NOT NULL,
+                                                 installment_count SMALLINT      NOT NULL,
+                                                 first_at          DATETIME      NOT NULL,
+                                                 note              VARCHAR(255)  NULL,
+                                                 remainder         ENUM('first','last') NOT NULL DEFAULT 'first',
+                                                 status            ENUM('active','closed') NOT NULL DEFAULT 'active',
+                                                 created_at        DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                 updated_at        DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+                                                 closed_at         DATETIME      NULL,
+                                                 INDEX idx_inst_user (user_id, status),
+                                                 CONSTRAINT fk_inst_user     FOREIGN KEY (user_id)     REFERENCES users(id),
+                                                 CONSTRAINT fk_inst_wallet   FOREIGN KEY (wallet_id)   REFERENCES wallets(id)    ON DELETE RESTRICT ON UPDATE CASCADE,
+                                                 CONSTRAINT fk_inst_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE transactions
+    ADD COLUMN installment_plan_id BIGINT   NULL,
+    ADD COLUMN installment_no      SMALLINT NULL,
+    ADD INDEX idx_tx_installment (installment_plan_id, installment_no),
+    ADD CONSTRAINT fk_tx_installment FOREIGN KEY (installment_plan_id) REFERENCES installment_plans(id)
+        ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- +goose Down
+ALTER TABLE transactions
+    DROP FOREIGN KEY fk_tx_installment,
+    DROP INDEX idx_tx_installment,
+    DROP COLUMN installment_no,
+    DROP COLUMN installment_plan_id;
+
+DROP TABLE IF EXISTS installment_plans;
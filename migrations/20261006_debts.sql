@@ -0,0 +1,55 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS contacts (
+                                        id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                        user_id    BIGINT       NOT NULL,
+                                        name       VARCHAR(120) NOT NULL,
+                                        email      VARCHAR(191) NULL,
+                                        phone      VARCHAR(32)  NULL,
+                                        note       VARCHAR(255) NULL,
+                                        created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                        updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+                                        UNIQUE KEY uniq_contact_user_name (user_id, name),
+                                        CONSTRAINT fk_contact_user FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS debts (
+                                     id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                     user_id    BIGINT        NOT NULL,
+                                     contact_id BIGINT        NOT NULL,
+                                     direction  ENUM('lent','borrowed') NOT NULL,
+                                     amount     DECIMAL(14,2) NOT NULL,
+                                     currency   CHAR(3)       NOT NULL,
+                                     due_at     DATETIME      NULL,
+                                     note       VARCHAR(255)  NULL,
+                                     status     ENUM('open','settled') NOT NULL DEFAULT 'open',
+                                     created_at DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                     updated_at DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+                                     settled_at DATETIME      NULL,
+                                     INDEX idx_debt_user_status_due (user_id, status, due_at),
+                                     INDEX idx_debt_contact (contact_id),
+                                     CONSTRAINT fk_debt_user    FOREIGN KEY (user_id)    REFERENCES users(id),
+                                     CONSTRAINT fk_debt_contact FOREIGN KEY (contact_id) REFERENCES contacts(id)
+                                         ON DELETE RESTRICT ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS debt_payments (
+                                             id             BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                             debt_id        BIGINT        NOT NULL,
+                                             user_id        BIGINT        NOT NULL,
+                                             amount         DECIMAL(14,2) NOT NULL,
+                                             paid_at        DATETIME      NOT NULL,
+                                             transaction_id BIGINT        NULL,
+                                             note           VARCHAR(255)  NULL,
+                                             created_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                             INDEX idx_debtpay_debt (debt_id, paid_at),
+                                             CONSTRAINT fk_debtpay_debt FOREIGN KEY (debt_id) REFERENCES debts(id)
+                                                 ON DELETE CASCADE ON UPDATE CASCADE,
+                                             CONSTRAINT fk_debtpay_user FOREIGN KEY (user_id) REFERENCES users(id),
+                                             CONSTRAINT fk_debtpay_tx   FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+                                                 ON DELETE SET NULL ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS debt_payments;
+DROP TABLE IF EXISTS debts;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,57 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS households (
+                                          id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                          name       VARCHAR(120) NOT NULL,
+                                          created_by BIGINT       NOT NULL,
+                                          created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                          CONSTRAINT fk_household_creator FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS household_members (
+                                                 household_id BIGINT NOT NULL,
+                                                 user_id      BIGINT NOT NULL,
+                                                 role         ENUM('owner','editor','viewer') NOT NULL,
+                                                 joined_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                 PRIMARY KEY (household_id, user_id),
+                                                 INDEX idx_hm_user (user_id),
+                                                 CONSTRAINT fk_hm_household FOREIGN KEY (household_id) REFERENCES households(id)
+                                                     ON DELETE CASCADE,
+                                                 CONSTRAINT fk_hm_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                     ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS household_invites (
+                                                 id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                                 household_id BIGINT       NOT NULL,
+                                                 email        VARCHAR(191) NOT NULL,
+                                                 role         ENUM('editor','viewer') NOT NULL,
+                                                 token_hash   CHAR(64)     NOT NULL,
+                                                 invited_by   BIGINT       NOT NULL,
+                                                 expires_at   DATETIME     NOT NULL,
+                                                 accepted_at  DATETIME     NULL,
+                                                 accepted_by  BIGINT       NULL,
+                                                 created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                 UNIQUE KEY uniq_hinv_token (token_hash),
+                                                 INDEX idx_hinv_household (household_id),
+                                                 CONSTRAINT fk_hinv_household FOREIGN KEY (household_id) REFERENCES households(id)
+                                                     ON DELETE CASCADE,
+                                                 CONSTRAINT fk_hinv_inviter FOREIGN KEY (invited_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE wallets
+    ADD COLUMN household_id BIGINT NULL AFTER user_id,
+    ADD INDEX idx_wallet_household (household_id),
+    ADD CONSTRAINT fk_wallet_household FOREIGN KEY (household_id) REFERENCES households(id);
+
+ALTER TABLE transactions
+    ADD COLUMN created_by BIGINT NULL AFTER user_id,
+    ADD CONSTRAINT fk_tx_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE transactions SET created_by = user_id WHERE created_by IS NULL;
+
+-- +goose Down
+ALTER TABLE transactions DROP FOREIGN KEY fk_tx_created_by, DROP COLUMN created_by;
+ALTER TABLE wallets DROP FOREIGN KEY fk_wallet_household, DROP INDEX idx_wallet_household, DROP COLUMN household_id;
+DROP TABLE IF EXISTS household_invites;
+DROP TABLE IF EXISTS household_members;
+DROP TABLE IF EXISTS households;
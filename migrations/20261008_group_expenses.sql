@@ -0,0 +1,87 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS expense_groups (
+                                              id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                              owner_id   BIGINT       NOT NULL,
+                                              name       VARCHAR(120) NOT NULL,
+                                              currency   CHAR(3)      NOT NULL,
+                                              created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                              CONSTRAINT fk_egroup_owner FOREIGN KEY (owner_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS group_members (
+                                             id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                             group_id   BIGINT       NOT NULL,
+                                             user_id    BIGINT       NULL,
+                                             name       VARCHAR(120) NOT NULL,
+                                             invite_email      VARCHAR(191) NULL,
+                                             invite_token_hash CHAR(64)     NULL,
+                                             invite_expires_at DATETIME     NULL,
+                                             created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                             UNIQUE KEY uniq_gmember_name (group_id, name),
+                                             UNIQUE KEY uniq_gmember_invite (invite_token_hash),
+                                             UNIQUE KEY uniq_gmember_user (group_id, user_id),
+                                             INDEX idx_gmember_user (user_id),
+                                             CONSTRAINT fk_gmember_group FOREIGN KEY (group_id) REFERENCES expense_groups(id)
+                                                 ON DELETE CASCADE,
+                                             CONSTRAINT fk_gmember_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                 ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS group_expenses (
+                                              id             BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                              group_id       BIGINT        NOT NULL,
+                                              paid_by        BIGINT        NOT NULL,
+                                              amount         DECIMAL(14,2) NOT NULL,
+                                              description    VARCHAR(255)  NOT NULL,
+                                              split_mode     ENUM('equal','shares','exact') NOT NULL,
+                                              occurred_at    DATETIME      NOT NULL,
+                                              created_by     BIGINT        NOT NULL,
+                                              transaction_id BIGINT        NULL,
+                                              created_at     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                              INDEX idx_gexp_group (group_id, occurred_at),
+                                              CONSTRAINT fk_gexp_group  FOREIGN KEY (group_id) REFERENCES expense_groups(id)
+                                                  ON DELETE CASCADE,
+                                              CONSTRAINT fk_gexp_payer  FOREIGN KEY (paid_by) REFERENCES group_members(id),
+                                              CONSTRAINT fk_gexp_author FOREIGN KEY (created_by) REFERENCES users(id),
+                                              CONSTRAINT fk_gexp_tx     FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+                                                  ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS group_expense_shares (
+                                                    expense_id BIGINT        NOT NULL,
+                                                    member_id  BIGINT        NOT NULL,
+                                                    amount     DECIMAL(14,2) NOT NULL,
+                                                    PRIMARY KEY (expense_id, member_id),
+                                                    CONSTRAINT fk_gshare_expense FOREIGN KEY (expense_id) REFERENCES group_expenses(id)
+                                                        ON DELETE CASCADE,
+                                                    CONSTRAINT fk_gshare_member FOREIGN KEY (member_id) REFERENCES group_members(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS group_settlements (
+                                                 id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                                 group_id    BIGINT        NOT NULL,
+                                                 from_member BIGINT        NOT NULL,
+                                                 to_member   BIGINT        NOT NULL,
+                                                 amount      DECIMAL(14,2) NOT NULL,
+                                                 settled_at  DATETIME      NOT NULL,
+                                                 from_tx_id  BIGINT        NULL,
+                                                 to_tx_id    BIGINT        NULL,
+                                                 created_by  BIGINT        NOT NULL,
+                                                 created_at  DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                 INDEX idx_gsettle_group (group_id, settled_at),
+                                                 CONSTRAINT fk_gsettle_group FOREIGN KEY (group_id) REFERENCES expense_groups(id)
+                                                     ON DELETE CASCADE,
+                                                 CONSTRAINT fk_gsettle_from  FOREIGN KEY (from_member) REFERENCES group_members(id),
+                                                 CONSTRAINT fk_gsettle_to    FOREIGN KEY (to_member) REFERENCES group_members(id),
+                                                 CONSTRAINT fk_gsettle_ftx   FOREIGN KEY (from_tx_id) REFERENCES transactions(id)
+                                                     ON DELETE SET NULL,
+                                                 CONSTRAINT fk_gsettle_ttx   FOREIGN KEY (to_tx_id) REFERENCES transactions(id)
+                                                     ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS group_settlements;
+DROP TABLE IF EXISTS group_expense_shares;
+DROP TABLE IF EXISTS group_expenses;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS expense_groups;
@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS assets (
+                                      id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                      user_id    BIGINT       NOT NULL,
+                                      symbol     VARCHAR(32)  NOT NULL,
+                                      name       VARCHAR(120) NOT NULL,
+                                      kind       ENUM('stock','fund','gold','crypto','other') NOT NULL,
+                                      unit       VARCHAR(16)  NOT NULL DEFAULT 'unit',
+                                      currency   CHAR(3)      NOT NULL,
+                                      created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                      UNIQUE KEY uniq_asset_user_symbol (user_id, symbol),
+                                      CONSTRAINT fk_asset_user FOREIGN KEY (user_id) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS holding_lots (
+                                            id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                            user_id    BIGINT        NOT NULL,
+                                            wallet_id  BIGINT        NOT NULL,
+                                            asset_id   BIGINT        NOT NULL,
+                                            side       ENUM('buy','sell') NOT NULL,
+                                            quantity   DECIMAL(24,8) NOT NULL,
+                                            price      DECIMAL(20,6) NOT NULL,
+                                            fee        DECIMAL(14,2) NOT NULL DEFAULT 0,
+                                            traded_at  DATETIME      NOT NULL,
+                                            note       VARCHAR(255)  NULL,
+                                            created_at DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                            INDEX idx_lot_user_asset (user_id, asset_id, traded_at),
+                                            INDEX idx_lot_wallet (wallet_id),
+                                            CONSTRAINT fk_lot_user   FOREIGN KEY (user_id)   REFERENCES users(id),
+                                            CONSTRAINT fk_lot_wallet FOREIGN KEY (wallet_id) REFERENCES wallets(id),
+                                            CONSTRAINT fk_lot_asset  FOREIGN KEY (asset_id)  REFERENCES assets(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS asset_prices (
+                                            asset_id   BIGINT        NOT NULL,
+                                            price_date DATE          NOT NULL,
+                                            price      DECIMAL(20,6) NOT NULL,
+                                            source     ENUM('manual','provider') NOT NULL DEFAULT 'manual',
+                                            updated_at DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+                                            PRIMARY KEY (asset_id, price_date),
+                                            CONSTRAINT fk_aprice_asset FOREIGN KEY (asset_id) REFERENCES assets(id)
+                                                ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS asset_prices;
+DROP TABLE IF EXISTS holding_lots;
+DROP TABLE IF EXISTS assets;
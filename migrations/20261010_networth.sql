@@ -0,0 +1,43 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN base_currency CHAR(3) NOT NULL DEFAULT 'TRY';
+
+CREATE TABLE IF NOT EXISTS networth_snapshots (
+                                                  user_id       BIGINT        NOT NULL,
+                                                  snap_date     DATE          NOT NULL,
+                                                  wallet_id     BIGINT        NOT NULL,
+                                                  currency      CHAR(3)       NOT NULL,
+                                                  balance       DECIMAL(14,2) NOT NULL,
+                                                  base_currency CHAR(3)       NOT NULL,
+                                                  value         DECIMAL(14,2) NOT NULL,
+                                                  liability     TINYINT(1)    NOT NULL DEFAULT 0,
+                                                  created_at    DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                  PRIMARY KEY (user_id, snap_date, wallet_id),
+                                                  CONSTRAINT fk_nw_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                      ON DELETE CASCADE,
+                                                  CONSTRAINT fk_nw_wallet FOREIGN KEY (wallet_id) REFERENCES wallets(id)
+                                                      ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Geçmiş tarihli değişiklikler: job since gününden itibaren anlık görüntüleri yeniden hesaplar.
+CREATE TABLE IF NOT EXISTS networth_stale (
+                                              user_id BIGINT NOT NULL PRIMARY KEY,
+                                              since   DATE   NOT NULL,
+                                              CONSTRAINT fk_nws_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                  ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Günlük kur geçmişi: geçmiş görüntüler o günün (yoksa önceki son günün) kuruyla çevrilir.
+CREATE TABLE IF NOT EXISTS fx_history (
+                                          base      CHAR(3)        NOT NULL,
+                                          rate_date DATE           NOT NULL,
+                                          quote     CHAR(3)        NOT NULL,
+                                          rate      DECIMAL(20,10) NOT NULL,
+                                          PRIMARY KEY (base, quote, rate_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS fx_history;
+DROP TABLE IF EXISTS networth_stale;
+DROP TABLE IF EXISTS networth_snapshots;
+ALTER TABLE users DROP COLUMN base_currency;
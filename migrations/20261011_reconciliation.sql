@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reconciliations (
+                                               id              BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                               user_id         BIGINT        NOT NULL,
+                                               wallet_id       BIGINT        NOT NULL,
+                                               statement_date  DATE          NOT NULL,
+                                               ending_balance  DECIMAL(14,2) NOT NULL,
+                                               cleared_balance DECIMAL(14,2) NULL,
+                                               difference      DECIMAL(14,2) NULL,
+                                               status          ENUM('open','locked','unlocked') NOT NULL DEFAULT 'open',
+                                               created_at      DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                               locked_at       DATETIME      NULL,
+                                               INDEX idx_rec_wallet (wallet_id, statement_date),
+                                               CONSTRAINT fk_rec_user   FOREIGN KEY (user_id)   REFERENCES users(id),
+                                               CONSTRAINT fk_rec_wallet FOREIGN KEY (wallet_id) REFERENCES wallets(id)
+                                                   ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE transactions
+    ADD COLUMN status ENUM('pending','cleared','reconciled') NOT NULL DEFAULT 'pending',
+    ADD COLUMN reconciliation_id BIGINT NULL,
+    ADD INDEX idx_tx_wallet_status (wallet_id, status, occurred_at),
+    ADD CONSTRAINT fk_tx_reconciliation FOREIGN KEY (reconciliation_id) REFERENCES reconciliations(id)
+        ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE transactions
+    DROP FOREIGN KEY fk_tx_reconciliation,
+    DROP INDEX idx_tx_wallet_status,
+    DROP COLUMN reconciliation_id,
+    DROP COLUMN status;
+DROP TABLE IF EXISTS reconciliations;
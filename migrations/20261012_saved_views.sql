@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saved_views (
+                                           id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                           user_id      BIGINT       NOT NULL,
+                                           name         VARCHAR(120) NOT NULL,
+                                           date_range   VARCHAR(32)  NOT NULL DEFAULT 'this-month',
+                                           from_date    DATE         NULL,
+                                           to_date      DATE         NULL,
+                                           wallet_ids   JSON         NULL,
+                                           category_ids JSON         NULL,
+                                           query        VARCHAR(500) NOT NULL DEFAULT '',
+                                           created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                           updated_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+                                           UNIQUE KEY uniq_view_user_name (user_id, name),
+                                           CONSTRAINT fk_view_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                               ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS saved_views;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS detected_subscriptions (
+                                                      user_id       BIGINT        NOT NULL,
+                                                      sub_key       VARCHAR(191)  NOT NULL,
+                                                      name          VARCHAR(255)  NOT NULL,
+                                                      currency      CHAR(3)       NOT NULL,
+                                                      cadence       ENUM('weekly','biweekly','monthly','quarterly','yearly') NOT NULL,
+                                                      wallet_id     BIGINT        NULL,
+                                                      category_id   BIGINT        NULL,
+                                                      charges       INT           NOT NULL,
+                                                      avg_amount    DECIMAL(14,2) NOT NULL,
+                                                      last_amount   DECIMAL(14,2) NOT NULL,
+                                                      last_charge   DATETIME      NOT NULL,
+                                                      next_expected DATE          NOT NULL,
+                                                      annualized    DECIMAL(14,2) NOT NULL,
+                                                      status        ENUM('active','lapsed') NOT NULL,
+                                                      prev_amount   DECIMAL(14,2) NULL,
+                                                      changed_at    DATETIME      NULL,
+                                                      PRIMARY KEY (user_id, sub_key),
+                                                      CONSTRAINT fk_dsub_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                          ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Kullanıcı başına son tarama; yalnızca sonrasında işlem değişen kullanıcılar yeniden taranır.
+CREATE TABLE IF NOT EXISTS subscription_scans (
+                                                  user_id    BIGINT   NOT NULL PRIMARY KEY,
+                                                  scanned_at DATETIME NOT NULL,
+                                                  CONSTRAINT fk_sscan_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                      ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_scans;
+DROP TABLE IF EXISTS detected_subscriptions;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS insights (
+                                        id             BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                        user_id        BIGINT       NOT NULL,
+                                        kind           ENUM('category_spike','new_merchant','large_transaction','category_mom','category_yoy') NOT NULL,
+                                        severity       ENUM('info','warning','alert') NOT NULL,
+                                        fingerprint    VARCHAR(191) NOT NULL,
+                                        title          VARCHAR(255) NOT NULL,
+                                        period         DATE         NOT NULL,
+                                        category_id    BIGINT       NULL,
+                                        transaction_id BIGINT       NULL,
+                                        currency       CHAR(3)      NULL,
+                                        data           JSON         NOT NULL,
+                                        created_at     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                        dismissed_at   DATETIME     NULL,
+                                        UNIQUE KEY uniq_insight_fp (user_id, fingerprint),
+                                        INDEX idx_insight_user (user_id, dismissed_at),
+                                        CONSTRAINT fk_insight_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                            ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS insight_scans (
+                                             user_id    BIGINT   NOT NULL PRIMARY KEY,
+                                             scanned_at DATETIME NOT NULL,
+                                             CONSTRAINT fk_iscan_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                 ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS insight_scans;
+DROP TABLE IF EXISTS insights;
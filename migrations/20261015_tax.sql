@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE transactions
+    ADD COLUMN tax_rate   DECIMAL(5,2)  NULL,
+    ADD COLUMN tax_amount DECIMAL(14,2) NULL,
+    ADD COLUMN deductible TINYINT(1)    NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE transactions
+    DROP COLUMN deductible,
+    DROP COLUMN tax_amount,
+    DROP COLUMN tax_rate;
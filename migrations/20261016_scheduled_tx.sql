@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE transactions
+    MODIFY COLUMN status ENUM('scheduled','pending','cleared','reconciled') NOT NULL DEFAULT 'pending',
+    ADD INDEX idx_tx_status_due (status, occurred_at);
+
+-- İleri tarihli mevcut kayıtlar (taksitler vb.) planlanmış sayılır.
+UPDATE transactions SET status='scheduled'
+WHERE occurred_at > NOW() AND status='pending' AND deleted_at IS NULL;
+
+-- +goose Down
+UPDATE transactions SET status='pending' WHERE status='scheduled';
+ALTER TABLE transactions
+    DROP INDEX idx_tx_status_due,
+    MODIFY COLUMN status ENUM('pending','cleared','reconciled') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN locale VARCHAR(8) NOT NULL DEFAULT 'tr';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN locale;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS data_exports (
+                                            id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                            user_id      BIGINT       NOT NULL,
+                                            status       ENUM('queued','running','ready','failed','expired') NOT NULL DEFAULT 'queued',
+                                            file_name    VARCHAR(191) NULL,
+                                            size_bytes   BIGINT       NULL,
+                                            sha256       CHAR(64)     NULL,
+                                            error        VARCHAR(255) NULL,
+                                            attempts     TINYINT      NOT NULL DEFAULT 0,
+                                            created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                            started_at   DATETIME     NULL,
+                                            completed_at DATETIME     NULL,
+                                            expires_at   DATETIME     NULL,
+                                            INDEX idx_export_user (user_id, created_at),
+                                            INDEX idx_export_status (status, expires_at),
+                                            CONSTRAINT fk_export_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN deletion_requested_at DATETIME NULL,
+    ADD COLUMN delete_after          DATETIME NULL,
+    ADD INDEX idx_users_delete_after (delete_after);
+
+-- Silinen hesabın denetim kayıtları anonimleştirilerek korunur.
+ALTER TABLE audit_logs
+    MODIFY user_id BIGINT NULL;
+
+-- +goose Down
+ALTER TABLE users
+    DROP INDEX idx_users_delete_after,
+    DROP COLUMN delete_after,
+    DROP COLUMN deletion_requested_at;
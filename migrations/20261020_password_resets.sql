@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS password_resets (
+                                               id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                               user_id    BIGINT   NOT NULL,
+                                               token_hash CHAR(64) NOT NULL,
+                                               expires_at DATETIME NOT NULL,
+                                               used_at    DATETIME NULL,
+                                               created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                               UNIQUE KEY uniq_preset_token (token_hash),
+                                               INDEX idx_preset_user (user_id, created_at),
+                                               CONSTRAINT fk_preset_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                   ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN verified_at DATETIME NULL;
+
+-- Mevcut hesaplar doğrulanmış sayılır; aksi halde politika yayın anında dışa/içe aktarma
+-- ve davetleri tüm kullanıcılara kapatır.
+UPDATE users SET verified_at = COALESCE(created_at, NOW()) WHERE verified_at IS NULL;
+
+-- E-posta değişikliği: hem eski hem yeni adres onaylanınca users.email güncellenir.
+CREATE TABLE IF NOT EXISTS email_changes (
+                                             id               BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                             user_id          BIGINT       NOT NULL,
+                                             new_email        VARCHAR(191) NOT NULL,
+                                             old_confirmed_at DATETIME     NULL,
+                                             new_confirmed_at DATETIME     NULL,
+                                             completed_at     DATETIME     NULL,
+                                             expires_at       DATETIME     NOT NULL,
+                                             created_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                             INDEX idx_email_change_user (user_id, completed_at),
+                                             CONSTRAINT fk_email_change_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                 ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS email_changes;
+ALTER TABLE users
+    DROP COLUMN verified_at;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Tekrar oynatma koruması: son kabul edilen 30 sn'lik adım; daha eski ya da aynı adım reddedilir.
+ALTER TABLE totp_secrets
+    ADD COLUMN last_step BIGINT NULL;
+
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+                                                   id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+                                                   user_id    BIGINT   NOT NULL,
+                                                   code_hash  CHAR(64) NOT NULL,
+                                                   used_at    DATETIME NULL,
+                                                   created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                                   UNIQUE KEY uniq_recovery_code (user_id, code_hash),
+                                                   CONSTRAINT fk_recovery_user FOREIGN KEY (user_id) REFERENCES users(id)
+                                                       ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- +goose Down
+DROP TABLE IF EXISTS totp_recovery_codes;
+ALTER TABLE totp_secrets
+    DROP COLUMN last_step;
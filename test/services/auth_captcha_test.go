@@ -31,6 +31,22 @@ func (m *memAuthRepo) FindUserByEmail(email string) (*ports.User, error) {
 	}
 	return u, nil
 }
+func (m *memAuthRepo) FindUserByID(id int64) (*ports.User, error) {
+	for _, u := range m.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+func (m *memAuthRepo) SetPassword(userID int64, passHash string) error {
+	u, err := m.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	u.PassHash = passHash
+	return nil
+}
 func (m *memAuthRepo) StoreRefresh(userID int64, refreshHash string, expires time.Time) error {
 	return nil
 }
@@ -44,9 +60,15 @@ func (m *memAuthRepo) RotateRefresh(userID int64, oldHash, newHash string, newEx
 func (m *memAuthRepo) UpsertDevice(userID int64, deviceID, name string, seen time.Time) error {
 	return nil
 }
-func (m *memAuthRepo) GetTotp(userID int64) (*ports.TotpSecret, error) { return nil, nil }
-func (m *memAuthRepo) SetTotp(userID int64, secret string) error       { return nil }
-func (m *memAuthRepo) ConfirmTotp(userID int64) error                  { return nil }
+func (m *memAuthRepo) GetTotp(userID int64) (*ports.TotpSecret, error)          { return nil, nil }
+func (m *memAuthRepo) SetTotp(userID int64, secret string) error                { return nil }
+func (m *memAuthRepo) ConfirmTotp(userID int64) error                           { return nil }
+func (m *memAuthRepo) UseTotpStep(userID, step int64) (bool, error)             { return true, nil }
+func (m *memAuthRepo) ReplaceRecoveryCodes(userID int64, hashes []string) error { return nil }
+func (m *memAuthRepo) UseRecoveryCode(userID int64, hash string, at time.Time) (bool, error) {
+	return false, nil
+}
+func (m *memAuthRepo) DeleteTotp(userID int64) error { return nil }
 
 func (m *memAuthRepo) IncLoginFail(email, ip string, now time.Time, window time.Duration) (int, *time.Time, error) {
 	return 1, nil, nil